@@ -0,0 +1,136 @@
+package alert
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/smtp"
+	"strings"
+	"time"
+)
+
+// SignatureHeader is header of HMAC-SHA256 signature of webhook body, such as "sha256=<hex>"
+const SignatureHeader = "X-Signature"
+
+// Payload is signal notified by alert
+type Payload struct {
+	Symbol   string  `json:"symbol"`
+	Strategy string  `json:"strategy"`
+	Action   string  `json:"action"`
+	Time     int64   `json:"time"`
+	Close    float64 `json:"close"`
+}
+
+// Subject returns one line summary of payload
+func (p *Payload) Subject() string {
+	return fmt.Sprintf("%v %v %v", p.Symbol, p.Strategy, p.Action)
+}
+
+// Sign returns HMAC-SHA256 signature of body by secret
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Webhook posts payload as JSON, when failed by network error or 5xx or 429 status,
+// retries Retries times, waiting Backoff doubled every retry
+type Webhook struct {
+	Client  *http.Client
+	Retries int
+	Backoff time.Duration
+}
+
+// NewWebhook is constructor of Webhook, with 3 retries from 1 second backoff
+func NewWebhook() *Webhook {
+	return &Webhook{Client: &http.Client{Timeout: 10 * time.Second}, Retries: 3, Backoff: time.Second}
+}
+
+// Send posts payload to url, body is signed when secret is not empty,
+// returns number of attempts
+func (wh *Webhook) Send(url, secret string, payload *Payload) (int, error) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return 0, err
+	}
+
+	attempts := 0
+	backoff := wh.Backoff
+	for {
+		attempts++
+		retry, err := wh.post(url, secret, body)
+		if err == nil {
+			return attempts, nil
+		}
+		if !(retry) || attempts > wh.Retries {
+			return attempts, err
+		}
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
+
+// post posts body once, returns whether it should be retried when failed
+func (wh *Webhook) post(url, secret string, body []byte) (bool, error) {
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if secret != "" {
+		req.Header.Set(SignatureHeader, Sign(secret, body))
+	}
+
+	resp, err := wh.Client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return false, nil
+	}
+	retry := resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests
+	return retry, fmt.Errorf("webhook status: %v", resp.StatusCode)
+}
+
+// Mailer sends payload by SMTP, when username is empty, no authentication
+type Mailer struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     string
+}
+
+// Send sends payload to address as plain text mail, returns number of attempts
+func (m *Mailer) Send(to string, payload *Payload) (int, error) {
+	if strings.ContainsAny(to, "\r\n") {
+		return 0, fmt.Errorf("bad address: %q", to)
+	}
+
+	var auth smtp.Auth
+	if m.Username != "" {
+		auth = smtp.PlainAuth("", m.Username, m.Password, m.Host)
+	}
+
+	message := strings.Join([]string{
+		"From: " + m.From,
+		"To: " + to,
+		"Subject: [gostocktrade] " + payload.Subject(),
+		"Content-Type: text/plain; charset=UTF-8",
+		"",
+		fmt.Sprintf("symbol: %v", payload.Symbol),
+		fmt.Sprintf("strategy: %v", payload.Strategy),
+		fmt.Sprintf("action: %v", payload.Action),
+		fmt.Sprintf("date: %v", time.Unix(payload.Time/1000, 0).UTC().Format("2006-01-02")),
+		fmt.Sprintf("close: %v", payload.Close),
+		"",
+	}, "\r\n")
+
+	return 1, smtp.SendMail(fmt.Sprintf("%v:%v", m.Host, m.Port), auth, m.From, []string{to}, []byte(message))
+}
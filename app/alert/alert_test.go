@@ -0,0 +1,144 @@
+package alert_test
+
+import (
+	"bufio"
+	"encoding/json"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/jumpei00/gostocktrade/app/alert"
+	"github.com/stretchr/testify/assert"
+)
+
+var payload = alert.Payload{Symbol: "VOO", Strategy: "ema", Action: "BUY", Time: 1609718400000, Close: 345.1}
+
+func TestWebhookSend(t *testing.T) {
+	assert := assert.New(t)
+
+	// fails twice, then succeeds
+	calls := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		calls++
+		body, _ := ioutil.ReadAll(req.Body)
+		assert.Equal(alert.Sign("secret", body), req.Header.Get(alert.SignatureHeader))
+		assert.Equal("application/json", req.Header.Get("Content-Type"))
+
+		received := alert.Payload{}
+		json.Unmarshal(body, &received)
+		assert.Equal(payload, received)
+
+		if calls < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer ts.Close()
+
+	webhook := alert.NewWebhook()
+	webhook.Backoff = 0
+	attempts, err := webhook.Send(ts.URL, "secret", &payload)
+	assert.Nil(err)
+	assert.Equal(3, attempts)
+
+	// gives up after retries
+	calls = 0
+	webhook.Retries = 1
+	attempts, err = webhook.Send(ts.URL, "secret", &payload)
+	assert.NotNil(err)
+	assert.Equal(2, attempts)
+}
+
+func TestWebhookNoRetry(t *testing.T) {
+	assert := assert.New(t)
+
+	calls := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		calls++
+		// not signed without secret
+		assert.Empty(req.Header.Get(alert.SignatureHeader))
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer ts.Close()
+
+	webhook := alert.NewWebhook()
+	webhook.Backoff = 0
+	attempts, err := webhook.Send(ts.URL, "", &payload)
+	assert.NotNil(err)
+	assert.Equal(1, attempts)
+	assert.Equal(1, calls)
+}
+
+// smtpStandIn accepts one mail without authentication, and sends the data to mails
+func smtpStandIn(t *testing.T, mails chan<- string) (host string, port int) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	go func() {
+		defer listener.Close()
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		reader := bufio.NewReader(conn)
+		reply := func(line string) { conn.Write([]byte(line + "\r\n")) }
+		reply("220 localhost")
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				return
+			}
+			switch command := strings.ToUpper(strings.TrimSpace(line)); {
+			case strings.HasPrefix(command, "EHLO"), strings.HasPrefix(command, "HELO"):
+				reply("250 localhost")
+			case strings.HasPrefix(command, "DATA"):
+				reply("354 start mail input")
+				data := ""
+				for {
+					line, err := reader.ReadString('\n')
+					if err != nil || line == ".\r\n" {
+						break
+					}
+					data += line
+				}
+				mails <- data
+				reply("250 ok")
+			case strings.HasPrefix(command, "QUIT"):
+				reply("221 bye")
+				return
+			default:
+				reply("250 ok")
+			}
+		}
+	}()
+
+	addr := listener.Addr().(*net.TCPAddr)
+	return addr.IP.String(), addr.Port
+}
+
+func TestMailerSend(t *testing.T) {
+	assert := assert.New(t)
+
+	mails := make(chan string, 1)
+	host, port := smtpStandIn(t, mails)
+
+	mailer := alert.Mailer{Host: host, Port: port, From: "alert@example.com"}
+	attempts, err := mailer.Send("user@example.com", &payload)
+	assert.Nil(err)
+	assert.Equal(1, attempts)
+
+	mail := <-mails
+	assert.Contains(mail, "To: user@example.com")
+	assert.Contains(mail, "Subject: [gostocktrade] VOO ema BUY")
+	assert.Contains(mail, "date: 2021-01-04")
+
+	// header injection
+	_, err = mailer.Send("user@example.com\r\nBcc: other@example.com", &payload)
+	assert.NotNil(err)
+}
@@ -0,0 +1,213 @@
+package models
+
+import (
+	"errors"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/jumpei00/gostocktrade/app/alert"
+	"github.com/jumpei00/gostocktrade/app/models/indicator"
+)
+
+// channels of alert
+const (
+	// WEBHOOK posts signed JSON to URL
+	WEBHOOK = "webhook"
+	// EMAIL sends mail by SMTP
+	EMAIL = "email"
+)
+
+// status of alert delivery
+const (
+	SENT   = "sent"
+	FAILED = "failed"
+)
+
+// AlertWebhook delivers alerts of webhook channel
+var AlertWebhook = alert.NewWebhook()
+
+// AlertMailer delivers alerts of email channel, nil means email is not configured
+var AlertMailer *alert.Mailer
+
+// AlertSubscription notifies signals of symbol to target, which is URL for webhook or address for email,
+// empty strategy or action matches any, and secret signs webhook body, which is never returned at json
+type AlertSubscription struct {
+	ID       int    `gorm:"primary_key" json:"id"`
+	Symbol   string `gorm:"index" json:"symbol"`
+	Strategy string `json:"strategy"`
+	Action   string `json:"action"`
+	Channel  string `json:"channel"`
+	Target   string `json:"target"`
+	Secret   string `json:"-"`
+}
+
+// AlertDelivery is log of an alert delivered or failed
+type AlertDelivery struct {
+	ID             int     `gorm:"primary_key" json:"-"`
+	SubscriptionID int     `gorm:"index" json:"subscription_id"`
+	Timestamp      int64   `json:"timestamp"`
+	Symbol         string  `json:"symbol"`
+	Strategy       string  `json:"strategy"`
+	Action         string  `json:"action"`
+	Time           int64   `json:"time"`
+	Close          float64 `json:"close"`
+	Channel        string  `json:"channel"`
+	Target         string  `json:"target"`
+	Attempts       int     `json:"attempts"`
+	Status         string  `json:"status"`
+	Error          string  `json:"error,omitempty"`
+}
+
+// alertSymbol is pattern of symbol, symbol and strategy are put at mail subject
+var alertSymbol = regexp.MustCompile(`^[A-Z0-9.^-]+$`)
+
+// CreateAlertSubscription validates and creates subscription
+func (as *AlertSubscription) CreateAlertSubscription() error {
+	as.Symbol = strings.ToUpper(strings.TrimSpace(as.Symbol))
+	if as.Symbol == "" {
+		return errors.New("alert symbol is empty")
+	}
+	if !alertSymbol.MatchString(as.Symbol) {
+		return errors.New("alert symbol must be letters, digits, '.', '^' or '-'")
+	}
+	as.Strategy = strings.TrimSpace(as.Strategy)
+	if strings.IndexFunc(as.Strategy, unicode.IsControl) >= 0 {
+		return errors.New("alert strategy must not contain control characters")
+	}
+	if as.Action != "" && as.Action != indicator.BUY && as.Action != indicator.SELL {
+		return errors.New("alert action must be BUY or SELL")
+	}
+
+	switch as.Channel {
+	case WEBHOOK:
+		if u, err := url.Parse(as.Target); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return errors.New("alert target must be http(s) URL")
+		}
+	case EMAIL:
+		if !(strings.Contains(as.Target, "@")) || strings.ContainsAny(as.Target, " \r\n") {
+			return errors.New("alert target must be mail address")
+		}
+	default:
+		return errors.New("alert channel must be webhook or email")
+	}
+
+	return DB.Create(as).Error
+}
+
+// GetAlertSubscriptions returns subscriptions of symbol, when symbol is empty, all subscriptions
+func GetAlertSubscriptions(symbol string) []AlertSubscription {
+	subscriptions := []AlertSubscription{}
+	query := DB.Order("id")
+	if symbol != "" {
+		query = query.Where("Symbol = ?", symbol)
+	}
+	query.Find(&subscriptions)
+	return subscriptions
+}
+
+// DeleteAlertSubscription deletes subscription, the delivery log is kept
+func DeleteAlertSubscription(id int) {
+	DB.Delete(AlertSubscription{}, "id = ?", id)
+}
+
+// GetAlertDeliveries returns latest limit deliveries of symbol, when symbol is empty, of all symbols
+func GetAlertDeliveries(symbol string, limit int) []AlertDelivery {
+	deliveries := []AlertDelivery{}
+	query := DB.Order("id desc").Limit(limit)
+	if symbol != "" {
+		query = query.Where("Symbol = ?", symbol)
+	}
+	query.Find(&deliveries)
+	return deliveries
+}
+
+// matches judges whether subscription is for the signal
+func (as *AlertSubscription) matches(signal DigestSignal) bool {
+	return (as.Strategy == "" || as.Strategy == signal.Strategy) && (as.Action == "" || as.Action == signal.Action)
+}
+
+// deliver sends payload through the channel
+func (as *AlertSubscription) deliver(payload *alert.Payload) (int, error) {
+	switch as.Channel {
+	case WEBHOOK:
+		return AlertWebhook.Send(as.Target, as.Secret, payload)
+	case EMAIL:
+		if AlertMailer == nil {
+			return 0, errors.New("smtp is not configured")
+		}
+		return AlertMailer.Send(as.Target, payload)
+	}
+	return 0, errors.New("unknown channel")
+}
+
+// notifyMutex serializes NotifyAlerts, since checking sent deliveries and logging them are not atomic
+var notifyMutex sync.Mutex
+
+// NotifyAlerts delivers signals at the last candle of symbol to matching subscriptions, and logs deliveries,
+// a signal already sent to the subscription is not sent again, so it can be called every time signals are updated,
+// also concurrently from request and scan
+func NotifyAlerts(symbol string) []AlertDelivery {
+	notifyMutex.Lock()
+	defer notifyMutex.Unlock()
+
+	deliveries := []AlertDelivery{}
+
+	subscriptions := GetAlertSubscriptions(symbol)
+	if len(subscriptions) == 0 {
+		return deliveries
+	}
+
+	cframe := GetCandleFrame(symbol, 1)
+	if len(cframe.Candles) == 0 {
+		return deliveries
+	}
+	candle := cframe.Candles[0]
+
+	for _, signal := range todaySignals(symbol) {
+		payload := alert.Payload{Symbol: symbol, Strategy: signal.Strategy, Action: signal.Action, Time: candle.Time, Close: candle.Close}
+
+		for _, as := range subscriptions {
+			if !(as.matches(signal)) {
+				continue
+			}
+
+			var sent int64
+			DB.Model(&AlertDelivery{}).Where("subscription_id = ? AND strategy = ? AND action = ? AND time = ? AND status = ?",
+				as.ID, signal.Strategy, signal.Action, candle.Time, SENT).Count(&sent)
+			if sent != 0 {
+				continue
+			}
+
+			delivery := AlertDelivery{
+				SubscriptionID: as.ID,
+				Timestamp:      time.Now().Unix() * 1000,
+				Symbol:         symbol,
+				Strategy:       signal.Strategy,
+				Action:         signal.Action,
+				Time:           candle.Time,
+				Close:          candle.Close,
+				Channel:        as.Channel,
+				Target:         as.Target,
+				Status:         SENT,
+			}
+			attempts, err := as.deliver(&payload)
+			delivery.Attempts = attempts
+			if err != nil {
+				logrus.Warnf("alert delivery error: %v, %v", as.Target, err)
+				delivery.Status = FAILED
+				delivery.Error = err.Error()
+			}
+
+			DB.Create(&delivery)
+			deliveries = append(deliveries, delivery)
+		}
+	}
+
+	return deliveries
+}
@@ -0,0 +1,113 @@
+package models_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+
+	"github.com/jumpei00/gostocktrade/app/models"
+	"github.com/jumpei00/gostocktrade/app/models/indicator"
+)
+
+func (suite *ModelsTestSuite) TestCreateAlertSubscription() {
+	as := models.AlertSubscription{Symbol: "voo", Action: indicator.BUY, Channel: models.WEBHOOK, Target: "http://localhost/hook"}
+	suite.Nil(as.CreateAlertSubscription())
+	suite.Equal("VOO", as.Symbol)
+	suite.Len(models.GetAlertSubscriptions("VOO"), 1)
+
+	index := models.AlertSubscription{Symbol: "^gspc", Strategy: "rule:golden", Channel: models.WEBHOOK, Target: "http://localhost/hook"}
+	suite.Nil(index.CreateAlertSubscription())
+	suite.Equal("^GSPC", index.Symbol)
+	models.DeleteAlertSubscription(index.ID)
+
+	for _, as := range []models.AlertSubscription{
+		{Channel: models.WEBHOOK, Target: "http://localhost/hook"},
+		{Symbol: "VOO", Action: "HOLD", Channel: models.WEBHOOK, Target: "http://localhost/hook"},
+		{Symbol: "VOO", Channel: models.WEBHOOK, Target: "localhost/hook"},
+		{Symbol: "VOO", Channel: models.EMAIL, Target: "user"},
+		{Symbol: "VOO", Channel: "slack", Target: "http://localhost/hook"},
+		{Symbol: "VOO\r\nBcc: user@example.com", Channel: models.EMAIL, Target: "user@example.com"},
+		{Symbol: "VOO", Strategy: "ema\r\nBcc: user@example.com", Channel: models.EMAIL, Target: "user@example.com"},
+	} {
+		suite.NotNil(as.CreateAlertSubscription())
+	}
+
+	models.DeleteAlertSubscription(as.ID)
+	suite.Empty(models.GetAlertSubscriptions(""))
+}
+
+func (suite *ModelsTestSuite) TestNotifyAlerts() {
+	// EMA signal at the last candle
+	lastTime, _ := models.LastCandleTime("VOO")
+	models.DB.Create(&indicator.EmaSignal{Symbol: "VOO", Time: lastTime, Price: 100, Action: indicator.BUY})
+
+	status := http.StatusOK
+	received := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		received++
+		w.WriteHeader(status)
+	}))
+	defer ts.Close()
+
+	ema := models.AlertSubscription{Symbol: "VOO", Strategy: "ema", Channel: models.WEBHOOK, Target: ts.URL, Secret: "secret"}
+	ema.CreateAlertSubscription()
+	sell := models.AlertSubscription{Symbol: "VOO", Action: indicator.SELL, Channel: models.WEBHOOK, Target: ts.URL}
+	sell.CreateAlertSubscription()
+	mail := models.AlertSubscription{Symbol: "VOO", Channel: models.EMAIL, Target: "user@example.com"}
+	mail.CreateAlertSubscription()
+
+	// webhook fails without retry, email is not configured
+	status = http.StatusBadRequest
+	deliveries := models.NotifyAlerts("VOO")
+	suite.Len(deliveries, 2)
+	for _, delivery := range deliveries {
+		suite.Equal(models.FAILED, delivery.Status)
+		suite.Equal("ema", delivery.Strategy)
+	}
+	suite.Equal(1, received)
+
+	// failed delivery is tried again, but sent one is not
+	status = http.StatusOK
+	deliveries = models.NotifyAlerts("VOO")
+	suite.Equal(models.SENT, deliveries[0].Status)
+	suite.Equal(ema.ID, deliveries[0].SubscriptionID)
+	suite.Len(models.NotifyAlerts("VOO"), 1)
+	suite.Equal(2, received)
+	suite.Len(models.GetAlertDeliveries("VOO", 10), 5)
+
+	for _, as := range models.GetAlertSubscriptions("") {
+		models.DeleteAlertSubscription(as.ID)
+	}
+	models.DeleteBacktestResult("VOO")
+}
+
+func (suite *ModelsTestSuite) TestNotifyAlertsConcurrent() {
+	// BB signal at the last candle
+	lastTime, _ := models.LastCandleTime("VOO")
+	models.DB.Create(&indicator.BBSignal{Symbol: "VOO", Time: lastTime, Price: 100, Action: indicator.SELL})
+
+	var received int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		atomic.AddInt32(&received, 1)
+	}))
+	defer ts.Close()
+
+	bb := models.AlertSubscription{Symbol: "VOO", Strategy: "bb", Channel: models.WEBHOOK, Target: ts.URL}
+	bb.CreateAlertSubscription()
+
+	// request and scan notify at the same time, the signal is sent once
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			models.NotifyAlerts("VOO")
+		}()
+	}
+	wg.Wait()
+	suite.Equal(int32(1), atomic.LoadInt32(&received))
+
+	models.DeleteAlertSubscription(bb.ID)
+	models.DeleteBacktestResult("VOO")
+}
@@ -0,0 +1,126 @@
+package analysis
+
+import (
+	"math"
+)
+
+// Returns returns daily returns of closes, the length is one less than closes
+func Returns(closes []float64) []float64 {
+	returns := []float64{}
+	for i := 1; i < len(closes); i++ {
+		returns = append(returns, closes[i]/closes[i-1]-1)
+	}
+	return returns
+}
+
+// Correlation returns Pearson correlation coefficient of x and y,
+// if either has no variance, returns 0
+func Correlation(x, y []float64) float64 {
+	if len(x) != len(y) || len(x) < 2 {
+		return 0
+	}
+
+	mx, my := mean(x), mean(y)
+	sxy, sxx, syy := 0.0, 0.0, 0.0
+	for i := range x {
+		sxy += (x[i] - mx) * (y[i] - my)
+		sxx += (x[i] - mx) * (x[i] - mx)
+		syy += (y[i] - my) * (y[i] - my)
+	}
+	if sxx == 0 || syy == 0 {
+		return 0
+	}
+	return sxy / math.Sqrt(sxx*syy)
+}
+
+// CorrelationMatrix returns correlation coefficients between each series
+func CorrelationMatrix(series [][]float64) [][]float64 {
+	matrix := make([][]float64, len(series))
+	for i := range series {
+		matrix[i] = make([]float64, len(series))
+		for j := range series {
+			if i == j {
+				matrix[i][j] = 1
+				continue
+			}
+			matrix[i][j] = Correlation(series[i], series[j])
+		}
+	}
+	return matrix
+}
+
+// RollingCorrelation returns correlation of x and y for last window values,
+// the values before window are 0
+func RollingCorrelation(x, y []float64, window int) []float64 {
+	correlation := make([]float64, len(x))
+	if window < 2 || len(x) != len(y) {
+		return correlation
+	}
+	for i := window - 1; i < len(x); i++ {
+		correlation[i] = Correlation(x[i-window+1:i+1], y[i-window+1:i+1])
+	}
+	return correlation
+}
+
+// HedgeRatio returns slope and intercept of least squares regression of y on x,
+// so that y - (ratio * x + intercept) is the spread
+func HedgeRatio(y, x []float64) (ratio, intercept float64) {
+	if len(x) != len(y) || len(x) == 0 {
+		return 0, 0
+	}
+
+	mx, my := mean(x), mean(y)
+	sxy, sxx := 0.0, 0.0
+	for i := range x {
+		sxy += (x[i] - mx) * (y[i] - my)
+		sxx += (x[i] - mx) * (x[i] - mx)
+	}
+	if sxx == 0 {
+		return 0, my
+	}
+
+	ratio = sxy / sxx
+	return ratio, my - ratio*mx
+}
+
+// Spread returns y - (ratio * x + intercept)
+func Spread(y, x []float64, ratio, intercept float64) []float64 {
+	spread := make([]float64, len(y))
+	for i := range y {
+		spread[i] = y[i] - (ratio*x[i] + intercept)
+	}
+	return spread
+}
+
+// ZScore returns how many standard deviations each value is away from the mean of last window values,
+// the values before window are 0
+func ZScore(values []float64, window int) []float64 {
+	zscore := make([]float64, len(values))
+	if window < 2 {
+		return zscore
+	}
+	for i := window - 1; i < len(values); i++ {
+		recent := values[i-window+1 : i+1]
+		if sd := stdev(recent); sd > 0 {
+			zscore[i] = (values[i] - mean(recent)) / sd
+		}
+	}
+	return zscore
+}
+
+// RollingSpread returns hedge ratio regressed for last window values, and z-score of the last spread
+// among spreads of the window calculated by the ratio, so that no future values are used,
+// the values before window are 0
+func RollingSpread(y, x []float64, window int) (ratios, zscores []float64) {
+	ratios, zscores = make([]float64, len(y)), make([]float64, len(y))
+	if window < 2 || len(x) != len(y) {
+		return ratios, zscores
+	}
+	for i := window - 1; i < len(y); i++ {
+		ratio, intercept := HedgeRatio(y[i-window+1:i+1], x[i-window+1:i+1])
+		spread := Spread(y[i-window+1:i+1], x[i-window+1:i+1], ratio, intercept)
+		ratios[i] = ratio
+		zscores[i] = ZScore(spread, window)[window-1]
+	}
+	return ratios, zscores
+}
@@ -0,0 +1,63 @@
+package analysis_test
+
+import (
+	"testing"
+
+	"github.com/jumpei00/gostocktrade/app/models/analysis"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCorrelation(t *testing.T) {
+	assert := assert.New(t)
+
+	x := []float64{1, 2, 3, 4, 5}
+	assert.InDelta(1, analysis.Correlation(x, []float64{2, 4, 6, 8, 10}), 1e-9)
+	assert.InDelta(-1, analysis.Correlation(x, []float64{5, 4, 3, 2, 1}), 1e-9)
+	// no variance
+	assert.Equal(0.0, analysis.Correlation(x, []float64{1, 1, 1, 1, 1}))
+
+	matrix := analysis.CorrelationMatrix([][]float64{x, {5, 4, 3, 2, 1}})
+	assert.Equal(1.0, matrix[0][0])
+	assert.InDelta(-1, matrix[0][1], 1e-9)
+	assert.InDelta(-1, matrix[1][0], 1e-9)
+
+	rolling := analysis.RollingCorrelation(x, []float64{1, 2, 3, 2, 1}, 3)
+	assert.Equal(0.0, rolling[1])
+	assert.InDelta(1, rolling[2], 1e-9)
+	assert.InDelta(-1, rolling[4], 1e-9)
+
+	assert.InDelta(0.1, analysis.Returns([]float64{100, 110})[0], 1e-9)
+}
+
+func TestHedgeRatioAndZScore(t *testing.T) {
+	assert := assert.New(t)
+
+	// y = 2x + 1
+	x := []float64{10, 11, 12, 13, 14}
+	y := []float64{21, 23, 25, 27, 29}
+	ratio, intercept := analysis.HedgeRatio(y, x)
+	assert.InDelta(2, ratio, 1e-9)
+	assert.InDelta(1, intercept, 1e-9)
+	for _, s := range analysis.Spread(y, x, ratio, intercept) {
+		assert.InDelta(0, s, 1e-9)
+	}
+
+	// the last value is 1 standard deviation above the mean of 1, 2, 3
+	zscore := analysis.ZScore([]float64{1, 2, 3}, 3)
+	assert.Equal(0.0, zscore[1])
+	assert.InDelta(1, zscore[2], 1e-9)
+}
+
+func TestRollingSpread(t *testing.T) {
+	assert := assert.New(t)
+
+	// y = 2x until the last value, which jumps above the line
+	x := []float64{10, 12, 11, 13, 12, 14}
+	y := []float64{20, 24, 22, 26, 24, 40}
+	ratios, zscores := analysis.RollingSpread(y, x, 3)
+	assert.Equal(0.0, ratios[1])
+	assert.InDelta(2, ratios[2], 1e-9)
+	assert.InDelta(2, ratios[4], 1e-9)
+	assert.InDelta(0, zscores[4], 1e-9)
+	assert.True(zscores[5] > 0)
+}
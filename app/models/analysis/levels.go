@@ -0,0 +1,222 @@
+package analysis
+
+import (
+	"math"
+	"sort"
+
+	"github.com/jumpei00/gostocktrade/app/models/indicator"
+)
+
+// kinds of level
+const (
+	SUPPORT    = "support"
+	RESISTANCE = "resistance"
+)
+
+// sources of level
+const (
+	// PIVOTLEVEL is level clustered from swing highs and lows
+	PIVOTLEVEL = "pivot"
+	// VOLUMELEVEL is level of price traded heavily
+	VOLUMELEVEL = "volume"
+	// FLOORLEVEL is classic floor pivot calculated by high, low and close of the previous day
+	FLOORLEVEL = "floor"
+)
+
+// Level is horizontal line of support or resistance,
+// strength is from 0 to 1, the higher is the stronger
+type Level struct {
+	Price    float64 `json:"price"`
+	Kind     string  `json:"kind"`
+	Source   string  `json:"source"`
+	Name     string  `json:"name,omitempty"`
+	Strength float64 `json:"strength"`
+	Touches  int     `json:"touches,omitempty"`
+}
+
+// kind returns support if price is under the current price, otherwise resistance
+func kind(price, current float64) string {
+	if price < current {
+		return SUPPORT
+	}
+	return RESISTANCE
+}
+
+// PivotLevels clusters swing highs and lows within tolerance(ratio of price) to levels,
+// the price of level is average of the cluster, and strength is relative number of touches,
+// levels touched only once are not included
+func PivotLevels(high, low, close []float64, width int, tolerance float64) []Level {
+	levels := []Level{}
+	if len(close) == 0 {
+		return levels
+	}
+
+	swingHighs, _ := indicator.SwingPoints(high, width)
+	_, swingLows := indicator.SwingPoints(low, width)
+	prices := []float64{}
+	for _, i := range swingHighs {
+		prices = append(prices, high[i])
+	}
+	for _, i := range swingLows {
+		prices = append(prices, low[i])
+	}
+	sort.Float64s(prices)
+
+	// clusters of near prices, compared with the first price of the cluster
+	clusters := [][]float64{}
+	for _, price := range prices {
+		last := len(clusters) - 1
+		if last >= 0 && price <= clusters[last][0]*(1+tolerance) {
+			clusters[last] = append(clusters[last], price)
+			continue
+		}
+		clusters = append(clusters, []float64{price})
+	}
+
+	maxTouches := 0
+	for _, cluster := range clusters {
+		if len(cluster) > maxTouches {
+			maxTouches = len(cluster)
+		}
+	}
+
+	current := close[len(close)-1]
+	for _, cluster := range clusters {
+		if len(cluster) < 2 {
+			continue
+		}
+		sum := 0.0
+		for _, price := range cluster {
+			sum += price
+		}
+		price := round(sum / float64(len(cluster)))
+		levels = append(levels, Level{
+			Price:    price,
+			Kind:     kind(price, current),
+			Source:   PIVOTLEVEL,
+			Strength: round(float64(len(cluster)) / float64(maxTouches)),
+			Touches:  len(cluster),
+		})
+	}
+
+	return levels
+}
+
+// volumeAtPrice distributes volume of each candle equally to bins between its low and high,
+// returns volume and the lower price of each bin, and the size of bin
+func volumeAtPrice(high, low, volume []float64, bins int) (histogram, prices []float64, size float64) {
+	if len(high) == 0 || bins < 1 {
+		return histogram, prices, size
+	}
+
+	lowest, highest := low[0], high[0]
+	for i := range high {
+		lowest = math.Min(lowest, low[i])
+		highest = math.Max(highest, high[i])
+	}
+
+	histogram = make([]float64, bins)
+	prices = make([]float64, bins)
+	size = (highest - lowest) / float64(bins)
+	for bin := range prices {
+		prices[bin] = lowest + size*float64(bin)
+	}
+	if size == 0 {
+		for i := range volume {
+			histogram[0] += volume[i]
+		}
+		return histogram, prices, size
+	}
+
+	binOf := func(price float64) int {
+		bin := int((price - lowest) / size)
+		if bin >= bins {
+			bin = bins - 1
+		}
+		return bin
+	}
+	for i := range high {
+		from, to := binOf(low[i]), binOf(high[i])
+		for bin := from; bin <= to; bin++ {
+			histogram[bin] += volume[i] / float64(to-from+1)
+		}
+	}
+
+	return histogram, prices, size
+}
+
+// VolumeLevels returns prices of bins which volume is local maximum in volume-at-price histogram,
+// top levels are returned in order of volume, and strength is relative volume to the maximum
+func VolumeLevels(high, low, close, volume []float64, bins, top int) []Level {
+	levels := []Level{}
+	histogram, prices, size := volumeAtPrice(high, low, volume, bins)
+	if len(histogram) == 0 {
+		return levels
+	}
+
+	maxVolume := 0.0
+	for _, v := range histogram {
+		maxVolume = math.Max(maxVolume, v)
+	}
+	if maxVolume == 0 {
+		return levels
+	}
+
+	current := close[len(close)-1]
+	for bin, v := range histogram {
+		if (bin > 0 && histogram[bin-1] >= v) || (bin < len(histogram)-1 && histogram[bin+1] > v) {
+			continue
+		}
+		price := round(prices[bin] + size/2)
+		levels = append(levels, Level{
+			Price:    price,
+			Kind:     kind(price, current),
+			Source:   VOLUMELEVEL,
+			Strength: round(v / maxVolume),
+		})
+	}
+
+	sort.Slice(levels, func(i, j int) bool { return levels[i].Strength > levels[j].Strength })
+	if len(levels) > top {
+		levels = levels[:top]
+	}
+
+	return levels
+}
+
+// FloorPivots returns classic floor pivots calculated by high, low and close of the previous day,
+// pivot is (high + low + close) / 3, and the farther level has less strength
+func FloorPivots(high, low, close float64) []Level {
+	pivot := (high + low + close) / 3
+	prices := []struct {
+		name     string
+		price    float64
+		strength float64
+	}{
+		{"R3", high + 2*(pivot-low), 0.4},
+		{"R2", pivot + (high - low), 0.6},
+		{"R1", 2*pivot - low, 0.8},
+		{"P", pivot, 1},
+		{"S1", 2*pivot - high, 0.8},
+		{"S2", pivot - (high - low), 0.6},
+		{"S3", low - 2*(high-pivot), 0.4},
+	}
+
+	levels := []Level{}
+	for _, p := range prices {
+		levels = append(levels, Level{
+			Price:    round(p.price),
+			Kind:     kind(p.price, close),
+			Source:   FLOORLEVEL,
+			Name:     p.name,
+			Strength: p.strength,
+		})
+	}
+
+	return levels
+}
+
+// round rounds to 2 decimal places
+func round(x float64) float64 {
+	return math.Round(x*100) / 100
+}
@@ -0,0 +1,84 @@
+package analysis_test
+
+import (
+	"testing"
+
+	"github.com/jumpei00/gostocktrade/app/models/analysis"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPivotLevels(t *testing.T) {
+	assert := assert.New(t)
+
+	// swing highs at 110, 110.5 and swing lows at 100, 100.5
+	high := []float64{105, 107, 110, 107, 104, 102, 104, 107, 110.5, 107, 104, 102, 104, 106}
+	low := []float64{103, 105, 108, 105, 102, 100, 102, 105, 108, 105, 102, 100.5, 102, 104}
+	close := []float64{104, 106, 109, 106, 103, 101, 103, 106, 109, 106, 103, 101, 103, 105}
+
+	levels := analysis.PivotLevels(high, low, close, 2, 0.01)
+	assert.Len(levels, 2)
+
+	assert.Equal(100.25, levels[0].Price)
+	assert.Equal(analysis.SUPPORT, levels[0].Kind)
+	assert.Equal(analysis.PIVOTLEVEL, levels[0].Source)
+	assert.Equal(2, levels[0].Touches)
+	assert.Equal(1.0, levels[0].Strength)
+
+	assert.Equal(110.25, levels[1].Price)
+	assert.Equal(analysis.RESISTANCE, levels[1].Kind)
+
+	// when tolerance is too small, each swing point is touched only once
+	assert.Empty(analysis.PivotLevels(high, low, close, 2, 0.001))
+
+	// when empty
+	assert.Empty(analysis.PivotLevels([]float64{}, []float64{}, []float64{}, 2, 0.01))
+}
+
+func TestVolumeLevels(t *testing.T) {
+	assert := assert.New(t)
+
+	// volume is concentrated around 101 and 109, and a little around 105
+	high := []float64{101.5, 101.5, 110, 110, 105.5}
+	low := []float64{100, 100, 108.5, 108.5, 104.5}
+	close := []float64{101, 101, 109, 109, 105}
+	volume := []float64{100, 100, 50, 50, 10}
+
+	levels := analysis.VolumeLevels(high, low, close, volume, 5, 5)
+	assert.Len(levels, 3)
+
+	assert.Equal(101.0, levels[0].Price)
+	assert.Equal(analysis.SUPPORT, levels[0].Kind)
+	assert.Equal(analysis.VOLUMELEVEL, levels[0].Source)
+	assert.Equal(1.0, levels[0].Strength)
+
+	assert.Equal(109.0, levels[1].Price)
+	assert.Equal(analysis.RESISTANCE, levels[1].Kind)
+	assert.Equal(0.5, levels[1].Strength)
+
+	assert.Equal(105.0, levels[2].Price)
+	assert.Equal(0.05, levels[2].Strength)
+
+	// top limits number of levels
+	assert.Len(analysis.VolumeLevels(high, low, close, volume, 5, 1), 1)
+}
+
+func TestFloorPivots(t *testing.T) {
+	assert := assert.New(t)
+
+	levels := analysis.FloorPivots(110, 90, 100)
+	assert.Len(levels, 7)
+
+	prices := map[string]float64{}
+	for _, level := range levels {
+		prices[level.Name] = level.Price
+		assert.Equal(analysis.FLOORLEVEL, level.Source)
+	}
+
+	assert.Equal(100.0, prices["P"])
+	assert.Equal(110.0, prices["R1"])
+	assert.Equal(90.0, prices["S1"])
+	assert.Equal(120.0, prices["R2"])
+	assert.Equal(80.0, prices["S2"])
+	assert.Equal(130.0, prices["R3"])
+	assert.Equal(70.0, prices["S3"])
+}
@@ -0,0 +1,82 @@
+package analysis
+
+// ValueArea is ratio of volume included in value area
+const ValueArea = 0.7
+
+// ProfileBins is default number of bins of volume profile
+const ProfileBins = 24
+
+// ProfileBin is volume traded between low and high
+type ProfileBin struct {
+	Low    float64 `json:"low"`
+	High   float64 `json:"high"`
+	Volume float64 `json:"volume"`
+}
+
+// VolumeProfile is volume-by-price histogram,
+// POC(point of control) is price of the bin traded most,
+// and VAH/VAL is high/low of value area including 70% of volume around POC
+type VolumeProfile struct {
+	Bins []ProfileBin `json:"bins"`
+	POC  float64      `json:"poc"`
+	VAH  float64      `json:"vah"`
+	VAL  float64      `json:"val"`
+}
+
+// NewVolumeProfile is constructor of VolumeProfile, if no candles, returns nil
+func NewVolumeProfile(high, low, volume []float64, bins int) *VolumeProfile {
+	histogram, prices, size := volumeAtPrice(high, low, volume, bins)
+	if len(histogram) == 0 {
+		return nil
+	}
+
+	profile := VolumeProfile{}
+	total, poc := 0.0, 0
+	for bin, v := range histogram {
+		profile.Bins = append(profile.Bins, ProfileBin{Low: round(prices[bin]), High: round(prices[bin] + size), Volume: round(v)})
+		total += v
+		if v > histogram[poc] {
+			poc = bin
+		}
+	}
+
+	// value area is extended from poc to the side which has more volume
+	lower, upper := poc, poc
+	area := histogram[poc]
+	for area < total*ValueArea && (lower > 0 || upper < len(histogram)-1) {
+		if upper == len(histogram)-1 || (lower > 0 && histogram[lower-1] >= histogram[upper+1]) {
+			lower--
+			area += histogram[lower]
+		} else {
+			upper++
+			area += histogram[upper]
+		}
+	}
+
+	profile.POC = round(prices[poc] + size/2)
+	profile.VAH = round(prices[upper] + size)
+	profile.VAL = round(prices[lower])
+
+	return &profile
+}
+
+// AnchoredVwap returns volume weighted average of typical price from anchor to each candle,
+// the values before anchor are 0
+func AnchoredVwap(high, low, close, volume []float64, anchor int) []float64 {
+	vwap := make([]float64, len(close))
+	if anchor < 0 {
+		return vwap
+	}
+
+	amount, sum := 0.0, 0.0
+	for i := anchor; i < len(close); i++ {
+		amount += (high[i] + low[i] + close[i]) / 3 * volume[i]
+		sum += volume[i]
+		if sum == 0 {
+			continue
+		}
+		vwap[i] = amount / sum
+	}
+
+	return vwap
+}
@@ -0,0 +1,43 @@
+package analysis_test
+
+import (
+	"testing"
+
+	"github.com/jumpei00/gostocktrade/app/models/analysis"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVolumeProfile(t *testing.T) {
+	assert := assert.New(t)
+
+	// bins are [100, 102), [102, 104), [104, 106), [106, 108), [108, 110]
+	high := []float64{101, 103, 105, 107, 110}
+	low := []float64{100, 102, 104, 106, 108.5}
+	volume := []float64{10, 30, 100, 20, 40}
+
+	profile := analysis.NewVolumeProfile(high, low, volume, 5)
+	assert.Len(profile.Bins, 5)
+	assert.Equal(analysis.ProfileBin{Low: 104, High: 106, Volume: 100}, profile.Bins[2])
+
+	// from poc, 30 of lower side is added, and then 20 of upper side is added than 10,
+	// 100 + 30 + 20 = 150 >= 200 * 0.7
+	assert.Equal(105.0, profile.POC)
+	assert.Equal(108.0, profile.VAH)
+	assert.Equal(102.0, profile.VAL)
+
+	// when empty
+	assert.Nil(analysis.NewVolumeProfile([]float64{}, []float64{}, []float64{}, 5))
+}
+
+func TestAnchoredVwap(t *testing.T) {
+	assert := assert.New(t)
+
+	high := []float64{11, 12, 13}
+	low := []float64{9, 10, 11}
+	close := []float64{10, 11, 12}
+	volume := []float64{100, 100, 300}
+
+	// typical prices are 10, 11, 12
+	assert.Equal([]float64{10, 10.5, 11.4}, analysis.AnchoredVwap(high, low, close, volume, 0))
+	assert.Equal([]float64{0, 11, 11.75}, analysis.AnchoredVwap(high, low, close, volume, 1))
+}
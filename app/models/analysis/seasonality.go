@@ -0,0 +1,225 @@
+package analysis
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// Significance is level of p-value regarded as significant
+const Significance = 0.05
+
+// SeasonalStat is statistics of returns in a period such as January or Monday,
+// t-stat and p-value(two-sided, Student's t-test) test whether average return differs from that of all periods
+type SeasonalStat struct {
+	Key           int     `json:"key"`
+	Count         int     `json:"count"`
+	AverageReturn float64 `json:"average_return"`
+	HitRate       float64 `json:"hit_rate"`
+	TStat         float64 `json:"t_stat"`
+	PValue        float64 `json:"p_value"`
+	Significant   bool    `json:"significant"`
+}
+
+// Seasonality is statistics of monthly returns by month of year,
+// and daily returns by day of week(1 is Monday) and by trading day of month(1 is the first trading day)
+type Seasonality struct {
+	Months      []SeasonalStat `json:"months"`
+	Weekdays    []SeasonalStat `json:"weekdays"`
+	TradingDays []SeasonalStat `json:"trading_days"`
+	// Latest is keys of periods including the last candle
+	Latest SeasonalKeys `json:"latest"`
+}
+
+// SeasonalKeys is keys of periods including a day
+type SeasonalKeys struct {
+	Month      int `json:"month"`
+	Weekday    int `json:"weekday"`
+	TradingDay int `json:"trading_day"`
+}
+
+// NewSeasonality is constructor of Seasonality, times are unixtime(ms) ordered by ascending,
+// the return of a day is from the close of the previous day, and the return of a month is from the last close of the previous month
+func NewSeasonality(times []int64, closes []float64) *Seasonality {
+	weekdays := map[int][]float64{}
+	tradingDays := map[int][]float64{}
+	months := map[int][]float64{}
+	daily, monthly := []float64{}, []float64{}
+
+	tradingDay := 0
+	monthStart := -1
+	for i := range times {
+		t := time.Unix(times[i]/1000, 0).UTC()
+		if i == 0 || t.Month() != time.Unix(times[i-1]/1000, 0).UTC().Month() {
+			tradingDay = 0
+			// the previous month is completed
+			if monthStart >= 0 {
+				r := closes[i-1]/closes[monthStart] - 1
+				month := int(time.Unix(times[i-1]/1000, 0).UTC().Month())
+				months[month] = append(months[month], r)
+				monthly = append(monthly, r)
+			}
+			if i > 0 {
+				monthStart = i - 1
+			}
+		}
+		tradingDay++
+
+		if i == 0 {
+			continue
+		}
+		r := closes[i]/closes[i-1] - 1
+		weekdays[int(t.Weekday())] = append(weekdays[int(t.Weekday())], r)
+		tradingDays[tradingDay] = append(tradingDays[tradingDay], r)
+		daily = append(daily, r)
+	}
+
+	seasonality := Seasonality{
+		Months:      seasonalStats(months, mean(monthly)),
+		Weekdays:    seasonalStats(weekdays, mean(daily)),
+		TradingDays: seasonalStats(tradingDays, mean(daily)),
+	}
+	if len(times) != 0 {
+		t := time.Unix(times[len(times)-1]/1000, 0).UTC()
+		seasonality.Latest = SeasonalKeys{Month: int(t.Month()), Weekday: int(t.Weekday()), TradingDay: tradingDay}
+	}
+
+	return &seasonality
+}
+
+// WeakPeriods returns names of periods including the last candle,
+// where average return is significantly lower than that of all periods
+func (s *Seasonality) WeakPeriods() []string {
+	weak := []string{}
+	periods := []struct {
+		name  string
+		stats []SeasonalStat
+		key   int
+	}{
+		{"month", s.Months, s.Latest.Month},
+		{"weekday", s.Weekdays, s.Latest.Weekday},
+		{"trading_day", s.TradingDays, s.Latest.TradingDay},
+	}
+	for _, period := range periods {
+		for _, stat := range period.stats {
+			if stat.Key == period.key && stat.Significant && stat.TStat < 0 {
+				weak = append(weak, period.name)
+			}
+		}
+	}
+	return weak
+}
+
+// seasonalStats returns statistics of each period ordered by key
+func seasonalStats(returns map[int][]float64, overall float64) []SeasonalStat {
+	stats := []SeasonalStat{}
+	for key, rs := range returns {
+		stat := SeasonalStat{Key: key, Count: len(rs), AverageReturn: mean(rs), PValue: 1}
+
+		hit := 0
+		for _, r := range rs {
+			if r > 0 {
+				hit++
+			}
+		}
+		stat.HitRate = float64(hit) / float64(len(rs))
+
+		if sd := stdev(rs); sd > 0 {
+			stat.TStat = (stat.AverageReturn - overall) / (sd / math.Sqrt(float64(len(rs))))
+			stat.PValue = pValue(stat.TStat, len(rs)-1)
+		}
+		stat.Significant = stat.PValue < Significance
+
+		stats = append(stats, stat)
+	}
+
+	sort.Slice(stats, func(i, j int) bool { return stats[i].Key < stats[j].Key })
+	return stats
+}
+
+// mean returns average of values, if empty, returns 0
+func mean(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sum := 0.0
+	for _, v := range values {
+		sum += v
+	}
+	return sum / float64(len(values))
+}
+
+// stdev returns sample standard deviation of values, if less than 2 values, returns 0
+func stdev(values []float64) float64 {
+	if len(values) < 2 {
+		return 0
+	}
+	m := mean(values)
+	sum := 0.0
+	for _, v := range values {
+		sum += (v - m) * (v - m)
+	}
+	return math.Sqrt(sum / float64(len(values)-1))
+}
+
+// pValue returns two-sided p-value of t-stat for Student's t-distribution with df degrees of freedom
+func pValue(t float64, df int) float64 {
+	x := float64(df) / (float64(df) + t*t)
+	return incompleteBeta(x, float64(df)/2, 0.5)
+}
+
+// incompleteBeta returns regularized incomplete beta function I_x(a, b)
+func incompleteBeta(x, a, b float64) float64 {
+	if x <= 0 {
+		return 0
+	}
+	if x >= 1 {
+		return 1
+	}
+
+	lgab, _ := math.Lgamma(a + b)
+	lga, _ := math.Lgamma(a)
+	lgb, _ := math.Lgamma(b)
+	front := math.Exp(lgab - lga - lgb + a*math.Log(x) + b*math.Log(1-x))
+
+	// continued fraction converges rapidly when x < (a + 1) / (a + b + 2)
+	if x < (a+1)/(a+b+2) {
+		return front * betaFraction(x, a, b) / a
+	}
+	return 1 - front*betaFraction(1-x, b, a)/b
+}
+
+// betaFraction evaluates continued fraction for incomplete beta function by modified Lentz's method
+func betaFraction(x, a, b float64) float64 {
+	const tiny = 1e-30
+	c, d := 1.0, 1-(a+b)*x/(a+1)
+	if math.Abs(d) < tiny {
+		d = tiny
+	}
+	d = 1 / d
+	h := d
+
+	for m := 1; m <= 200; m++ {
+		fm := float64(m)
+		for _, numerator := range []float64{
+			fm * (b - fm) * x / ((a + 2*fm - 1) * (a + 2*fm)),
+			-(a + fm) * (a + b + fm) * x / ((a + 2*fm) * (a + 2*fm + 1)),
+		} {
+			d = 1 + numerator*d
+			if math.Abs(d) < tiny {
+				d = tiny
+			}
+			c = 1 + numerator/c
+			if math.Abs(c) < tiny {
+				c = tiny
+			}
+			d = 1 / d
+			h *= d * c
+		}
+		if math.Abs(d*c-1) < 1e-12 {
+			break
+		}
+	}
+
+	return h
+}
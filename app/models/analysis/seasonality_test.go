@@ -0,0 +1,85 @@
+package analysis_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jumpei00/gostocktrade/app/models/analysis"
+	"github.com/stretchr/testify/assert"
+)
+
+func unixMilli(year int, month time.Month, day int) int64 {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC).Unix() * 1000
+}
+
+func TestSeasonality(t *testing.T) {
+	assert := assert.New(t)
+
+	times := []int64{
+		unixMilli(2020, 1, 30), unixMilli(2020, 1, 31), // Thu, Fri
+		unixMilli(2020, 2, 3), unixMilli(2020, 2, 4), unixMilli(2020, 2, 28), // Mon, Tue, Fri
+		unixMilli(2020, 3, 2), // Mon
+	}
+	closes := []float64{100, 101, 102, 100, 110, 121}
+
+	seasonality := analysis.NewSeasonality(times, closes)
+
+	// January is not completed from the beginning, March is not completed yet,
+	// so only February from 101 to 110
+	assert.Len(seasonality.Months, 1)
+	assert.Equal(2, seasonality.Months[0].Key)
+	assert.InDelta(110.0/101-1, seasonality.Months[0].AverageReturn, 1e-9)
+	assert.Equal(1.0, seasonality.Months[0].PValue)
+	assert.False(seasonality.Months[0].Significant)
+
+	// Monday, Tuesday, Friday
+	assert.Len(seasonality.Weekdays, 3)
+	assert.Equal(1, seasonality.Weekdays[0].Key)
+	assert.Equal(2, seasonality.Weekdays[0].Count)
+	assert.InDelta((102.0/101-1+121.0/110-1)/2, seasonality.Weekdays[0].AverageReturn, 1e-9)
+	assert.Equal(1.0, seasonality.Weekdays[0].HitRate)
+	assert.Equal(0.0, seasonality.Weekdays[1].HitRate)
+
+	// first, second, third trading day of month
+	assert.Len(seasonality.TradingDays, 3)
+	assert.Equal(2, seasonality.TradingDays[0].Count)
+	assert.Equal(2, seasonality.TradingDays[1].Count)
+
+	assert.Equal(analysis.SeasonalKeys{Month: 3, Weekday: 1, TradingDay: 1}, seasonality.Latest)
+}
+
+func TestWeakPeriods(t *testing.T) {
+	assert := assert.New(t)
+
+	// Monday always falls, and other days rise
+	times := []int64{}
+	closes := []float64{}
+	price := 100.0
+	day := time.Date(2020, 1, 6, 0, 0, 0, 0, time.UTC)
+	for i := 0; len(times) < 500; i++ {
+		if day.Weekday() != time.Saturday && day.Weekday() != time.Sunday {
+			noise := 0.001 * float64(i%3-1)
+			if day.Weekday() == time.Monday {
+				price *= 0.99 + noise
+			} else {
+				price *= 1.005 + noise
+			}
+			times = append(times, day.Unix()*1000)
+			closes = append(closes, price)
+		}
+		day = day.AddDate(0, 0, 1)
+	}
+
+	seasonality := analysis.NewSeasonality(times, closes)
+	monday := seasonality.Weekdays[0]
+	assert.True(monday.Significant)
+	assert.True(monday.TStat < 0)
+	assert.Equal(0.0, monday.HitRate)
+
+	// the last day is Monday
+	for time.Unix(times[len(times)-1]/1000, 0).UTC().Weekday() != time.Monday {
+		times = times[:len(times)-1]
+		closes = closes[:len(closes)-1]
+	}
+	assert.Contains(analysis.NewSeasonality(times, closes).WeakPeriods(), "weekday")
+}
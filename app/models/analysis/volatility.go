@@ -0,0 +1,106 @@
+package analysis
+
+import (
+	"math"
+)
+
+// TradingDays is number of trading days in a year, used for annualizing volatility
+const TradingDays = 252
+
+// CloseToClose returns annualized standard deviation of log returns for last window days,
+// the values before window days are 0
+func CloseToClose(close []float64, window int) []float64 {
+	volatility := make([]float64, len(close))
+	if window < 2 {
+		return volatility
+	}
+
+	for i := window; i < len(close); i++ {
+		returns := make([]float64, window)
+		mean := 0.0
+		for j := range returns {
+			returns[j] = math.Log(close[i-j] / close[i-j-1])
+			mean += returns[j]
+		}
+		mean /= float64(window)
+
+		variance := 0.0
+		for _, r := range returns {
+			variance += (r - mean) * (r - mean)
+		}
+		volatility[i] = math.Sqrt(variance / float64(window-1) * TradingDays)
+	}
+
+	return volatility
+}
+
+// Parkinson returns annualized volatility estimated by high and low for last window days,
+// the values before window days are 0
+func Parkinson(high, low []float64, window int) []float64 {
+	return estimate(len(high), window, func(i int) float64 {
+		hl := math.Log(high[i] / low[i])
+		return hl * hl / (4 * math.Ln2)
+	})
+}
+
+// GarmanKlass returns annualized volatility estimated by open, high, low and close for last window days,
+// the values before window days are 0
+func GarmanKlass(open, high, low, close []float64, window int) []float64 {
+	return estimate(len(close), window, func(i int) float64 {
+		hl := math.Log(high[i] / low[i])
+		co := math.Log(close[i] / open[i])
+		return 0.5*hl*hl - (2*math.Ln2-1)*co*co
+	})
+}
+
+// estimate returns annualized square root of average of daily variance for last window days
+func estimate(length, window int, variance func(i int) float64) []float64 {
+	volatility := make([]float64, length)
+	if window < 1 {
+		return volatility
+	}
+
+	for i := window - 1; i < length; i++ {
+		sum := 0.0
+		for j := i - window + 1; j <= i; j++ {
+			sum += variance(j)
+		}
+		volatility[i] = math.Sqrt(math.Max(sum, 0) / float64(window) * TradingDays)
+	}
+
+	return volatility
+}
+
+// PercentileRank returns percentage of the last lookback values which are less than the value,
+// 0 values are regarded as not calculated, and the rank is 0 until lookback values are calculated
+func PercentileRank(values []float64, lookback int) []float64 {
+	rank := make([]float64, len(values))
+	if lookback < 1 {
+		return rank
+	}
+
+	for i := range values {
+		if values[i] == 0 || i < lookback || values[i-lookback] == 0 {
+			continue
+		}
+		below := 0
+		for j := i - lookback; j < i; j++ {
+			if values[j] < values[i] {
+				below++
+			}
+		}
+		rank[i] = float64(below) / float64(lookback) * 100
+	}
+
+	return rank
+}
+
+// VolatilityScale returns scale of position size so that volatility of the position becomes target,
+// for example, when target is 0.1 and volatility is 0.2, position should be half,
+// if volatility is not calculated, returns 0
+func VolatilityScale(volatility, target float64) float64 {
+	if volatility <= 0 {
+		return 0
+	}
+	return target / volatility
+}
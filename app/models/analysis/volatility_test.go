@@ -0,0 +1,59 @@
+package analysis_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/jumpei00/gostocktrade/app/models/analysis"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCloseToClose(t *testing.T) {
+	assert := assert.New(t)
+
+	// log returns are -ln(1.1) and ln(1.1) alternately, the mean is 0
+	volatility := analysis.CloseToClose([]float64{100, 110, 100, 110}, 2)
+	assert.Equal(0.0, volatility[1])
+	assert.InDelta(math.Log(1.1)*math.Sqrt(2*252), volatility[2], 1e-9)
+	assert.InDelta(math.Log(1.1)*math.Sqrt(2*252), volatility[3], 1e-9)
+
+	// constant price has no volatility
+	assert.Equal([]float64{0, 0, 0}, analysis.CloseToClose([]float64{100, 100, 100}, 2))
+}
+
+func TestParkinsonAndGarmanKlass(t *testing.T) {
+	assert := assert.New(t)
+
+	open := []float64{101, 101, 101}
+	high := []float64{102, 102, 102}
+	low := []float64{100, 100, 100}
+	close := []float64{101, 101, 101}
+	hl := math.Log(1.02)
+
+	parkinson := analysis.Parkinson(high, low, 2)
+	assert.Equal(0.0, parkinson[0])
+	assert.InDelta(hl*math.Sqrt(252/(4*math.Ln2)), parkinson[1], 1e-9)
+
+	// when open equals close, only high-low term is left
+	garmanKlass := analysis.GarmanKlass(open, high, low, close, 2)
+	assert.Equal(0.0, garmanKlass[0])
+	assert.InDelta(hl*math.Sqrt(0.5*252), garmanKlass[2], 1e-9)
+}
+
+func TestPercentileRank(t *testing.T) {
+	assert := assert.New(t)
+
+	rank := analysis.PercentileRank([]float64{0, 1, 3, 2, 5, 4}, 3)
+	// not calculated until 3 values are before
+	assert.Equal([]float64{0, 0, 0, 0}, rank[:4])
+	assert.Equal(100.0, rank[4])
+	assert.InDelta(66.67, rank[5], 0.01)
+}
+
+func TestVolatilityScale(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal(0.5, analysis.VolatilityScale(0.2, 0.1))
+	assert.Equal(2.0, analysis.VolatilityScale(0.05, 0.1))
+	assert.Equal(0.0, analysis.VolatilityScale(0, 0.1))
+}
@@ -1,7 +1,10 @@
 package models
 
 import (
+	"fmt"
 	"math"
+	"reflect"
+	"strings"
 	"time"
 
 	"github.com/sirupsen/logrus"
@@ -11,24 +14,104 @@ import (
 
 // BackTestParam recieves some parameters used for backtest at json
 type BackTestParam struct {
-	Symbol string                        `json:"symbol"`
-	Period int                           `json:"period"`
-	Ema    *indicator.EmaBacktestParam   `json:"ema"`
-	BB     *indicator.BBBacktestParam    `json:"bb"`
-	Macd   *indicator.MacdBacktestParam  `json:"macd"`
-	Rsi    *indicator.RsiBacktestParam   `json:"rsi"`
-	Willr  *indicator.WillrBacktestParam `json:"willr"`
+	Symbol   string                           `json:"symbol"`
+	Period   int                              `json:"period"`
+	Ema      *indicator.EmaBacktestParam      `json:"ema"`
+	BB       *indicator.BBBacktestParam       `json:"bb"`
+	Macd     *indicator.MacdBacktestParam     `json:"macd"`
+	Rsi      *indicator.RsiBacktestParam      `json:"rsi"`
+	Willr    *indicator.WillrBacktestParam    `json:"willr"`
+	Ichimoku *indicator.IchimokuBacktestParam `json:"ichimoku"`
+	Psar     *indicator.PsarBacktestParam     `json:"psar"`
+	Obv      *indicator.ObvBacktestParam      `json:"obv"`
+	Mfi      *indicator.MfiBacktestParam      `json:"mfi"`
+	Donchian *indicator.DonchianBacktestParam `json:"donchian"`
+	Pattern  *indicator.PatternBacktestParam  `json:"pattern"`
+	// PatternFilter allows buy of other strategies only after bullish candlestick pattern, ignored without Pattern
+	PatternFilter bool                               `json:"pattern_filter"`
+	Divergence    *indicator.DivergenceBacktestParam `json:"divergence"`
+	Stoch         *indicator.StochBacktestParam      `json:"stoch"`
+	Level         *indicator.LevelBacktestParam      `json:"level"`
+	// Adx is regime filter, nil means no filter
+	Adx *indicator.AdxBacktestParam `json:"adx,omitempty"`
+	// Confirmations are settings of higher timeframe confirmation for each strategy
+	Confirmations []Confirmation `json:"confirmations,omitempty"`
+	// Sizing is position sizing for performance, nil means one share of each signal
+	Sizing *Sizing `json:"sizing,omitempty"`
+	// EntryOrders are limit or stop orders of buy for each strategy, instead of buying at close
+	EntryOrders []EntryOrder `json:"entry_orders,omitempty"`
+	// TotalReturn is dividends of held trades in performance, credit or reinvest, and empty means price-return
+	TotalReturn string `json:"total_return,omitempty"`
+}
+
+// Validate checks total return and sets defaults of sizing and checks it
+func (bt *BackTestParam) Validate() error {
+	if bt.TotalReturn != "" && bt.TotalReturn != CREDITDIVIDEND && bt.TotalReturn != REINVESTDIVIDEND {
+		return fmt.Errorf("unknown total return: %v", bt.TotalReturn)
+	}
+	if bt.Sizing != nil {
+		if err := bt.Sizing.Validate(); err != nil {
+			return fmt.Errorf("sizing: %v", err)
+		}
+	}
+
+	// strategies not filtered are rejected, rather than ignored at backtest
+	strategies := []string{}
+	if bt.Adx != nil {
+		strategies = append(strategies, bt.Adx.AdxStrategies...)
+	}
+	for _, confirmation := range bt.Confirmations {
+		strategies = append(strategies, confirmation.Strategy)
+	}
+	for _, entryOrder := range bt.EntryOrders {
+		strategies = append(strategies, entryOrder.Strategy)
+	}
+	for _, strategy := range strategies {
+		if _, ok := filteredStrategies[strategy]; !ok {
+			return fmt.Errorf("strategy can not be filtered: %v", strategy)
+		}
+	}
+	return nil
 }
 
 // BackTest excecutes backtest
 // Caution, the Symbol in BackTestParam is the same to ticker symbol of the candle data,
 // if those are different, deal with frontend process
-func (bt *BackTestParam) BackTest() *OptimizedParam {
+func (bt *BackTestParam) BackTest() (*OptimizedParam, error) {
+	if err := bt.Validate(); err != nil {
+		return nil, err
+	}
 	DeleteBacktestResult(bt.Symbol)
 
 	cframe := GetCandleFrame(bt.Symbol, bt.Period)
 	logrus.Infof("backtest start: %v, %v", bt.Symbol, bt.Period)
 
+	cframe.setSizing(bt.Sizing)
+	cframe.setDividends(bt.TotalReturn)
+
+	op := bt.optimize(cframe)
+	if bt.Adx != nil {
+		bt.optimizeAdx(cframe, op)
+	}
+	return op, nil
+}
+
+// optimize optimizes params of all strategies, adx filter is not applied here but by optimizeAdx
+func (bt *BackTestParam) optimize(cframe *CandleFrame) *OptimizedParam {
+	cframe.resetEntryFilters()
+
+	// pattern filter needs params of pattern
+	var bpPattern float64
+	var bpPatternTrend int
+	if bt.Pattern != nil {
+		bpPattern, bpPatternTrend = cframe.optimizePattern(bt.Pattern.PatternTrendLow, bt.Pattern.PatternTrendHigh)
+		if bt.PatternFilter {
+			cframe.setPatternFilter(bpPatternTrend)
+		}
+	}
+	cframe.setConfirmationFilter(bt.Confirmations)
+	cframe.setEntryOrders(bt.EntryOrders)
+
 	bpEma, bpEmaShort, bpEmaLong := cframe.optimizeEma(
 		bt.Ema.EmaShortLow, bt.Ema.EmaShortHigh, bt.Ema.EmaLongLow, bt.Ema.EmaLongHigh)
 	bpBB, bpBBn, bpBBk := cframe.optimizeBB(
@@ -42,77 +125,357 @@ func (bt *BackTestParam) BackTest() *OptimizedParam {
 	bpWillr, bpWillrPeriod, bpWillrBuy, bpWillrSell := cframe.optimizeWillr(
 		bt.Willr.WillrPeriodLow, bt.Willr.WillrPeriodHigh, bt.Willr.WillrBuyThreadLow, bt.Willr.WillrBuyThreadHigh,
 		bt.Willr.WillrSellThreadLow, bt.Willr.WillrSellThreadHigh)
+	// strategies without params are not optimized, and have no signals
+	var bpIchimoku float64
+	var bpIchimokuTenkan, bpIchimokuKijun, bpIchimokuSenkou int
+	if bt.Ichimoku != nil {
+		bpIchimoku, bpIchimokuTenkan, bpIchimokuKijun, bpIchimokuSenkou = cframe.optimizeIchimoku(
+			bt.Ichimoku.IchimokuTenkanLow, bt.Ichimoku.IchimokuTenkanHigh, bt.Ichimoku.IchimokuKijunLow, bt.Ichimoku.IchimokuKijunHigh,
+			bt.Ichimoku.IchimokuSenkouLow, bt.Ichimoku.IchimokuSenkouHigh)
+	}
+	var bpPsar, bpPsarStep, bpPsarMax float64
+	if bt.Psar != nil {
+		bpPsar, bpPsarStep, bpPsarMax = cframe.optimizePsar(
+			bt.Psar.PsarStepLow, bt.Psar.PsarStepHigh, bt.Psar.PsarMaxLow, bt.Psar.PsarMaxHigh)
+	}
+	var bpObv float64
+	var bpObvPeriod int
+	if bt.Obv != nil {
+		bpObv, bpObvPeriod = cframe.optimizeObv(bt.Obv.ObvPeriodLow, bt.Obv.ObvPeriodHigh)
+	}
+	var bpMfi, bpMfiBuy, bpMfiSell float64
+	var bpMfiPeriod int
+	if bt.Mfi != nil {
+		bpMfi, bpMfiPeriod, bpMfiBuy, bpMfiSell = cframe.optimizeMfi(
+			bt.Mfi.MfiPeriodLow, bt.Mfi.MfiPeriodHigh, bt.Mfi.MfiBuyThreadLow, bt.Mfi.MfiBuyThreadHigh,
+			bt.Mfi.MfiSellThreadLow, bt.Mfi.MfiSellThreadHigh)
+	}
+	var bpDonchian float64
+	var bpDonchianEntry, bpDonchianExit, bpDonchianAtr int
+	if bt.Donchian != nil {
+		bpDonchian, bpDonchianEntry, bpDonchianExit, bpDonchianAtr = cframe.optimizeDonchian(
+			bt.Donchian.DonchianEntryLow, bt.Donchian.DonchianEntryHigh, bt.Donchian.DonchianExitLow, bt.Donchian.DonchianExitHigh,
+			bt.Donchian.DonchianAtrLow, bt.Donchian.DonchianAtrHigh)
+	}
+	var bpDivergence float64
+	var bpDivergenceWidth int
+	var bpDivergenceSource string
+	if bt.Divergence != nil {
+		bpDivergence, bpDivergenceWidth, bpDivergenceSource = cframe.optimizeDivergence(
+			bt.Divergence.DivergenceWidthLow, bt.Divergence.DivergenceWidthHigh)
+	}
+	var bpStoch, bpStochBuy, bpStochSell float64
+	var bpStochFast bool
+	var bpStochK, bpStochD, bpStochSlowing int
+	if bt.Stoch != nil {
+		bpStoch, bpStochFast, bpStochK, bpStochD, bpStochSlowing, bpStochBuy, bpStochSell = cframe.optimizeStoch(
+			bt.Stoch.StochKLow, bt.Stoch.StochKHigh, bt.Stoch.StochDLow, bt.Stoch.StochDHigh, bt.Stoch.StochSlowingLow, bt.Stoch.StochSlowingHigh,
+			bt.Stoch.StochBuyThreadLow, bt.Stoch.StochBuyThreadHigh, bt.Stoch.StochSellThreadLow, bt.Stoch.StochSellThreadHigh)
+	}
+	var bpLevel float64
+	var bpLevelLookback int
+	if bt.Level != nil {
+		bpLevel, bpLevelLookback = cframe.optimizeLevel(bt.Level.LevelLookbackLow, bt.Level.LevelLookbackHigh)
+	}
 
 	op := OptimizedParam{
-		Timestamp:        time.Now().Unix() * 1000,
-		Symbol:           bt.Symbol,
-		EmaPerformance:   math.Round(bpEma*100) / 100,
-		EmaShort:         bpEmaShort,
-		EmaLong:          bpEmaLong,
-		BBPerformance:    math.Round(bpBB*100) / 100,
-		BBn:              bpBBn,
-		BBk:              math.Round(bpBBk*10) / 10,
-		MacdPerformance:  math.Round(bpMacd*100) / 100,
-		MacdFast:         bpMacdFast,
-		MacdSlow:         bpMacdSlow,
-		MacdSignal:       bpMacdSignal,
-		RsiPerformance:   math.Round(bpRsi*100) / 100,
-		RsiPeriod:        bpRsiPeriod,
-		RsiBuyThread:     bpRsiBuy,
-		RsiSellThread:    bpRsiSell,
-		WillrPerformance: math.Round(bpWillr*100) / 100,
-		WillrPeriod:      bpWillrPeriod,
-		WillrBuyThread:   bpWillrBuy,
-		WillrSellThread:  bpWillrSell,
-		EmaSignals:       cframe.backtestEma(1, bpEmaShort, bpEmaLong, nil).EmaSignals,
-		BBSignals:        cframe.backtestBB(1, bpBBn, bpBBk, nil).BBSignals,
-		MacdSignals:      cframe.backtestMacd(1, bpMacdFast, bpMacdSlow, bpMacdSignal, nil).MacdSignals,
-		RsiSignals:       cframe.backtestRsi(1, bpRsiPeriod, bpRsiBuy, bpRsiSell, nil).RsiSignals,
-		WillrSignals:     cframe.backtestWillr(1, bpWillrPeriod, bpWillrBuy, bpWillrSell, nil).WillrSignals,
+		Timestamp:             time.Now().Unix() * 1000,
+		Symbol:                bt.Symbol,
+		EmaPerformance:        math.Round(bpEma*100) / 100,
+		EmaShort:              bpEmaShort,
+		EmaLong:               bpEmaLong,
+		BBPerformance:         math.Round(bpBB*100) / 100,
+		BBn:                   bpBBn,
+		BBk:                   math.Round(bpBBk*10) / 10,
+		MacdPerformance:       math.Round(bpMacd*100) / 100,
+		MacdFast:              bpMacdFast,
+		MacdSlow:              bpMacdSlow,
+		MacdSignal:            bpMacdSignal,
+		RsiPerformance:        math.Round(bpRsi*100) / 100,
+		RsiPeriod:             bpRsiPeriod,
+		RsiBuyThread:          bpRsiBuy,
+		RsiSellThread:         bpRsiSell,
+		WillrPerformance:      math.Round(bpWillr*100) / 100,
+		WillrPeriod:           bpWillrPeriod,
+		WillrBuyThread:        bpWillrBuy,
+		WillrSellThread:       bpWillrSell,
+		IchimokuPerformance:   math.Round(bpIchimoku*100) / 100,
+		IchimokuTenkan:        bpIchimokuTenkan,
+		IchimokuKijun:         bpIchimokuKijun,
+		IchimokuSenkou:        bpIchimokuSenkou,
+		PsarPerformance:       math.Round(bpPsar*100) / 100,
+		PsarStep:              math.Round(bpPsarStep*100) / 100,
+		PsarMax:               math.Round(bpPsarMax*100) / 100,
+		ObvPerformance:        math.Round(bpObv*100) / 100,
+		ObvPeriod:             bpObvPeriod,
+		MfiPerformance:        math.Round(bpMfi*100) / 100,
+		MfiPeriod:             bpMfiPeriod,
+		MfiBuyThread:          bpMfiBuy,
+		MfiSellThread:         bpMfiSell,
+		DonchianPerformance:   math.Round(bpDonchian*100) / 100,
+		DonchianEntry:         bpDonchianEntry,
+		DonchianExit:          bpDonchianExit,
+		DonchianAtr:           bpDonchianAtr,
+		PatternPerformance:    math.Round(bpPattern*100) / 100,
+		PatternTrend:          bpPatternTrend,
+		PatternFilter:         bt.PatternFilter && bt.Pattern != nil,
+		DivergencePerformance: math.Round(bpDivergence*100) / 100,
+		DivergenceWidth:       bpDivergenceWidth,
+		DivergenceSource:      bpDivergenceSource,
+		StochPerformance:      math.Round(bpStoch*100) / 100,
+		StochFast:             bpStochFast,
+		StochK:                bpStochK,
+		StochD:                bpStochD,
+		StochSlowing:          bpStochSlowing,
+		StochBuyThread:        bpStochBuy,
+		StochSellThread:       bpStochSell,
+		LevelPerformance:      math.Round(bpLevel*100) / 100,
+		LevelLookback:         bpLevelLookback,
+		EmaSignals:            cframe.backtestEma(1, bpEmaShort, bpEmaLong, nil).EmaSignals,
+		BBSignals:             cframe.backtestBB(1, bpBBn, bpBBk, nil).BBSignals,
+		MacdSignals:           cframe.backtestMacd(1, bpMacdFast, bpMacdSlow, bpMacdSignal, nil).MacdSignals,
+		RsiSignals:            cframe.backtestRsi(1, bpRsiPeriod, bpRsiBuy, bpRsiSell, nil).RsiSignals,
+		WillrSignals:          cframe.backtestWillr(1, bpWillrPeriod, bpWillrBuy, bpWillrSell, nil).WillrSignals,
+	}
+
+	if bt.Ichimoku != nil {
+		op.IchimokuSignals = cframe.backtestIchimoku(1, bpIchimokuTenkan, bpIchimokuKijun, bpIchimokuSenkou, nil).IchimokuSignals
+	}
+	if bt.Psar != nil {
+		op.PsarSignals = cframe.backtestPsar(1, bpPsarStep, bpPsarMax, nil).PsarSignals
+	}
+	if bt.Obv != nil {
+		op.ObvSignals = cframe.backtestObv(1, bpObvPeriod, nil).ObvSignals
+	}
+	if bt.Mfi != nil {
+		op.MfiSignals = cframe.backtestMfi(1, bpMfiPeriod, bpMfiBuy, bpMfiSell, nil).MfiSignals
+	}
+	if bt.Donchian != nil {
+		op.DonchianSignals = cframe.backtestDonchian(1, bpDonchianEntry, bpDonchianExit, bpDonchianAtr, nil).DonchianSignals
+	}
+	if bt.Pattern != nil {
+		op.PatternSignals = cframe.backtestPattern(1, bpPatternTrend, nil).PatternSignals
+	}
+	if bt.Divergence != nil {
+		op.DivergenceSignals = cframe.backtestDivergence(1, bpDivergenceWidth, bpDivergenceSource, nil).DivergenceSignals
+	}
+	if bt.Stoch != nil {
+		op.StochSignals = cframe.backtestStoch(1, bpStochFast, bpStochK, bpStochD, bpStochSlowing, bpStochBuy, bpStochSell, nil).StochSignals
+	}
+	if bt.Level != nil {
+		op.LevelSignals = cframe.backtestLevel(1, bpLevelLookback, nil).LevelSignals
+	}
+
+	if bt.Sizing != nil {
+		op.Sizing = *bt.Sizing
+	}
+	op.TotalReturn = cframe.dividendMode
+	for _, confirmation := range bt.Confirmations {
+		op.Confirmations = append(op.Confirmations, Confirmation{
+			Symbol: bt.Symbol, Strategy: confirmation.Strategy, Timeframe: confirmation.Timeframe, Indicator: confirmation.Indicator})
+	}
+	for _, entryOrder := range bt.EntryOrders {
+		// invalid ones are not set to cframe
+		entryOrder, ok := cframe.entryOrders[entryOrder.Strategy]
+		if !ok {
+			continue
+		}
+		op.EntryOrders = append(op.EntryOrders, EntryOrder{
+			Symbol: bt.Symbol, Strategy: entryOrder.Strategy, Type: entryOrder.Type, Reference: entryOrder.Reference, Offset: entryOrder.Offset})
 	}
 
 	return &op
 }
 
+// optimizeAdx optimizes adx threshold by total performance of filtered strategies with the optimized params,
+// and replaces performance and signals of them with the ones filtered by the best threshold
+func (bt *BackTestParam) optimizeAdx(cframe *CandleFrame, op *OptimizedParam) {
+	op.AdxPeriod = bt.Adx.AdxPeriod
+	op.AdxDI = bt.Adx.AdxDI
+	op.AdxStrategies = strings.Join(bt.Adx.AdxStrategies, ",")
+
+	strategies := bt.Adx.AdxStrategies
+	if len(strategies) == 0 {
+		for strategy := range filteredStrategies {
+			strategies = append(strategies, strategy)
+		}
+	}
+
+	bestPerformance := 0.0
+	bestThread := bt.Adx.AdxThreadLow
+	for thread := bt.Adx.AdxThreadLow; thread <= bt.Adx.AdxThreadHigh; thread++ {
+		op.AdxThread = thread
+		cframe.setEntryFilters(op)
+
+		performance := 0.0
+		for _, strategy := range strategies {
+			if signals := bt.filteredSignals(cframe, op, strategy); signals != nil {
+				performance += cframe.profit(signals)
+			}
+		}
+		if thread == bt.Adx.AdxThreadLow || bestPerformance < performance {
+			bestPerformance = performance
+			bestThread = thread
+		}
+	}
+
+	op.AdxThread = bestThread
+	cframe.setEntryFilters(op)
+	rv := reflect.ValueOf(op).Elem()
+	for _, strategy := range strategies {
+		signals := bt.filteredSignals(cframe, op, strategy)
+		if signals == nil {
+			continue
+		}
+		name := filteredStrategies[strategy]
+		rv.FieldByName(name + "Performance").SetFloat(math.Round(cframe.profit(signals)*100) / 100)
+		rv.FieldByName(name + "Signals").Set(reflect.ValueOf(signals).Elem().FieldByName(name + "Signals"))
+	}
+
+	logrus.Infof("adx filter: results -> %v, %v", bestPerformance, bestThread)
+}
+
+// filteredSignals backtests strategy with the optimized params,
+// returns nil if the strategy is unknown, not optimized, or has no signals
+func (bt *BackTestParam) filteredSignals(cframe *CandleFrame, op *OptimizedParam, strategy string) interface{ Profit() float64 } {
+	var signals interface{ Profit() float64 }
+	switch {
+	case strategy == "ema":
+		signals = cframe.backtestEma(1, op.EmaShort, op.EmaLong, nil)
+	case strategy == "bb":
+		signals = cframe.backtestBB(1, op.BBn, op.BBk, nil)
+	case strategy == "macd":
+		signals = cframe.backtestMacd(1, op.MacdFast, op.MacdSlow, op.MacdSignal, nil)
+	case strategy == "rsi":
+		signals = cframe.backtestRsi(1, op.RsiPeriod, op.RsiBuyThread, op.RsiSellThread, nil)
+	case strategy == "willr":
+		signals = cframe.backtestWillr(1, op.WillrPeriod, op.WillrBuyThread, op.WillrSellThread, nil)
+	case strategy == "ichimoku" && bt.Ichimoku != nil:
+		signals = cframe.backtestIchimoku(1, op.IchimokuTenkan, op.IchimokuKijun, op.IchimokuSenkou, nil)
+	case strategy == "obv" && bt.Obv != nil:
+		signals = cframe.backtestObv(1, op.ObvPeriod, nil)
+	case strategy == "mfi" && bt.Mfi != nil:
+		signals = cframe.backtestMfi(1, op.MfiPeriod, op.MfiBuyThread, op.MfiSellThread, nil)
+	case strategy == "donchian" && bt.Donchian != nil:
+		signals = cframe.backtestDonchian(1, op.DonchianEntry, op.DonchianExit, op.DonchianAtr, nil)
+	case strategy == "divergence" && bt.Divergence != nil:
+		signals = cframe.backtestDivergence(1, op.DivergenceWidth, op.DivergenceSource, nil)
+	case strategy == "stoch" && bt.Stoch != nil:
+		signals = cframe.backtestStoch(1, op.StochFast, op.StochK, op.StochD, op.StochSlowing, op.StochBuyThread, op.StochSellThread, nil)
+	case strategy == "level" && bt.Level != nil:
+		signals = cframe.backtestLevel(1, op.LevelLookback, nil)
+	case strategy == "pattern" && bt.Pattern != nil:
+		signals = cframe.backtestPattern(1, op.PatternTrend, nil)
+	default:
+		return nil
+	}
+
+	// backtest returns nil pointer when params are over candles
+	if reflect.ValueOf(signals).IsNil() {
+		return nil
+	}
+	return signals
+}
+
 // OptimizedParam is stored to optimized parameter for backtest,
 // also has relationships a part of signal results of backtest.
 type OptimizedParam struct {
-	ID               int                     `gorm:"primary_key" json:"-"`
-	Timestamp        int64                   `json:"timestamp"`
-	Symbol           string                  `json:"symbol"`
-	EmaPerformance   float64                 `json:"ema_performance"`
-	EmaShort         int                     `json:"ema_short"`
-	EmaLong          int                     `json:"ema_long"`
-	BBPerformance    float64                 `json:"bb_performance"`
-	BBn              int                     `json:"bb_n"`
-	BBk              float64                 `json:"bb_k"`
-	MacdPerformance  float64                 `json:"macd_performance"`
-	MacdFast         int                     `json:"macd_fast"`
-	MacdSlow         int                     `json:"macd_slow"`
-	MacdSignal       int                     `json:"macd_signal"`
-	RsiPerformance   float64                 `json:"rsi_performance"`
-	RsiPeriod        int                     `json:"rsi_period"`
-	RsiBuyThread     float64                 `json:"rsi_buythread"`
-	RsiSellThread    float64                 `json:"rsi_sellthread"`
-	WillrPerformance float64                 `json:"willr_performance"`
-	WillrPeriod      int                     `json:"willr_period"`
-	WillrBuyThread   float64                 `json:"willr_buythread"`
-	WillrSellThread  float64                 `json:"willr_sellthread"`
-	EmaSignals       []indicator.EmaSignal   `gorm:"foreignKey:Symbol;references:Symbol" json:"-"`
-	BBSignals        []indicator.BBSignal    `gorm:"foreignKey:Symbol;references:Symbol" json:"-"`
-	MacdSignals      []indicator.MacdSignal  `gorm:"foreignKey:Symbol;references:Symbol" json:"-"`
-	RsiSignals       []indicator.RsiSignal   `gorm:"foreignKey:Symbol;references:Symbol" json:"-"`
-	WillrSignals     []indicator.WillrSignal `gorm:"foreignKey:Symbol;references:Symbol" json:"-"`
+	ID                    int                          `gorm:"primary_key" json:"-"`
+	Timestamp             int64                        `json:"timestamp"`
+	Symbol                string                       `json:"symbol"`
+	EmaPerformance        float64                      `json:"ema_performance"`
+	EmaShort              int                          `json:"ema_short"`
+	EmaLong               int                          `json:"ema_long"`
+	BBPerformance         float64                      `json:"bb_performance"`
+	BBn                   int                          `json:"bb_n"`
+	BBk                   float64                      `json:"bb_k"`
+	MacdPerformance       float64                      `json:"macd_performance"`
+	MacdFast              int                          `json:"macd_fast"`
+	MacdSlow              int                          `json:"macd_slow"`
+	MacdSignal            int                          `json:"macd_signal"`
+	RsiPerformance        float64                      `json:"rsi_performance"`
+	RsiPeriod             int                          `json:"rsi_period"`
+	RsiBuyThread          float64                      `json:"rsi_buythread"`
+	RsiSellThread         float64                      `json:"rsi_sellthread"`
+	WillrPerformance      float64                      `json:"willr_performance"`
+	WillrPeriod           int                          `json:"willr_period"`
+	WillrBuyThread        float64                      `json:"willr_buythread"`
+	WillrSellThread       float64                      `json:"willr_sellthread"`
+	IchimokuPerformance   float64                      `json:"ichimoku_performance"`
+	IchimokuTenkan        int                          `json:"ichimoku_tenkan"`
+	IchimokuKijun         int                          `json:"ichimoku_kijun"`
+	IchimokuSenkou        int                          `json:"ichimoku_senkou"`
+	PsarPerformance       float64                      `json:"psar_performance"`
+	PsarStep              float64                      `json:"psar_step"`
+	PsarMax               float64                      `json:"psar_max"`
+	ObvPerformance        float64                      `json:"obv_performance"`
+	ObvPeriod             int                          `json:"obv_period"`
+	MfiPerformance        float64                      `json:"mfi_performance"`
+	MfiPeriod             int                          `json:"mfi_period"`
+	MfiBuyThread          float64                      `json:"mfi_buythread"`
+	MfiSellThread         float64                      `json:"mfi_sellthread"`
+	DonchianPerformance   float64                      `json:"donchian_performance"`
+	DonchianEntry         int                          `json:"donchian_entry"`
+	DonchianExit          int                          `json:"donchian_exit"`
+	DonchianAtr           int                          `json:"donchian_atr"`
+	PatternPerformance    float64                      `json:"pattern_performance"`
+	PatternTrend          int                          `json:"pattern_trend"`
+	PatternFilter         bool                         `json:"pattern_filter"`
+	DivergencePerformance float64                      `json:"divergence_performance"`
+	DivergenceWidth       int                          `json:"divergence_width"`
+	DivergenceSource      string                       `json:"divergence_source"`
+	StochPerformance      float64                      `json:"stoch_performance"`
+	StochFast             bool                         `json:"stoch_fast"`
+	StochK                int                          `json:"stoch_k"`
+	StochD                int                          `json:"stoch_d"`
+	StochSlowing          int                          `json:"stoch_slowing"`
+	StochBuyThread        float64                      `json:"stoch_buythread"`
+	StochSellThread       float64                      `json:"stoch_sellthread"`
+	LevelPerformance      float64                      `json:"level_performance"`
+	LevelLookback         int                          `json:"level_lookback"`
+	AdxPeriod             int                          `json:"adx_period"`
+	AdxThread             float64                      `json:"adx_thread"`
+	AdxDI                 bool                         `json:"adx_di"`
+	AdxStrategies         string                       `json:"adx_strategies"`
+	Sizing                Sizing                       `gorm:"embedded;embeddedPrefix:sizing_" json:"sizing"`
+	TotalReturn           string                       `json:"total_return"`
+	Confirmations         []Confirmation               `gorm:"foreignKey:Symbol;references:Symbol" json:"confirmations"`
+	EntryOrders           []EntryOrder                 `gorm:"foreignKey:Symbol;references:Symbol" json:"entry_orders"`
+	EmaSignals            []indicator.EmaSignal        `gorm:"foreignKey:Symbol;references:Symbol" json:"-"`
+	BBSignals             []indicator.BBSignal         `gorm:"foreignKey:Symbol;references:Symbol" json:"-"`
+	MacdSignals           []indicator.MacdSignal       `gorm:"foreignKey:Symbol;references:Symbol" json:"-"`
+	RsiSignals            []indicator.RsiSignal        `gorm:"foreignKey:Symbol;references:Symbol" json:"-"`
+	WillrSignals          []indicator.WillrSignal      `gorm:"foreignKey:Symbol;references:Symbol" json:"-"`
+	IchimokuSignals       []indicator.IchimokuSignal   `gorm:"foreignKey:Symbol;references:Symbol" json:"-"`
+	PsarSignals           []indicator.PsarSignal       `gorm:"foreignKey:Symbol;references:Symbol" json:"-"`
+	ObvSignals            []indicator.ObvSignal        `gorm:"foreignKey:Symbol;references:Symbol" json:"-"`
+	MfiSignals            []indicator.MfiSignal        `gorm:"foreignKey:Symbol;references:Symbol" json:"-"`
+	DonchianSignals       []indicator.DonchianSignal   `gorm:"foreignKey:Symbol;references:Symbol" json:"-"`
+	PatternSignals        []indicator.PatternSignal    `gorm:"foreignKey:Symbol;references:Symbol" json:"-"`
+	DivergenceSignals     []indicator.DivergenceSignal `gorm:"foreignKey:Symbol;references:Symbol" json:"-"`
+	StochSignals          []indicator.StochSignal      `gorm:"foreignKey:Symbol;references:Symbol" json:"-"`
+	LevelSignals          []indicator.LevelSignal      `gorm:"foreignKey:Symbol;references:Symbol" json:"-"`
 }
 
 // DeleteBacktestResult deletes all exiting data for symbol
 func DeleteBacktestResult(symbol string) {
-	DB.Delete(OptimizedParam{}, "Symbol LIKE ?", "%"+symbol+"%")
-	DB.Delete(indicator.EmaSignal{}, "Symbol LIKE ?", "%"+symbol+"%")
-	DB.Delete(indicator.BBSignal{}, "Symbol LIKE ?", "%"+symbol+"%")
-	DB.Delete(indicator.MacdSignal{}, "Symbol LIKE ?", "%"+symbol+"%")
-	DB.Delete(indicator.RsiSignal{}, "Symbol LIKE ?", "%"+symbol+"%")
-	DB.Delete(indicator.WillrSignal{}, "Symbol LIKE ?", "%"+symbol+"%")
+	DB.Delete(OptimizedParam{}, "Symbol = ?", symbol)
+	DB.Delete(indicator.EmaSignal{}, "Symbol = ?", symbol)
+	DB.Delete(indicator.BBSignal{}, "Symbol = ?", symbol)
+	DB.Delete(indicator.MacdSignal{}, "Symbol = ?", symbol)
+	DB.Delete(indicator.RsiSignal{}, "Symbol = ?", symbol)
+	DB.Delete(indicator.WillrSignal{}, "Symbol = ?", symbol)
+	DB.Delete(indicator.IchimokuSignal{}, "Symbol = ?", symbol)
+	DB.Delete(indicator.PsarSignal{}, "Symbol = ?", symbol)
+	DB.Delete(indicator.ObvSignal{}, "Symbol = ?", symbol)
+	DB.Delete(indicator.MfiSignal{}, "Symbol = ?", symbol)
+	DB.Delete(indicator.DonchianSignal{}, "Symbol = ?", symbol)
+	DB.Delete(indicator.PatternSignal{}, "Symbol = ?", symbol)
+	DB.Delete(indicator.DivergenceSignal{}, "Symbol = ?", symbol)
+	DB.Delete(indicator.StochSignal{}, "Symbol = ?", symbol)
+	DB.Delete(Confirmation{}, "Symbol = ?", symbol)
+	DB.Delete(EntryOrder{}, "Symbol = ?", symbol)
+	DB.Delete(indicator.LevelSignal{}, "Symbol = ?", symbol)
 }
 
 // GetOptimizedParamFrame returns OptimizedParamFrame including OptimizedParam for symbol
@@ -120,7 +483,7 @@ func GetOptimizedParamFrame(symbol string) *OptimizedParamFrame {
 	var op OptimizedParam
 	var opframe OptimizedParamFrame
 
-	err := DB.First(&op, OptimizedParam{Symbol: symbol})
+	err := DB.Preload("Confirmations").Preload("EntryOrders").First(&op, OptimizedParam{Symbol: symbol})
 	if err.Error != nil {
 		// Not Found
 		opframe.Param = nil
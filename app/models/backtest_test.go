@@ -2,6 +2,8 @@ package models_test
 
 import (
 	"github.com/jumpei00/gostocktrade/app/models"
+	"github.com/jumpei00/gostocktrade/app/models/indicator"
+	"github.com/markcheno/go-talib"
 )
 
 func (suite *ModelsTestSuite) TestCreateBacktestResult() {
@@ -20,7 +22,122 @@ func (suite *ModelsTestSuite) TestGetOptimizedParamFrame() {
 	opframe = models.GetOptimizedParamFrame("TEST")
 	suite.Nil(opframe.Param)
 
+	// symbol is matched exactly, V is not VOO
+	models.DeleteBacktestResult("V")
+	opframe = models.GetOptimizedParamFrame("VOO")
+	suite.NotNil(opframe.Param)
+	suite.NotEmpty(models.GetSignalFrame("VOO", true, false, false, false, false, false, false, false, false, false, false, false, false, false).Signals.EmaSignals)
+
 	models.DeleteBacktestResult("VOO")
 	opframe = models.GetOptimizedParamFrame("VOO")
 	suite.Nil(opframe.Param)
 }
+
+func (suite *ModelsTestSuite) TestPatternFilter() {
+	filtered := backTestParam
+	filtered.PatternFilter = true
+	op, _ := filtered.BackTest()
+	suite.True(op.PatternFilter)
+
+	// buy is only at the day of bullish pattern or the day after
+	cframe := models.GetCandleFrame("VOO", 500)
+	allowed := map[int64]bool{}
+	for _, pattern := range indicator.Patterns(cframe.Opens(), cframe.Highs(), cframe.Lows(), cframe.Closes(), op.PatternTrend) {
+		if pattern.Direction != indicator.BULLISH {
+			continue
+		}
+		allowed[cframe.Candles[pattern.Index].Time] = true
+		if pattern.Index+1 < len(cframe.Candles) {
+			allowed[cframe.Candles[pattern.Index+1].Time] = true
+		}
+	}
+	for _, signal := range op.EmaSignals {
+		if signal.Action == indicator.BUY {
+			suite.True(allowed[signal.Time])
+		}
+	}
+
+	models.DeleteBacktestResult("VOO")
+}
+
+func (suite *ModelsTestSuite) TestAdxFilter() {
+	filtered := backTestParam
+	filtered.Adx = &indicator.AdxBacktestParam{
+		AdxPeriod:     14,
+		AdxThreadLow:  20,
+		AdxThreadHigh: 21,
+		AdxStrategies: []string{"ema", "macd"},
+	}
+	op, _ := filtered.BackTest()
+	suite.Equal(14, op.AdxPeriod)
+	suite.True(op.AdxThread == 20 || op.AdxThread == 21)
+	suite.Equal("ema,macd", op.AdxStrategies)
+
+	// params are optimized without filter, only threshold is swept
+	suite.Equal(suite.Op.EmaShort, op.EmaShort)
+	suite.Equal(suite.Op.EmaLong, op.EmaLong)
+	suite.Equal(suite.Op.MacdFast, op.MacdFast)
+	suite.Equal(suite.Op.BBPerformance, op.BBPerformance)
+	suite.Equal(suite.Op.BBSignals, op.BBSignals)
+
+	// buy of ema is only in trending market
+	cframe := models.GetCandleFrame("VOO", 500)
+	adx := talib.Adx(cframe.Highs(), cframe.Lows(), cframe.Closes(), 14)
+	trending := map[int64]bool{}
+	for i, candle := range cframe.Candles {
+		trending[candle.Time] = adx[i] >= op.AdxThread
+	}
+	for _, signal := range op.EmaSignals {
+		if signal.Action == indicator.BUY {
+			suite.True(trending[signal.Time])
+		}
+	}
+
+	// filter state is reported with signals
+	op.CreateBacktestResult()
+	filter := models.GetSignalFrame("VOO", true, false, false, false, false, false, false, false, false, false, false, false, false, false).Filter
+	suite.Equal(op.AdxThread, filter.AdxThread)
+	suite.Equal([]string{"ema", "macd"}, filter.AdxStrategies)
+
+	models.DeleteBacktestResult("VOO")
+	suite.Nil(models.GetEntryFilter("VOO"))
+}
+
+func (suite *ModelsTestSuite) TestAdxFilterStrategies() {
+	// psar is always in the market, and can not be filtered
+	filtered := backTestParam
+	filtered.Adx = &indicator.AdxBacktestParam{AdxPeriod: 14, AdxThreadLow: 20, AdxThreadHigh: 20, AdxStrategies: []string{"psar"}}
+	_, err := filtered.BackTest()
+	suite.NotNil(err)
+
+	// pattern and custom strategies are filtered
+	filtered.Adx.AdxStrategies = []string{"pattern", "rule"}
+	op, err := filtered.BackTest()
+	suite.Nil(err)
+	suite.Nil(op.CreateBacktestResult())
+
+	rs := models.RuleStrategy{Name: "golden", Rule: goldenCross}
+	rs.SaveRuleStrategy()
+	result, err := (&models.RuleBacktestParam{Name: "golden", Symbol: "VOO", Period: 500}).BackTest()
+	suite.Nil(err)
+
+	cframe := models.GetCandleFrame("VOO", 500)
+	adx := talib.Adx(cframe.Highs(), cframe.Lows(), cframe.Closes(), 14)
+	trending := map[int64]bool{}
+	for i, candle := range cframe.Candles {
+		trending[candle.Time] = adx[i] >= 20
+	}
+	for _, signal := range op.PatternSignals {
+		if signal.Action == indicator.BUY {
+			suite.True(trending[signal.Time])
+		}
+	}
+	for _, signal := range result.Signals {
+		if signal.Action == indicator.BUY {
+			suite.True(trending[signal.Time])
+		}
+	}
+
+	models.DeleteRuleStrategy("golden")
+	models.DeleteBacktestResult("VOO")
+}
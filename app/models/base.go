@@ -31,5 +31,33 @@ func InitDB() {
 		&indicator.MacdSignal{},
 		&indicator.RsiSignal{},
 		&indicator.WillrSignal{},
+		&indicator.IchimokuSignal{},
+		&indicator.PsarSignal{},
+		&indicator.ObvSignal{},
+		&indicator.MfiSignal{},
+		&indicator.DonchianSignal{},
+		&indicator.PatternSignal{},
+		&indicator.DivergenceSignal{},
+		&indicator.StochSignal{},
+		&indicator.LevelSignal{},
+		&Confirmation{},
+		&RuleStrategy{},
+		&indicator.RuleSignal{},
+		&PairResult{},
+		&indicator.PairTrade{},
+		&Watchlist{},
+		&Digest{},
+		&DigestEntry{},
+		&DigestSignal{},
+		&AlertSubscription{},
+		&AlertDelivery{},
+		&PaperAccount{},
+		&PaperOrder{},
+		&PaperFill{},
+		&PaperPosition{},
+		&EntryOrder{},
+		&CorporateAction{},
+		&QualityReport{},
+		&QualityIssue{},
 	)
 }
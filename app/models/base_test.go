@@ -54,6 +54,64 @@ var backTestParam = models.BackTestParam{
 		WillrSellThreadLow:  -25,
 		WillrSellThreadHigh: -10,
 	},
+	Ichimoku: &indicator.IchimokuBacktestParam{
+		IchimokuTenkanLow:  7,
+		IchimokuTenkanHigh: 11,
+		IchimokuKijunLow:   22,
+		IchimokuKijunHigh:  30,
+		IchimokuSenkouLow:  44,
+		IchimokuSenkouHigh: 56,
+	},
+	Psar: &indicator.PsarBacktestParam{
+		PsarStepLow:  0.01,
+		PsarStepHigh: 0.05,
+		PsarMaxLow:   0.1,
+		PsarMaxHigh:  0.3,
+	},
+	Obv: &indicator.ObvBacktestParam{
+		ObvPeriodLow:  15,
+		ObvPeriodHigh: 25,
+	},
+	Mfi: &indicator.MfiBacktestParam{
+		MfiPeriodLow:      13,
+		MfiPeriodHigh:     15,
+		MfiBuyThreadLow:   19,
+		MfiBuyThreadHigh:  21,
+		MfiSellThreadLow:  79,
+		MfiSellThreadHigh: 81,
+	},
+	Donchian: &indicator.DonchianBacktestParam{
+		DonchianEntryLow:  19,
+		DonchianEntryHigh: 21,
+		DonchianExitLow:   9,
+		DonchianExitHigh:  11,
+		DonchianAtrLow:    14,
+		DonchianAtrHigh:   15,
+	},
+	Pattern: &indicator.PatternBacktestParam{
+		PatternTrendLow:  3,
+		PatternTrendHigh: 7,
+	},
+	Divergence: &indicator.DivergenceBacktestParam{
+		DivergenceWidthLow:  3,
+		DivergenceWidthHigh: 7,
+	},
+	Stoch: &indicator.StochBacktestParam{
+		StochKLow:           12,
+		StochKHigh:          14,
+		StochDLow:           3,
+		StochDHigh:          3,
+		StochSlowingLow:     3,
+		StochSlowingHigh:    3,
+		StochBuyThreadLow:   20,
+		StochBuyThreadHigh:  25,
+		StochSellThreadLow:  75,
+		StochSellThreadHigh: 80,
+	},
+	Level: &indicator.LevelBacktestParam{
+		LevelLookbackLow:  40,
+		LevelLookbackHigh: 60,
+	},
 }
 
 type ModelsTestSuite struct {
@@ -76,6 +134,34 @@ func (suite *ModelsTestSuite) SetupSuite() {
 		&indicator.MacdSignal{},
 		&indicator.RsiSignal{},
 		&indicator.WillrSignal{},
+		&indicator.IchimokuSignal{},
+		&indicator.PsarSignal{},
+		&indicator.ObvSignal{},
+		&indicator.MfiSignal{},
+		&indicator.DonchianSignal{},
+		&indicator.PatternSignal{},
+		&indicator.DivergenceSignal{},
+		&indicator.StochSignal{},
+		&indicator.LevelSignal{},
+		&models.Confirmation{},
+		&models.RuleStrategy{},
+		&indicator.RuleSignal{},
+		&models.PairResult{},
+		&indicator.PairTrade{},
+		&models.Watchlist{},
+		&models.Digest{},
+		&models.DigestEntry{},
+		&models.DigestSignal{},
+		&models.AlertSubscription{},
+		&models.AlertDelivery{},
+		&models.PaperAccount{},
+		&models.PaperOrder{},
+		&models.PaperFill{},
+		&models.PaperPosition{},
+		&models.EntryOrder{},
+		&models.CorporateAction{},
+		&models.QualityReport{},
+		&models.QualityIssue{},
 	)
 
 	adjStock, _ := stock.GetStockData("VOO", 500, true)
@@ -85,7 +171,7 @@ func (suite *ModelsTestSuite) SetupSuite() {
 
 func (suite *ModelsTestSuite) SetupTest() {
 	suite.Candles.CreateCandles()
-	suite.Op = backTestParam.BackTest()
+	suite.Op, _ = backTestParam.BackTest()
 }
 
 func (suite *ModelsTestSuite) TearDownTest() {
@@ -0,0 +1,276 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/jumpei00/gostocktrade/app/models/indicator"
+)
+
+// type of order
+const (
+	MARKETORDER = indicator.MARKETORDER
+	LIMITORDER  = indicator.LIMITORDER
+	STOPORDER   = indicator.STOPORDER
+)
+
+// Broker routes orders, implemented by SimulatedBroker, and an adapter of a real broker
+// should convert its orders, fills and positions into the same records
+type Broker interface {
+	SubmitOrder(order *PaperOrder) error
+	CancelOrder(id int) error
+	Orders() ([]PaperOrder, error)
+	Positions() ([]PaperPosition, error)
+	Account() (*PaperReport, error)
+	Fills() ([]PaperFill, error)
+}
+
+// SimulatedBroker is in-process broker of paper account, which fills orders against stored candles by Process
+type SimulatedBroker struct {
+	account *PaperAccount
+}
+
+// NewSimulatedBroker returns SimulatedBroker of paper account
+func NewSimulatedBroker(pa *PaperAccount) *SimulatedBroker {
+	return &SimulatedBroker{account: pa}
+}
+
+// SubmitOrder validates and stores order as open, when time is 0, the order is at the last candle
+func (sb *SimulatedBroker) SubmitOrder(order *PaperOrder) error {
+	if order.Side != indicator.BUY && order.Side != indicator.SELL {
+		return errors.New("order side must be BUY or SELL")
+	}
+	if order.Quantity < 0 || (order.Side == indicator.BUY && order.Quantity == 0) {
+		return errors.New("bad order quantity")
+	}
+
+	switch order.Type {
+	case "":
+		order.Type = MARKETORDER
+	case MARKETORDER:
+	case LIMITORDER, STOPORDER:
+		if order.Price <= 0 {
+			return fmt.Errorf("%v order needs price", order.Type)
+		}
+	default:
+		return fmt.Errorf("unknown order type: %v", order.Type)
+	}
+
+	if order.Time == 0 {
+		lastCandleTime, err := LastCandleTime(order.Symbol)
+		if err != nil {
+			return fmt.Errorf("no candles, symbol: %v", order.Symbol)
+		}
+		order.Time = lastCandleTime
+	}
+
+	order.ID, order.AccountID, order.Status, order.Reason = 0, sb.account.ID, ORDEROPEN, ""
+	return DB.Create(order).Error
+}
+
+// CancelOrder cancels open order of the account
+func (sb *SimulatedBroker) CancelOrder(id int) error {
+	var order PaperOrder
+	if err := DB.Where("id = ? AND account_id = ?", id, sb.account.ID).First(&order).Error; err != nil {
+		return fmt.Errorf("no order: %v", id)
+	}
+	if order.Status != ORDEROPEN {
+		return fmt.Errorf("order is already %v", order.Status)
+	}
+
+	order.Status = ORDERCANCELLED
+	return DB.Save(&order).Error
+}
+
+// Orders returns orders of the account, newest first
+func (sb *SimulatedBroker) Orders() ([]PaperOrder, error) {
+	orders := []PaperOrder{}
+	err := DB.Where("account_id = ?", sb.account.ID).Order("time desc, id desc").Find(&orders).Error
+	return orders, err
+}
+
+// Positions returns positions of the account valued by the last candles
+func (sb *SimulatedBroker) Positions() ([]PaperPosition, error) {
+	return sb.account.Report().Positions, nil
+}
+
+// Account returns report of the account
+func (sb *SimulatedBroker) Account() (*PaperReport, error) {
+	return sb.account.Report(), nil
+}
+
+// Fills returns fills of the account, newest first
+func (sb *SimulatedBroker) Fills() ([]PaperFill, error) {
+	fills := []PaperFill{}
+	err := DB.Where("account_id = ?", sb.account.ID).Order("time desc, id desc").Find(&fills).Error
+	return fills, err
+}
+
+// Process fills open orders of symbol against candles after the order, candle by candle,
+// market order is filled at the open of the next candle, and limit or stop order is filled
+// at the first candle reaching the price by FillPrice
+func (sb *SimulatedBroker) Process(symbol string) {
+	orders := []PaperOrder{}
+	DB.Where("account_id = ? AND symbol = ? AND status = ?", sb.account.ID, symbol, ORDEROPEN).Order("time, id").Find(&orders)
+	if len(orders) == 0 {
+		return
+	}
+
+	for _, candle := range GetCandleFrameBetween(symbol, orders[0].Time+1, math.MaxInt64).Candles {
+		for i := range orders {
+			order := &orders[i]
+			if order.Status != ORDEROPEN || order.Time >= candle.Time {
+				continue
+			}
+			if price, ok := indicator.FillPrice(order.Type, order.Side, order.Price, candle.Open, candle.High, candle.Low); ok {
+				sb.fill(order, candle.Time, price)
+			}
+		}
+	}
+}
+
+// fill executes order at price, if cash or position is not enough, the order is rejected
+func (sb *SimulatedBroker) fill(order *PaperOrder, time int64, price float64) {
+	pa := sb.account
+	// position is created at the first buy filled, not by rejected orders
+	position := PaperPosition{AccountID: pa.ID, Symbol: order.Symbol}
+	DB.Where(PaperPosition{AccountID: pa.ID, Symbol: order.Symbol}).First(&position)
+
+	quantity := order.Quantity
+	if order.Side == indicator.SELL && quantity == 0 {
+		quantity = position.Quantity
+	}
+
+	switch {
+	case order.Side == indicator.BUY && quantity*price > pa.Cash:
+		order.Status, order.Reason = ORDERREJECTED, "insufficient cash"
+	case order.Side == indicator.SELL && (quantity == 0 || quantity > position.Quantity):
+		order.Status, order.Reason = ORDERREJECTED, "insufficient position"
+	}
+	if order.Status == ORDERREJECTED {
+		DB.Save(order)
+		return
+	}
+
+	fill := PaperFill{AccountID: pa.ID, OrderID: order.ID, Symbol: order.Symbol, Side: order.Side, Quantity: quantity, Price: price, Time: time}
+	if order.Side == indicator.BUY {
+		position.AveragePrice = (position.AveragePrice*position.Quantity + price*quantity) / (position.Quantity + quantity)
+		position.Quantity += quantity
+		pa.Cash -= price * quantity
+	} else {
+		fill.RealizedPnL = (price - position.AveragePrice) * quantity
+		position.RealizedPnL += fill.RealizedPnL
+		position.Quantity -= quantity
+		if position.Quantity == 0 {
+			position.AveragePrice = 0
+		}
+		pa.Cash += price * quantity
+	}
+
+	order.Quantity, order.Status = quantity, ORDERFILLED
+	DB.Save(order)
+	DB.Create(&fill)
+	DB.Save(&position)
+	DB.Model(pa).Update("cash", pa.Cash)
+}
+
+// EmitSignalOrders submits market orders of signals of strategy for symbol from start to broker,
+// skipping signals already ordered, buy is sized by sizing at the close of the signal, nil means 1 share,
+// and sell is all of the position
+func EmitSignalOrders(broker Broker, symbol, strategy string, start int64, sizing *Sizing) error {
+	signals, err := strategySignals(symbol, strategy)
+	if err != nil {
+		return err
+	}
+
+	orders, err := broker.Orders()
+	if err != nil {
+		return err
+	}
+	ordered := map[int64]bool{}
+	for _, order := range orders {
+		if order.Symbol == symbol && order.Strategy == strategy {
+			ordered[order.Time] = true
+		}
+	}
+
+	var cframe *CandleFrame
+	for _, signal := range signals {
+		if signal.Time < start || ordered[signal.Time] {
+			continue
+		}
+
+		order := PaperOrder{Symbol: symbol, Side: signal.Action, Type: MARKETORDER, Strategy: strategy, Time: signal.Time}
+		if signal.Action == indicator.BUY {
+			order.Quantity = 1
+			if sizing != nil {
+				if cframe == nil {
+					cframe = GetCandleFrameBetween(symbol, 0, math.MaxInt64)
+					cframe.setSizing(sizing)
+				}
+				if order.Quantity, err = sizedQuantity(broker, cframe, signal.Time); err != nil {
+					return err
+				}
+			}
+			if order.Quantity == 0 {
+				logrus.Infof("signal is not ordered by sizing: %v, %v", symbol, signal.Time)
+				continue
+			}
+		}
+		if err := broker.SubmitOrder(&order); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sizedQuantity returns quantity of buy at the close of day of time by sizing of cframe,
+// equity is of broker, and returns of kelly are of past sells of the symbol
+func sizedQuantity(broker Broker, cframe *CandleFrame, time int64) (float64, error) {
+	day, ok := cframe.sizingDays[time]
+	if !ok {
+		return 0, nil
+	}
+
+	report, err := broker.Account()
+	if err != nil {
+		return 0, err
+	}
+	fills, err := broker.Fills()
+	if err != nil {
+		return 0, err
+	}
+
+	// fills are newest first
+	returns := []float64{}
+	for i := len(fills) - 1; i >= 0; i-- {
+		fill := fills[i]
+		if fill.Symbol != cframe.Symbol || fill.Side != indicator.SELL {
+			continue
+		}
+		if cost := fill.Price*fill.Quantity - fill.RealizedPnL; cost > 0 {
+			returns = append(returns, fill.RealizedPnL/cost)
+		}
+	}
+
+	return cframe.sizing.quantity(report.Equity, cframe.Candles[day].Close, cframe.sizingAtr[day], returns), nil
+}
+
+// PaperTrade emits new signals of symbol to simulated brokers of accounts with strategy for the symbol,
+// and fills open orders of symbol for all accounts, used after SignalTest
+func PaperTrade(symbol string) {
+	for _, pa := range GetPaperAccounts() {
+		pa := pa
+		broker := NewSimulatedBroker(&pa)
+		if pa.Strategy != "" && pa.Symbol == symbol {
+			if err := EmitSignalOrders(broker, symbol, pa.Strategy, pa.Start, pa.sizing()); err != nil {
+				logrus.Warnf("paper order error: %v, %v", pa.Name, err)
+				continue
+			}
+		}
+		broker.Process(symbol)
+	}
+}
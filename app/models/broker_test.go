@@ -0,0 +1,57 @@
+package models_test
+
+import (
+	"math"
+
+	"github.com/jumpei00/gostocktrade/app/models"
+	"github.com/jumpei00/gostocktrade/app/models/indicator"
+)
+
+func (suite *ModelsTestSuite) TestSimulatedBroker() {
+	candles := models.GetCandleFrame("VOO", 10).Candles
+	pa := models.PaperAccount{Name: "manual", InitialCash: 10000, Start: candles[0].Time}
+	suite.Nil(pa.CreatePaperAccount())
+	broker := models.NewSimulatedBroker(&pa)
+
+	// limit below every low is not filled, and stop above the open of the next candle is filled at the price
+	low, high := math.MaxFloat64, 0.0
+	for _, candle := range candles[1:] {
+		low, high = math.Min(low, candle.Low), math.Max(high, candle.High)
+	}
+	limit := models.PaperOrder{Symbol: "VOO", Side: indicator.BUY, Type: models.LIMITORDER, Quantity: 1, Price: low - 1, Time: candles[0].Time}
+	suite.Nil(broker.SubmitOrder(&limit))
+	stop := models.PaperOrder{Symbol: "VOO", Side: indicator.BUY, Type: models.STOPORDER, Quantity: 2, Price: high, Time: candles[0].Time}
+	suite.Nil(broker.SubmitOrder(&stop))
+	suite.NotNil(broker.SubmitOrder(&models.PaperOrder{Symbol: "VOO", Side: indicator.BUY, Type: models.LIMITORDER, Quantity: 1}))
+	suite.NotNil(broker.SubmitOrder(&models.PaperOrder{Symbol: "VOO", Side: indicator.BUY, Type: "trailing", Quantity: 1}))
+
+	broker.Process("VOO")
+	fills, _ := broker.Fills()
+	suite.Len(fills, 1)
+	suite.Equal(stop.ID, fills[0].OrderID)
+	suite.Equal(high, fills[0].Price)
+
+	positions, _ := broker.Positions()
+	suite.Equal(2.0, positions[0].Quantity)
+
+	// open order is cancelled once
+	suite.Nil(broker.CancelOrder(limit.ID))
+	suite.NotNil(broker.CancelOrder(limit.ID))
+	suite.NotNil(broker.CancelOrder(stop.ID))
+
+	models.DeletePaperAccount("manual")
+
+	// rejected sell leaves no position
+	pa = models.PaperAccount{Name: "reject", InitialCash: 10000, Start: candles[0].Time}
+	suite.Nil(pa.CreatePaperAccount())
+	broker = models.NewSimulatedBroker(&pa)
+	sell := models.PaperOrder{Symbol: "VOO", Side: indicator.SELL, Type: models.MARKETORDER, Time: candles[0].Time}
+	suite.Nil(broker.SubmitOrder(&sell))
+	broker.Process("VOO")
+	orders, _ := broker.Orders()
+	suite.Equal(models.ORDERREJECTED, orders[0].Status)
+	positions, _ = broker.Positions()
+	suite.Empty(positions)
+
+	models.DeletePaperAccount("reject")
+}
@@ -16,15 +16,30 @@ type Candles []Candle
 // ex) [Date[1, 2, 3...], Open[1, 2, 3...]...] → [[Date[1], Open[1]...], [Date[2], Open[2]...]...]
 // and return pointer of Candles(used as constructor)
 // Because of using for frondend, this method also converts time to Unixtime
+// Candles are price-return series, adjusted for splits by yahoo but not for dividends,
+// go-quote returns raw Open/High/Low with dividend-adjusted Close as adjusted quote,
+// and Open/High/Low scaled by Close / adjusted Close as unadjusted quote,
+// so Open/High/Low/Volume are of adjStock and Close is of Stock, matched by date.
+// Dividend-adjusted series is derived from corporate actions by Adjusted
 func NewCandlesFromQuote(adjStock *quote.Quote, Stock *quote.Quote) *Candles {
-	candles := Candles{}
+	closes := map[int64]float64{}
 	for i := 0; i < len(Stock.Date); i++ {
+		closes[Stock.Date[i].Unix()] = Stock.Close[i]
+	}
+
+	candles := Candles{}
+	for i := 0; i < len(adjStock.Date); i++ {
+		unadjusted, ok := closes[adjStock.Date[i].Unix()]
+		if !ok {
+			continue
+		}
 		candles = append(candles, Candle{
-			Time:   Stock.Date[i].Unix() * 1000,
+			Symbol: Stock.Symbol,
+			Time:   adjStock.Date[i].Unix() * 1000,
 			Open:   (math.Round(adjStock.Open[i]*100) / 100),
 			High:   (math.Round(adjStock.High[i]*100) / 100),
 			Low:    (math.Round(adjStock.Low[i]*100) / 100),
-			Close:  (math.Round(Stock.Close[i]*100) / 100),
+			Close:  (math.Round(unadjusted*100) / 100),
 			Volume: (math.Round(adjStock.Volume[i]*100) / 100),
 		})
 	}
@@ -36,7 +51,7 @@ func NewCandlesFromQuote(adjStock *quote.Quote, Stock *quote.Quote) *Candles {
 // After get data, return DataFrame stored in data
 func GetCandleFrame(symbol string, limit int) *CandleFrame {
 	var candles Candles
-	DB.Order("time desc").Limit(limit).Find(&candles)
+	DB.Where("symbol = ?", symbol).Order("time desc").Limit(limit).Find(&candles)
 	sort.Slice(candles, func(i, j int) bool { return candles[i].Time < candles[j].Time })
 
 	cframe := CandleFrame{}
@@ -46,11 +61,35 @@ func GetCandleFrame(symbol string, limit int) *CandleFrame {
 	return &cframe
 }
 
+// GetCandleFrameBetween gets candle data from "from" to "to" by ascending, both are unixtime(ms) and included
+func GetCandleFrameBetween(symbol string, from, to int64) *CandleFrame {
+	var candles Candles
+	DB.Where("symbol = ? AND time BETWEEN ? AND ?", symbol, from, to).Order("time asc").Find(&candles)
+
+	cframe := CandleFrame{}
+	cframe.Symbol = symbol
+	cframe.Candles = candles
+
+	return &cframe
+}
+
 // AllDeleteCandles deletes all data of "candles" table
 func AllDeleteCandles() {
 	DB.Session(&gorm.Session{AllowGlobalUpdate: true}).Delete(&Candle{})
 }
 
+// DeleteCandles deletes candle data of symbol, candles of other symbols are kept
+func DeleteCandles(symbol string) {
+	DB.Delete(&Candle{}, "symbol = ?", symbol)
+}
+
+// StoredSymbols returns symbols of which candles are stored
+func StoredSymbols() []string {
+	symbols := []string{}
+	DB.Model(&Candle{}).Distinct("symbol").Order("symbol").Pluck("symbol", &symbols)
+	return symbols
+}
+
 // CreateCandles creates candle data
 func (cs *Candles) CreateCandles() {
 	DB.Create(cs)
@@ -59,6 +98,7 @@ func (cs *Candles) CreateCandles() {
 // Candle is daily stock candledata, also used as json
 type Candle struct {
 	ID     int     `json:"-"`
+	Symbol string  `gorm:"index" json:"-"`
 	Time   int64   `json:"time"`
 	Open   float64 `json:"open"`
 	High   float64 `json:"high"`
@@ -67,19 +107,19 @@ type Candle struct {
 	Volume float64 `json:"volume"`
 }
 
-// LastCandleTime returns a time of last candle
-func LastCandleTime() (int64, error) {
+// LastCandleTime returns a time of last candle of symbol
+func LastCandleTime(symbol string) (int64, error) {
 	var candle Candle
-	if err := DB.Last(&candle).Error; err != nil {
+	if err := DB.Where("symbol = ?", symbol).Order("time desc").First(&candle).Error; err != nil {
 		return 0, err
 	}
 	return candle.Time, nil
 }
 
-// MatchTime returns ID mathed to Time field
-func MatchTime(time int64) (int, error) {
+// MatchTime returns ID of candle of symbol mathed to Time field
+func MatchTime(symbol string, time int64) (int, error) {
 	var candle Candle
-	if err := DB.Where("Time = ?", time).First(&candle).Error; err != nil {
+	if err := DB.Where("symbol = ? AND Time = ?", symbol, time).First(&candle).Error; err != nil {
 		return 0, err
 	}
 	return candle.ID, nil
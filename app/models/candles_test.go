@@ -27,10 +27,21 @@ func (suite *ModelsTestSuite) TestGetCandleFrame() {
 	suite.IsIncreasing(time)
 }
 
+func (suite *ModelsTestSuite) TestGetCandleFrameBetween() {
+	all := models.GetCandleFrame("VOO", 500).Candles
+	from, to := all[10].Time, all[20].Time
+	cframe := models.GetCandleFrameBetween("VOO", from, to)
+
+	suite.Equal("VOO", cframe.Symbol)
+	suite.Len(cframe.Candles, 11)
+	suite.Equal(from, cframe.Candles[0].Time)
+	suite.Equal(to, cframe.Candles[10].Time)
+}
+
 func (suite *ModelsTestSuite) TestLastCandleTime() {
 	cframe := models.GetCandleFrame("VOO", 500)
 	lastTime := cframe.Candles[len(cframe.Candles)-1].Time
-	lastCandleTime, err := models.LastCandleTime()
+	lastCandleTime, err := models.LastCandleTime("VOO")
 
 	suite.Equal(lastTime, lastCandleTime)
 	suite.Nil(err)
@@ -41,15 +52,15 @@ func (suite *ModelsTestSuite) TestMatchTime() {
 	firstCandle := cframe.Candles[0]
 	lastCandle := cframe.Candles[len(cframe.Candles)-1]
 
-	firstMatch, err1 := models.MatchTime(firstCandle.Time)
-	lastMatch, err2 := models.MatchTime(lastCandle.Time)
+	firstMatch, err1 := models.MatchTime("VOO", firstCandle.Time)
+	lastMatch, err2 := models.MatchTime("VOO", lastCandle.Time)
 
 	suite.Equal(firstCandle.ID, firstMatch)
 	suite.Nil(err1)
 	suite.Equal(lastCandle.ID, lastMatch)
 	suite.Nil(err2)
 
-	wrongMatch, err := models.MatchTime(firstCandle.Time+1)
+	wrongMatch, err := models.MatchTime("VOO", firstCandle.Time+1)
 
 	suite.Equal(0, wrongMatch)
 	suite.NotNil(err)
@@ -61,3 +72,22 @@ func (suite *ModelsTestSuite) TestAllDeleteCandles() {
 
 	suite.Empty(cframe.Candles)
 }
+
+func (suite *ModelsTestSuite) TestDeleteCandles() {
+	// candles of other symbol
+	other := models.Candles{}
+	for _, candle := range *suite.Candles {
+		candle.ID = 0
+		candle.Symbol = "QQQ"
+		other = append(other, candle)
+	}
+	other.CreateCandles()
+	suite.Equal([]string{"QQQ", "VOO"}, models.StoredSymbols())
+	suite.Len(models.GetCandleFrame("QQQ", 10).Candles, 10)
+
+	// only candles of the symbol are deleted
+	models.DeleteCandles("QQQ")
+	suite.Empty(models.GetCandleFrame("QQQ", 10).Candles)
+	suite.NotEmpty(models.GetCandleFrame("VOO", 10).Candles)
+	suite.Equal([]string{"VOO"}, models.StoredSymbols())
+}
@@ -0,0 +1,196 @@
+package models
+
+import (
+	"sort"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/jumpei00/gostocktrade/stock"
+)
+
+// type of corporate action
+const (
+	SPLIT    = "split"
+	DIVIDEND = "dividend"
+)
+
+// dividends of total-return backtest, empty means price-return
+const (
+	// CREDITDIVIDEND adds dividends to profit as cash
+	CREDITDIVIDEND = "credit"
+	// REINVESTDIVIDEND buys shares by dividends at the close of ex-date
+	REINVESTDIVIDEND = "reinvest"
+)
+
+// basis of price series
+const (
+	// RAWPRICE is price at the time, not adjusted for splits
+	RAWPRICE = "raw"
+	// PRICERETURN is adjusted for splits, the same as stored candles
+	PRICERETURN = "price"
+	// TOTALRETURN is adjusted for splits and dividends
+	TOTALRETURN = "total"
+)
+
+// CorporateAction is split or cash dividend of symbol at ex-date(unixtime(ms)), amount of dividend
+// is per share on the basis of stored candles, that is, adjusted for later splits
+type CorporateAction struct {
+	ID     int    `gorm:"primary_key" json:"-"`
+	Symbol string `gorm:"index" json:"-"`
+	Time   int64  `json:"time"`
+	Type   string `json:"type"`
+	// Ratio is new shares per old share of split, such as 2 of 2:1
+	Ratio  float64 `json:"ratio,omitempty"`
+	Amount float64 `json:"amount,omitempty"`
+}
+
+// NewCorporateActions converts events to corporate actions by ascending time
+func NewCorporateActions(events *stock.Events) []CorporateAction {
+	actions := []CorporateAction{}
+	for _, dividend := range events.Dividends {
+		actions = append(actions, CorporateAction{
+			Symbol: events.Symbol, Time: dividend.Date.Unix() * 1000, Type: DIVIDEND, Amount: dividend.Amount})
+	}
+	for _, split := range events.Splits {
+		if split.Numerator <= 0 || split.Denominator <= 0 {
+			continue
+		}
+		actions = append(actions, CorporateAction{
+			Symbol: events.Symbol, Time: split.Date.Unix() * 1000, Type: SPLIT, Ratio: split.Numerator / split.Denominator})
+	}
+	sort.SliceStable(actions, func(i, j int) bool { return actions[i].Time < actions[j].Time })
+	return actions
+}
+
+// SaveCorporateActions replaces corporate actions of symbol
+func SaveCorporateActions(symbol string, actions []CorporateAction) error {
+	DeleteCorporateActions(symbol)
+	if len(actions) == 0 {
+		return nil
+	}
+	for i := range actions {
+		actions[i].ID, actions[i].Symbol = 0, symbol
+	}
+	return DB.Create(&actions).Error
+}
+
+// GetCorporateActions returns corporate actions of symbol by ascending time
+func GetCorporateActions(symbol string) []CorporateAction {
+	actions := []CorporateAction{}
+	DB.Where("symbol = ?", symbol).Order("time, id").Find(&actions)
+	return actions
+}
+
+// DeleteCorporateActions deletes corporate actions of symbol
+func DeleteCorporateActions(symbol string) {
+	DB.Delete(CorporateAction{}, "symbol = ?", symbol)
+}
+
+// Raw returns candles at the price of the time, which are not adjusted for splits after each candle
+func (cs Candles) Raw(actions []CorporateAction) Candles {
+	raw := make(Candles, len(cs))
+	for i, candle := range cs {
+		factor := 1.0
+		for _, action := range actions {
+			if action.Type == SPLIT && action.Time > candle.Time {
+				factor *= action.Ratio
+			}
+		}
+		candle.Open, candle.High, candle.Low, candle.Close = candle.Open*factor, candle.High*factor, candle.Low*factor, candle.Close*factor
+		candle.Volume /= factor
+		raw[i] = candle
+	}
+	return raw
+}
+
+// Adjusted returns total-return candles, which are adjusted backward by dividends after each candle,
+// the factor of dividend is 1 - amount / close of the day before ex-date
+func (cs Candles) Adjusted(actions []CorporateAction) Candles {
+	factors := make([]float64, len(cs))
+	for i := range factors {
+		factors[i] = 1
+	}
+	for _, action := range actions {
+		if action.Type != DIVIDEND {
+			continue
+		}
+		exDay := sort.Search(len(cs), func(i int) bool { return cs[i].Time >= action.Time })
+		if exDay == 0 || exDay == len(cs) || cs[exDay-1].Close <= 0 {
+			continue
+		}
+		factor := 1 - action.Amount/cs[exDay-1].Close
+		for day := 0; day < exDay; day++ {
+			factors[day] *= factor
+		}
+	}
+
+	adjusted := make(Candles, len(cs))
+	for i, candle := range cs {
+		candle.Open, candle.High, candle.Low, candle.Close = candle.Open*factors[i], candle.High*factors[i], candle.Low*factors[i], candle.Close*factors[i]
+		adjusted[i] = candle
+	}
+	return adjusted
+}
+
+// GetPriceFrame returns candles of symbol for limit on basis of raw, price or total
+func GetPriceFrame(symbol string, limit int, basis string) *CandleFrame {
+	cframe := GetCandleFrame(symbol, limit)
+	switch basis {
+	case RAWPRICE:
+		cframe.Candles = Candles(cframe.Candles).Raw(GetCorporateActions(symbol))
+	case TOTALRETURN:
+		cframe.Candles = Candles(cframe.Candles).Adjusted(GetCorporateActions(symbol))
+	}
+	return cframe
+}
+
+// exDividend is dividend at the candle of ex-date
+type exDividend struct {
+	Time   int64
+	Amount float64
+	Close  float64
+}
+
+// setDividends sets dividends of stored corporate actions used for total-return performance,
+// mode is credit or reinvest, and empty is price-return
+func (cframe *CandleFrame) setDividends(mode string) {
+	cframe.dividendMode, cframe.dividends = "", nil
+	if mode == "" {
+		return
+	}
+	if mode != CREDITDIVIDEND && mode != REINVESTDIVIDEND {
+		logrus.Warnf("total return, unknown dividends: %v", mode)
+		return
+	}
+
+	cframe.dividendMode = mode
+	candles := cframe.Candles
+	for _, action := range GetCorporateActions(cframe.Symbol) {
+		if action.Type != DIVIDEND {
+			continue
+		}
+		// ex-date not traded is the next candle
+		exDay := sort.Search(len(candles), func(i int) bool { return candles[i].Time >= action.Time })
+		if exDay == 0 || exDay == len(candles) {
+			continue
+		}
+		cframe.dividends = append(cframe.dividends, exDividend{Time: candles[exDay].Time, Amount: action.Amount, Close: candles[exDay].Close})
+	}
+}
+
+// dividendValue returns value at sell of a share bought at buyTime, including dividends of ex-date
+// after buy until sell, which are credited or reinvested
+func (cframe *CandleFrame) dividendValue(buyTime, sellTime int64, sellPrice float64) float64 {
+	shares, cash := 1.0, 0.0
+	for _, dividend := range cframe.dividends {
+		if dividend.Time <= buyTime || dividend.Time > sellTime {
+			continue
+		}
+		if cframe.dividendMode == REINVESTDIVIDEND && dividend.Close > 0 {
+			shares += shares * dividend.Amount / dividend.Close
+		} else {
+			cash += shares * dividend.Amount
+		}
+	}
+	return shares*sellPrice + cash
+}
@@ -0,0 +1,59 @@
+package models_test
+
+import (
+	"github.com/jumpei00/gostocktrade/app/models"
+)
+
+func (suite *ModelsTestSuite) TestCorporateActions() {
+	candles := models.GetCandleFrame("VOO", 500).Candles
+	actions := []models.CorporateAction{
+		{Time: candles[100].Time, Type: models.DIVIDEND, Amount: 1.5},
+		{Time: candles[300].Time, Type: models.SPLIT, Ratio: 2},
+		{Time: candles[400].Time, Type: models.DIVIDEND, Amount: 1.5},
+	}
+	suite.Nil(models.SaveCorporateActions("VOO", actions))
+	suite.Len(models.GetCorporateActions("VOO"), 3)
+
+	// raw is doubled before split
+	raw := models.GetPriceFrame("VOO", 500, models.RAWPRICE).Candles
+	suite.Equal(candles[299].Close*2, raw[299].Close)
+	suite.Equal(candles[300].Close, raw[300].Close)
+
+	// total return is adjusted before ex-date
+	total := models.GetPriceFrame("VOO", 500, models.TOTALRETURN).Candles
+	suite.Less(total[99].Close, candles[99].Close)
+	suite.InDelta(candles[399].Close-1.5, total[399].Close, 1e-9)
+	suite.Equal(candles[400].Close, total[400].Close)
+
+	models.DeleteCorporateActions("VOO")
+	suite.Empty(models.GetCorporateActions("VOO"))
+}
+
+func (suite *ModelsTestSuite) TestTotalReturnBacktest() {
+	candles := models.GetCandleFrame("VOO", 500).Candles
+	actions := []models.CorporateAction{}
+	for day := 20; day < len(candles); day += 60 {
+		actions = append(actions, models.CorporateAction{Time: candles[day].Time, Type: models.DIVIDEND, Amount: 1.5})
+	}
+	models.SaveCorporateActions("VOO", actions)
+
+	// dividends of held trades are added
+	total := backTestParam
+	total.TotalReturn = models.CREDITDIVIDEND
+	op, err := total.BackTest()
+	suite.Nil(err)
+	suite.Equal(models.CREDITDIVIDEND, op.TotalReturn)
+	suite.GreaterOrEqual(op.EmaPerformance, suite.Op.EmaPerformance)
+	suite.GreaterOrEqual(op.MacdPerformance, suite.Op.MacdPerformance)
+
+	total.TotalReturn = models.REINVESTDIVIDEND
+	op, _ = total.BackTest()
+	suite.Equal(models.REINVESTDIVIDEND, op.TotalReturn)
+
+	total.TotalReturn = "dividend"
+	_, err = total.BackTest()
+	suite.NotNil(err)
+
+	models.DeleteCorporateActions("VOO")
+	models.DeleteBacktestResult("VOO")
+}
@@ -0,0 +1,129 @@
+package models
+
+import (
+	"errors"
+	"sort"
+
+	"github.com/jumpei00/gostocktrade/app/models/analysis"
+)
+
+// RollingCorrelation is correlation of returns between two symbols for last window days
+type RollingCorrelation struct {
+	A      string    `json:"a"`
+	B      string    `json:"b"`
+	Values []float64 `json:"values"`
+}
+
+// CorrelationFrame is correlation matrix of daily returns for the full period in order of symbols,
+// and rolling correlation of each pair, times are of returns
+type CorrelationFrame struct {
+	Symbols []string             `json:"symbols"`
+	Times   []int64              `json:"times"`
+	Matrix  [][]float64          `json:"matrix"`
+	Window  int                  `json:"window"`
+	Rolling []RollingCorrelation `json:"rolling"`
+}
+
+// PairFrame is analysis of pair, y is regressed on x for the full period,
+// so spread is y - (hedge ratio * x + intercept), and z-score of spread is for last window days
+type PairFrame struct {
+	Y           string    `json:"y"`
+	X           string    `json:"x"`
+	Correlation float64   `json:"correlation"`
+	HedgeRatio  float64   `json:"hedge_ratio"`
+	Intercept   float64   `json:"intercept"`
+	Window      int       `json:"window"`
+	Times       []int64   `json:"times"`
+	Spread      []float64 `json:"spread"`
+	ZScore      []float64 `json:"zscore"`
+}
+
+// alignCloses returns times at which all symbols have candles in latest limit candles,
+// and closes of each symbol at the times
+func alignCloses(symbols []string, limit int) (times []int64, closes [][]float64) {
+	counts := map[int64]int{}
+	prices := []map[int64]float64{}
+	for _, symbol := range symbols {
+		price := map[int64]float64{}
+		for _, candle := range GetCandleFrame(symbol, limit).Candles {
+			price[candle.Time] = candle.Close
+			counts[candle.Time]++
+		}
+		prices = append(prices, price)
+	}
+
+	for time, count := range counts {
+		if count == len(symbols) {
+			times = append(times, time)
+		}
+	}
+	sort.Slice(times, func(i, j int) bool { return times[i] < times[j] })
+
+	for _, price := range prices {
+		close := make([]float64, len(times))
+		for i, time := range times {
+			close[i] = price[time]
+		}
+		closes = append(closes, close)
+	}
+
+	return times, closes
+}
+
+// GetCorrelationFrame returns correlation of symbols, if less than 2 symbols or 3 common candles, returns error
+func GetCorrelationFrame(symbols []string, limit, window int) (*CorrelationFrame, error) {
+	if len(symbols) < 2 {
+		return nil, errors.New("two or more symbols are needed")
+	}
+
+	times, closes := alignCloses(symbols, limit)
+	if len(times) < 3 {
+		return nil, errors.New("not enough common candles")
+	}
+
+	returns := [][]float64{}
+	for _, close := range closes {
+		returns = append(returns, analysis.Returns(close))
+	}
+
+	cframe := CorrelationFrame{
+		Symbols: symbols,
+		Times:   times[1:],
+		Matrix:  analysis.CorrelationMatrix(returns),
+		Window:  window,
+	}
+	for i := range symbols {
+		for j := i + 1; j < len(symbols); j++ {
+			cframe.Rolling = append(cframe.Rolling, RollingCorrelation{
+				A:      symbols[i],
+				B:      symbols[j],
+				Values: analysis.RollingCorrelation(returns[i], returns[j], window),
+			})
+		}
+	}
+
+	return &cframe, nil
+}
+
+// GetPairFrame returns hedge ratio and z-score of spread between y and x, if less than 3 common candles, returns error
+func GetPairFrame(y, x string, limit, window int) (*PairFrame, error) {
+	times, closes := alignCloses([]string{y, x}, limit)
+	if len(times) < 3 {
+		return nil, errors.New("not enough common candles")
+	}
+
+	ratio, intercept := analysis.HedgeRatio(closes[0], closes[1])
+	spread := analysis.Spread(closes[0], closes[1], ratio, intercept)
+
+	return &PairFrame{
+		Y:           y,
+		X:           x,
+		Correlation: analysis.Correlation(analysis.Returns(closes[0]), analysis.Returns(closes[1])),
+		HedgeRatio:  ratio,
+		Intercept:   intercept,
+		Window:      window,
+		Times:       times,
+		Spread:      spread,
+		ZScore:      analysis.ZScore(spread, window),
+	}, nil
+}
@@ -1,7 +1,11 @@
 package models
 
 import (
+	"math"
+
+	"github.com/jumpei00/gostocktrade/app/models/analysis"
 	"github.com/jumpei00/gostocktrade/app/models/indicator"
+	"github.com/jumpei00/gostocktrade/app/models/rule"
 	"github.com/markcheno/go-talib"
 	"github.com/sirupsen/logrus"
 )
@@ -12,6 +16,7 @@ type DataFrame struct {
 	*OptimizedParamFrame
 	*SignalFrame
 	*TradeFrame
+	*SeriesFrame
 }
 
 // NewDataFrame is constructor of DataFrame
@@ -25,8 +30,8 @@ func (dframe *DataFrame) AddCandleFrame(symbol string, limit int) {
 }
 
 // AddSignalFrame adds SignalFrame in DataFrame
-func (dframe *DataFrame) AddSignalFrame(symbol string, ema, bb, macd, rsi, willr bool) {
-	dframe.SignalFrame = GetSignalFrame(symbol, ema, bb, macd, rsi, willr)
+func (dframe *DataFrame) AddSignalFrame(symbol string, ema, bb, macd, rsi, willr, ichimoku, psar, obv, mfi, donchian, pattern, divergence, stoch, level bool) {
+	dframe.SignalFrame = GetSignalFrame(symbol, ema, bb, macd, rsi, willr, ichimoku, psar, obv, mfi, donchian, pattern, divergence, stoch, level)
 }
 
 // AddOptimizedParamFrame adds OptimizedParamFrame in DataFrame
@@ -39,9 +44,15 @@ func (dframe *DataFrame) AddTradeFrame(symbol string) {
 	dframe.TradeFrame = GetTradeState(symbol)
 }
 
+// AddSeriesFrame adds SeriesFrame in DataFrame
+func (dframe *DataFrame) AddSeriesFrame(symbol string, limit int) {
+	dframe.SeriesFrame = GetSeriesFrame(symbol, limit)
+}
+
 // SignalFrame is dataframe of SignalEvents
 type SignalFrame struct {
 	Signals *SignalEvents `json:"signals,omitempty"`
+	Filter  *EntryFilter  `json:"filter,omitempty"`
 }
 
 // OptimizedParamFrame is optimized params data frame
@@ -58,6 +69,18 @@ type TradeFrame struct {
 type CandleFrame struct {
 	Symbol  string   `json:"symbol,omitempty"`
 	Candles []Candle `json:"candles,omitempty"`
+	// entryFilters is whether buy is allowed at each day, the key is json key of strategy,
+	// "" is for all strategies, and no key means no filter
+	entryFilters map[string][]bool
+	// sizing is position sizing of performance, nil means one share of each signal
+	sizing     *Sizing
+	sizingAtr  []float64
+	sizingDays map[int64]int
+	// entryOrders is limit or stop order of buy, the key is json key of strategy, and no key means buy at close
+	entryOrders map[string]EntryOrder
+	// dividends are of total-return performance, and dividendMode is credit or reinvest
+	dividends    []exDividend
+	dividendMode string
 }
 
 // Opens is open prices of candles
@@ -105,6 +128,32 @@ func (cframe *CandleFrame) Volumes() []float64 {
 	return volume
 }
 
+// ruleData converts candles to price series used for custom rule
+func (cframe *CandleFrame) ruleData() *rule.Data {
+	return &rule.Data{
+		Opens:   cframe.Opens(),
+		Highs:   cframe.Highs(),
+		Lows:    cframe.Lows(),
+		Closes:  cframe.Closes(),
+		Volumes: cframe.Volumes(),
+	}
+}
+
+// ema returns EMA of closes
+func (cframe *CandleFrame) ema(period int) []float64 {
+	return talib.Ema(cframe.Closes(), period)
+}
+
+// sma returns SMA of closes
+func (cframe *CandleFrame) sma(period int) []float64 {
+	return talib.Sma(cframe.Closes(), period)
+}
+
+// rsi returns RSI of closes
+func (cframe *CandleFrame) rsi(period int) []float64 {
+	return talib.Rsi(cframe.Closes(), period)
+}
+
 // following, using for backtest
 func (cframe *CandleFrame) optimizeEma(
 	lowShort, highShort, lowLong, highLong int) (bestPerformance float64, bestShort, bestLong int) {
@@ -121,7 +170,7 @@ func (cframe *CandleFrame) optimizeEma(
 				continue
 			}
 
-			profit = signals.Profit()
+			profit = cframe.profit(signals)
 			if bestPerformance < profit {
 				bestPerformance = profit
 				bestShort = short
@@ -148,16 +197,18 @@ func (cframe *CandleFrame) backtestEma(startDay, short int, long int, lastSignal
 		signals.EmaSignals = append(signals.EmaSignals, *lastSignal)
 	}
 
-	shortEma := talib.Ema(cframe.Closes(), short)
-	longEma := talib.Ema(cframe.Closes(), long)
+	shortEma := cframe.ema(short)
+	longEma := cframe.ema(long)
 
 	for day := startDay; day < lenCandles; day++ {
 		if day < short || day < long {
 			continue
 		}
 
-		if shortEma[day-1] < longEma[day-1] && shortEma[day] >= longEma[day] {
-			signals.Buy(cframe.Symbol, candles[day].Time, candles[day].Close)
+		if shortEma[day-1] < longEma[day-1] && shortEma[day] >= longEma[day] && cframe.canEntry("ema", day) {
+			if entryTime, entryPrice, ok := cframe.entry("ema", day, nil); ok {
+				signals.Buy(cframe.Symbol, entryTime, entryPrice)
+			}
 		}
 
 		if shortEma[day-1] > longEma[day-1] && shortEma[day] <= longEma[day] {
@@ -182,7 +233,7 @@ func (cframe *CandleFrame) optimizeBB(
 			if signals == nil {
 				continue
 			}
-			profit = signals.Profit()
+			profit = cframe.profit(signals)
 			if bestPerformance < profit {
 				bestPerformance = profit
 				bestN = n
@@ -216,8 +267,10 @@ func (cframe *CandleFrame) backtestBB(startDay, N int, K float64, lastSignal *in
 			continue
 		}
 
-		if candles[day-1].Close < lowBand[day-1] && candles[day].Close >= lowBand[day] {
-			signals.Buy(cframe.Symbol, candles[day].Time, candles[day].Close)
+		if candles[day-1].Close < lowBand[day-1] && candles[day].Close >= lowBand[day] && cframe.canEntry("bb", day) {
+			if entryTime, entryPrice, ok := cframe.entry("bb", day, map[string]float64{REFBBLOWER: lowBand[day]}); ok {
+				signals.Buy(cframe.Symbol, entryTime, entryPrice)
+			}
 		}
 
 		if candles[day-1].Close > upBand[day-1] && candles[day].Close <= upBand[day] {
@@ -244,7 +297,7 @@ func (cframe *CandleFrame) optimizeMacd(
 				if signals == nil {
 					continue
 				}
-				profit = signals.Profit()
+				profit = cframe.profit(signals)
 				if bestPerformance < profit {
 					bestPerformance = profit
 					bestFast = fast
@@ -279,8 +332,10 @@ func (cframe *CandleFrame) backtestMacd(startDay, fast, slow, signal int, lastSi
 	for day := startDay; day < lenCandles; day++ {
 		if macd[day] < 0 && macdSignal[day] < 0 &&
 			macd[day-1] < macdSignal[day-1] &&
-			macd[day] >= macdSignal[day] {
-			signals.Buy(cframe.Symbol, candles[day].Time, candles[day].Close)
+			macd[day] >= macdSignal[day] && cframe.canEntry("macd", day) {
+			if entryTime, entryPrice, ok := cframe.entry("macd", day, nil); ok {
+				signals.Buy(cframe.Symbol, entryTime, entryPrice)
+			}
 		}
 
 		if macd[day] > 0 && macdSignal[day] > 0 &&
@@ -310,7 +365,7 @@ func (cframe *CandleFrame) optimizeRsi(
 				if signals == nil {
 					continue
 				}
-				profit = signals.Profit()
+				profit = cframe.profit(signals)
 				if bestPerformance < profit {
 					bestPerformance = profit
 					bestPeriod = peirod
@@ -339,15 +394,17 @@ func (cframe *CandleFrame) backtestRsi(startDay, period int, buyThread, sellThre
 		signals.RsiSignals = append(signals.RsiSignals, *lastSignal)
 	}
 
-	rsi := talib.Rsi(cframe.Closes(), period)
+	rsi := cframe.rsi(period)
 
 	for day := startDay; day < lenCandles; day++ {
 		if rsi[day-1] == 0 || rsi[day-1] == 100 {
 			continue
 		}
 
-		if rsi[day-1] < buyThread && rsi[day] >= buyThread {
-			signals.Buy(cframe.Symbol, candles[day].Time, candles[day].Close)
+		if rsi[day-1] < buyThread && rsi[day] >= buyThread && cframe.canEntry("rsi", day) {
+			if entryTime, entryPrice, ok := cframe.entry("rsi", day, nil); ok {
+				signals.Buy(cframe.Symbol, entryTime, entryPrice)
+			}
 		}
 
 		if rsi[day-1] > sellThread && rsi[day] <= sellThread {
@@ -375,7 +432,7 @@ func (cframe *CandleFrame) optimizeWillr(
 				if signals == nil {
 					continue
 				}
-				profit = signals.Profit()
+				profit = cframe.profit(signals)
 				if bestPerformance < profit {
 					bestPerformance = profit
 					bestPeriod = period
@@ -411,8 +468,10 @@ func (cframe *CandleFrame) backtestWillr(startDay, period int, buyThread, sellTh
 			continue
 		}
 
-		if willr[day-1] < buyThread && willr[day] >= buyThread {
-			signals.Buy(cframe.Symbol, candles[day].Time, candles[day].Close)
+		if willr[day-1] < buyThread && willr[day] >= buyThread && cframe.canEntry("willr", day) {
+			if entryTime, entryPrice, ok := cframe.entry("willr", day, nil); ok {
+				signals.Buy(cframe.Symbol, entryTime, entryPrice)
+			}
 		}
 
 		if willr[day-1] > sellThread && willr[day] <= sellThread {
@@ -422,3 +481,685 @@ func (cframe *CandleFrame) backtestWillr(startDay, period int, buyThread, sellTh
 
 	return &signals
 }
+
+func (cframe *CandleFrame) optimizeIchimoku(
+	lowTenkan, highTenkan, lowKijun, highKijun, lowSenkou, highSenkou int) (bestPerformance float64, bestTenkan, bestKijun, bestSenkou int) {
+	logrus.Infof("Ichimoku backtest start: paramas -> %v, %v, %v %v, %v, %v", lowTenkan, highTenkan, lowKijun, highKijun, lowSenkou, highSenkou)
+
+	profit := 0.0
+	bestTenkan = 9
+	bestKijun = 26
+	bestSenkou = 52
+
+	for tenkan := lowTenkan; tenkan <= highTenkan; tenkan++ {
+		for kijun := lowKijun; kijun <= highKijun; kijun++ {
+			for senkou := lowSenkou; senkou <= highSenkou; senkou++ {
+				// lines must be tenkan < kijun < senkou
+				if tenkan >= kijun || kijun >= senkou {
+					continue
+				}
+
+				signals := cframe.backtestIchimoku(1, tenkan, kijun, senkou, nil)
+				if signals == nil {
+					continue
+				}
+				profit = cframe.profit(signals)
+				if bestPerformance < profit {
+					bestPerformance = profit
+					bestTenkan = tenkan
+					bestKijun = kijun
+					bestSenkou = senkou
+				}
+			}
+		}
+	}
+
+	logrus.Infof("Ichimoku backtest end: results -> %v, %v, %v %v", bestPerformance, bestTenkan, bestKijun, bestSenkou)
+	return bestPerformance, bestTenkan, bestKijun, bestSenkou
+}
+
+func (cframe *CandleFrame) backtestIchimoku(startDay, tenkan, kijun, senkou int, lastSignal *indicator.IchimokuSignal) *indicator.IchimokuSignals {
+	candles := cframe.Candles
+	lenCandles := len(candles)
+
+	if tenkan >= lenCandles || kijun >= lenCandles || senkou+kijun >= lenCandles {
+		return nil
+	}
+
+	signals := indicator.IchimokuSignals{}
+	// using at SignalTest
+	if lastSignal != nil {
+		signals.IchimokuSignals = append(signals.IchimokuSignals, *lastSignal)
+	}
+
+	tenkanSen, kijunSen, senkouA, senkouB, _ := indicator.Ichimoku(
+		cframe.Highs(), cframe.Lows(), cframe.Closes(), tenkan, kijun, senkou)
+
+	for day := startDay; day < lenCandles; day++ {
+		// cloud is ready after senkou + kijun
+		if day < senkou+kijun {
+			continue
+		}
+
+		cloudTop := math.Max(senkouA[day], senkouB[day])
+		cloudBottom := math.Min(senkouA[day], senkouB[day])
+
+		if tenkanSen[day-1] < kijunSen[day-1] && tenkanSen[day] >= kijunSen[day] &&
+			candles[day].Close > cloudTop && cframe.canEntry("ichimoku", day) {
+			if entryTime, entryPrice, ok := cframe.entry("ichimoku", day, nil); ok {
+				signals.Buy(cframe.Symbol, entryTime, entryPrice)
+			}
+		}
+
+		if tenkanSen[day-1] > kijunSen[day-1] && tenkanSen[day] <= kijunSen[day] &&
+			candles[day].Close < cloudBottom {
+			signals.Sell(cframe.Symbol, candles[day].Time, candles[day].Close)
+		}
+	}
+
+	return &signals
+}
+
+func (cframe *CandleFrame) optimizePsar(
+	lowStep, highStep, lowMax, highMax float64) (bestPerformance float64, bestStep, bestMax float64) {
+	logrus.Infof("Psar backtest start: paramas -> %v, %v, %v %v", lowStep, highStep, lowMax, highMax)
+
+	profit := 0.0
+	bestStep = 0.02
+	bestMax = 0.2
+
+	// 1e-9 is margin for error of adding 0.01
+	for step := lowStep; step <= highStep+1e-9; step += 0.01 {
+		for max := lowMax; max <= highMax+1e-9; max += 0.01 {
+			// acceleration can not be over maximum
+			if step > max {
+				continue
+			}
+
+			signals := cframe.backtestPsar(1, step, max, nil)
+			if signals == nil {
+				continue
+			}
+			profit = cframe.profit(signals)
+			if bestPerformance < profit {
+				bestPerformance = profit
+				bestStep = step
+				bestMax = max
+			}
+		}
+	}
+
+	logrus.Infof("Psar backtest end: results -> %v, %v, %v", bestPerformance, bestStep, bestMax)
+	return bestPerformance, bestStep, bestMax
+}
+
+func (cframe *CandleFrame) backtestPsar(startDay int, step, max float64, lastSignal *indicator.PsarSignal) *indicator.PsarSignals {
+	candles := cframe.Candles
+	lenCandles := len(candles)
+
+	if lenCandles < 3 || step <= 0 || max <= 0 {
+		return nil
+	}
+
+	signals := indicator.PsarSignals{}
+	// using at SignalTest
+	if lastSignal != nil {
+		signals.PsarSignals = append(signals.PsarSignals, *lastSignal)
+	}
+
+	sar := talib.Sar(cframe.Highs(), cframe.Lows(), step, max)
+
+	for day := startDay; day < lenCandles; day++ {
+		// first sar is not calculated
+		if day < 2 {
+			continue
+		}
+
+		// sar flips below the price, stop short and reverse to long
+		if sar[day-1] > candles[day-1].Close && sar[day] < candles[day].Close {
+			signals.Buy(cframe.Symbol, candles[day].Time, candles[day].Close)
+		}
+
+		// sar flips above the price, stop long and reverse to short
+		if sar[day-1] < candles[day-1].Close && sar[day] > candles[day].Close {
+			signals.Sell(cframe.Symbol, candles[day].Time, candles[day].Close)
+		}
+	}
+
+	return &signals
+}
+
+func (cframe *CandleFrame) optimizeObv(lowPeriod, highPeriod int) (bestPerformance float64, bestPeriod int) {
+	logrus.Infof("Obv backtest start: paramas -> %v, %v", lowPeriod, highPeriod)
+
+	profit := 0.0
+	bestPeriod = 20
+
+	for period := lowPeriod; period <= highPeriod; period++ {
+		signals := cframe.backtestObv(1, period, nil)
+		if signals == nil {
+			continue
+		}
+		profit = cframe.profit(signals)
+		if bestPerformance < profit {
+			bestPerformance = profit
+			bestPeriod = period
+		}
+	}
+
+	logrus.Infof("Obv backtest end: results -> %v, %v", bestPerformance, bestPeriod)
+	return bestPerformance, bestPeriod
+}
+
+func (cframe *CandleFrame) backtestObv(startDay, period int, lastSignal *indicator.ObvSignal) *indicator.ObvSignals {
+	candles := cframe.Candles
+	lenCandles := len(candles)
+
+	if period <= 1 || period >= lenCandles {
+		return nil
+	}
+
+	signals := indicator.ObvSignals{}
+	// using at SignalTest
+	if lastSignal != nil {
+		signals.ObvSignals = append(signals.ObvSignals, *lastSignal)
+	}
+
+	obv := talib.Obv(cframe.Closes(), cframe.Volumes())
+	obvEma := talib.Ema(obv, period)
+
+	for day := startDay; day < lenCandles; day++ {
+		// ema is not calculated
+		if day < period {
+			continue
+		}
+
+		// volume flows in, obv crosses over its ema
+		if obv[day-1] < obvEma[day-1] && obv[day] >= obvEma[day] && cframe.canEntry("obv", day) {
+			if entryTime, entryPrice, ok := cframe.entry("obv", day, nil); ok {
+				signals.Buy(cframe.Symbol, entryTime, entryPrice)
+			}
+		}
+
+		// volume flows out, obv crosses under its ema
+		if obv[day-1] > obvEma[day-1] && obv[day] <= obvEma[day] {
+			signals.Sell(cframe.Symbol, candles[day].Time, candles[day].Close)
+		}
+	}
+
+	return &signals
+}
+
+func (cframe *CandleFrame) optimizeMfi(
+	lowPeriod, highPeriod int,
+	lowBuyThread, highBuyThread, lowSellThread, highSellThread float64) (bestPerformance float64, bestPeriod int, bestBuyThread, bestSellThread float64) {
+	logrus.Infof("Mfi backtest start: paramas -> %v, %v, %v %v, %v, %v", lowPeriod, highPeriod, lowBuyThread, highBuyThread, lowSellThread, highSellThread)
+
+	profit := 0.0
+	bestPeriod = 14
+	bestBuyThread = 20.0
+	bestSellThread = 80.0
+
+	for period := lowPeriod; period <= highPeriod; period++ {
+		for buyThread := lowBuyThread; buyThread <= highBuyThread; buyThread++ {
+			for sellThread := lowSellThread; sellThread <= highSellThread; sellThread++ {
+				signals := cframe.backtestMfi(1, period, buyThread, sellThread, nil)
+				if signals == nil {
+					continue
+				}
+				profit = cframe.profit(signals)
+				if bestPerformance < profit {
+					bestPerformance = profit
+					bestPeriod = period
+					bestBuyThread = buyThread
+					bestSellThread = sellThread
+				}
+			}
+		}
+	}
+
+	logrus.Infof("Mfi backtest end: results -> %v, %v, %v %v", bestPerformance, bestPeriod, bestBuyThread, bestSellThread)
+	return bestPerformance, bestPeriod, bestBuyThread, bestSellThread
+}
+
+func (cframe *CandleFrame) backtestMfi(startDay, period int, buyThread, sellThread float64, lastSignal *indicator.MfiSignal) *indicator.MfiSignals {
+	candles := cframe.Candles
+	lenCandles := len(candles)
+
+	if period <= 1 || period >= lenCandles {
+		return nil
+	}
+
+	signals := indicator.MfiSignals{}
+	// using at SignalTest
+	if lastSignal != nil {
+		signals.MfiSignals = append(signals.MfiSignals, *lastSignal)
+	}
+
+	mfi := talib.Mfi(cframe.Highs(), cframe.Lows(), cframe.Closes(), cframe.Volumes(), period)
+
+	for day := startDay; day < lenCandles; day++ {
+		// mfi is not calculated
+		if day <= period {
+			continue
+		}
+
+		if mfi[day-1] < buyThread && mfi[day] >= buyThread && cframe.canEntry("mfi", day) {
+			if entryTime, entryPrice, ok := cframe.entry("mfi", day, nil); ok {
+				signals.Buy(cframe.Symbol, entryTime, entryPrice)
+			}
+		}
+
+		if mfi[day-1] > sellThread && mfi[day] <= sellThread {
+			signals.Sell(cframe.Symbol, candles[day].Time, candles[day].Close)
+		}
+	}
+
+	return &signals
+}
+
+func (cframe *CandleFrame) optimizeDonchian(
+	lowEntry, highEntry, lowExit, highExit, lowAtr, highAtr int) (bestPerformance float64, bestEntry, bestExit, bestAtr int) {
+	logrus.Infof("Donchian backtest start: paramas -> %v, %v, %v %v, %v, %v", lowEntry, highEntry, lowExit, highExit, lowAtr, highAtr)
+
+	profit := 0.0
+	bestEntry = 20
+	bestExit = 10
+	bestAtr = 20
+
+	for entry := lowEntry; entry <= highEntry; entry++ {
+		for exit := lowExit; exit <= highExit; exit++ {
+			for atr := lowAtr; atr <= highAtr; atr++ {
+				signals := cframe.backtestDonchian(1, entry, exit, atr, nil)
+				if signals == nil {
+					continue
+				}
+				profit = cframe.profit(signals)
+				if bestPerformance < profit {
+					bestPerformance = profit
+					bestEntry = entry
+					bestExit = exit
+					bestAtr = atr
+				}
+			}
+		}
+	}
+
+	logrus.Infof("Donchian backtest end: results -> %v, %v, %v %v", bestPerformance, bestEntry, bestExit, bestAtr)
+	return bestPerformance, bestEntry, bestExit, bestAtr
+}
+
+func (cframe *CandleFrame) backtestDonchian(startDay, entry, exit, atrPeriod int, lastSignal *indicator.DonchianSignal) *indicator.DonchianSignals {
+	candles := cframe.Candles
+	lenCandles := len(candles)
+
+	if entry <= 1 || exit <= 1 || atrPeriod <= 1 ||
+		entry >= lenCandles || exit >= lenCandles || atrPeriod >= lenCandles {
+		return nil
+	}
+
+	signals := indicator.DonchianSignals{}
+	// using at SignalTest
+	if lastSignal != nil {
+		signals.DonchianSignals = append(signals.DonchianSignals, *lastSignal)
+	}
+
+	// channel of the day is calculated by the days before
+	upper := talib.Max(cframe.Highs(), entry)
+	lower := talib.Min(cframe.Lows(), exit)
+	atr := talib.Atr(cframe.Highs(), cframe.Lows(), cframe.Closes(), atrPeriod)
+
+	for day := startDay; day < lenCandles; day++ {
+		if day <= entry || day <= exit || day <= atrPeriod {
+			continue
+		}
+
+		// breakout of entry-day high
+		if candles[day].Close > upper[day-1] && cframe.canEntry("donchian", day) {
+			if entryTime, entryPrice, ok := cframe.entry("donchian", day, nil); ok {
+				signals.Buy(cframe.Symbol, entryTime, entryPrice,
+					indicator.DonchianSize(entryPrice, atr[day]))
+			}
+		}
+
+		// breakdown of exit-day low
+		if candles[day].Close < lower[day-1] {
+			signals.Sell(cframe.Symbol, candles[day].Time, candles[day].Close)
+		}
+	}
+
+	return &signals
+}
+
+func (cframe *CandleFrame) optimizePattern(lowTrend, highTrend int) (bestPerformance float64, bestTrend int) {
+	logrus.Infof("Pattern backtest start: paramas -> %v, %v", lowTrend, highTrend)
+
+	profit := 0.0
+	bestTrend = 5
+
+	for trend := lowTrend; trend <= highTrend; trend++ {
+		signals := cframe.backtestPattern(1, trend, nil)
+		if signals == nil {
+			continue
+		}
+		profit = cframe.profit(signals)
+		if bestPerformance < profit {
+			bestPerformance = profit
+			bestTrend = trend
+		}
+	}
+
+	logrus.Infof("Pattern backtest end: results -> %v, %v", bestPerformance, bestTrend)
+	return bestPerformance, bestTrend
+}
+
+func (cframe *CandleFrame) backtestPattern(startDay, trend int, lastSignal *indicator.PatternSignal) *indicator.PatternSignals {
+	candles := cframe.Candles
+	lenCandles := len(candles)
+
+	if trend < 1 || trend >= lenCandles {
+		return nil
+	}
+
+	signals := indicator.PatternSignals{}
+	// using at SignalTest
+	if lastSignal != nil {
+		signals.PatternSignals = append(signals.PatternSignals, *lastSignal)
+	}
+
+	directions := indicator.PatternDirections(cframe.patterns(trend), lenCandles)
+
+	for day := startDay; day < lenCandles; day++ {
+		if directions[day] > 0 && cframe.canEntry("pattern", day) {
+			if entryTime, entryPrice, ok := cframe.entry("pattern", day, nil); ok {
+				signals.Buy(cframe.Symbol, entryTime, entryPrice)
+			}
+		}
+
+		if directions[day] < 0 {
+			signals.Sell(cframe.Symbol, candles[day].Time, candles[day].Close)
+		}
+	}
+
+	return &signals
+}
+
+// oscillator returns RSI(14) or MACD(12, 26, 9) line used for divergence
+func (cframe *CandleFrame) oscillator(source string) []float64 {
+	switch source {
+	case indicator.DIVERGENCERSI:
+		return talib.Rsi(cframe.Closes(), 14)
+	case indicator.DIVERGENCEMACD:
+		macd, _, _ := talib.Macd(cframe.Closes(), 12, 26, 9)
+		return macd
+	}
+	return nil
+}
+
+func (cframe *CandleFrame) optimizeDivergence(lowWidth, highWidth int) (bestPerformance float64, bestWidth int, bestSource string) {
+	logrus.Infof("Divergence backtest start: paramas -> %v, %v", lowWidth, highWidth)
+
+	profit := 0.0
+	bestWidth = 5
+	bestSource = indicator.DIVERGENCERSI
+
+	for _, source := range []string{indicator.DIVERGENCERSI, indicator.DIVERGENCEMACD} {
+		for width := lowWidth; width <= highWidth; width++ {
+			signals := cframe.backtestDivergence(1, width, source, nil)
+			if signals == nil {
+				continue
+			}
+			profit = cframe.profit(signals)
+			if bestPerformance < profit {
+				bestPerformance = profit
+				bestWidth = width
+				bestSource = source
+			}
+		}
+	}
+
+	logrus.Infof("Divergence backtest end: results -> %v, %v, %v", bestPerformance, bestWidth, bestSource)
+	return bestPerformance, bestWidth, bestSource
+}
+
+func (cframe *CandleFrame) backtestDivergence(startDay, width int, source string, lastSignal *indicator.DivergenceSignal) *indicator.DivergenceSignals {
+	candles := cframe.Candles
+	lenCandles := len(candles)
+
+	oscillator := cframe.oscillator(source)
+	if width < 1 || width*2 >= lenCandles || oscillator == nil {
+		return nil
+	}
+
+	signals := indicator.DivergenceSignals{}
+	// using at SignalTest
+	if lastSignal != nil {
+		signals.DivergenceSignals = append(signals.DivergenceSignals, *lastSignal)
+	}
+
+	// signal is at the day when divergence is confirmed, not at the swing point
+	for _, divergence := range indicator.Divergences(cframe.Highs(), cframe.Lows(), oscillator, width) {
+		day := divergence.Index
+		if day < startDay {
+			continue
+		}
+
+		if divergence.Direction == indicator.BULLISH && cframe.canEntry("divergence", day) {
+			if entryTime, entryPrice, ok := cframe.entry("divergence", day, nil); ok {
+				signals.Buy(cframe.Symbol, entryTime, entryPrice)
+			}
+		}
+
+		if divergence.Direction == indicator.BEARISH {
+			signals.Sell(cframe.Symbol, candles[day].Time, candles[day].Close)
+		}
+	}
+
+	return &signals
+}
+
+func (cframe *CandleFrame) optimizeStoch(
+	lowK, highK, lowD, highD, lowSlowing, highSlowing int,
+	lowBuyThread, highBuyThread, lowSellThread, highSellThread float64) (bestPerformance float64, bestFast bool, bestK, bestD, bestSlowing int, bestBuyThread, bestSellThread float64) {
+	logrus.Infof("Stoch backtest start: paramas -> %v, %v, %v, %v, %v, %v, %v, %v, %v, %v",
+		lowK, highK, lowD, highD, lowSlowing, highSlowing, lowBuyThread, highBuyThread, lowSellThread, highSellThread)
+
+	profit := 0.0
+	bestFast = false
+	bestK = 14
+	bestD = 3
+	bestSlowing = 3
+	bestBuyThread = 20.0
+	bestSellThread = 80.0
+
+	for _, fast := range []bool{true, false} {
+		for k := lowK; k <= highK; k++ {
+			for d := lowD; d <= highD; d++ {
+				for slowing := lowSlowing; slowing <= highSlowing; slowing++ {
+					// fast stochastic does not use slowing
+					if fast && slowing != lowSlowing {
+						continue
+					}
+					for buyThread := lowBuyThread; buyThread <= highBuyThread; buyThread++ {
+						for sellThread := lowSellThread; sellThread <= highSellThread; sellThread++ {
+							signals := cframe.backtestStoch(1, fast, k, d, slowing, buyThread, sellThread, nil)
+							if signals == nil {
+								continue
+							}
+							profit = cframe.profit(signals)
+							if bestPerformance < profit {
+								bestPerformance = profit
+								bestFast = fast
+								bestK = k
+								bestD = d
+								bestSlowing = slowing
+								bestBuyThread = buyThread
+								bestSellThread = sellThread
+							}
+						}
+					}
+				}
+			}
+		}
+	}
+
+	logrus.Infof("Stoch backtest end: results -> %v, %v, %v, %v, %v, %v, %v",
+		bestPerformance, bestFast, bestK, bestD, bestSlowing, bestBuyThread, bestSellThread)
+	return bestPerformance, bestFast, bestK, bestD, bestSlowing, bestBuyThread, bestSellThread
+}
+
+// stoch returns %K and %D of fast or slow stochastic, and days of which values are not calculated
+func (cframe *CandleFrame) stoch(fast bool, k, d, slowing int) (kLine, dLine []float64, lookback int) {
+	if fast {
+		kLine, dLine = talib.StochF(cframe.Highs(), cframe.Lows(), cframe.Closes(), k, d, talib.SMA)
+		return kLine, dLine, k + d - 2
+	}
+	kLine, dLine = talib.Stoch(cframe.Highs(), cframe.Lows(), cframe.Closes(), k, slowing, talib.SMA, d, talib.SMA)
+	return kLine, dLine, k + slowing + d - 3
+}
+
+func (cframe *CandleFrame) backtestStoch(
+	startDay int, fast bool, k, d, slowing int, buyThread, sellThread float64, lastSignal *indicator.StochSignal) *indicator.StochSignals {
+	candles := cframe.Candles
+	lenCandles := len(candles)
+
+	if k < 1 || d < 1 || slowing < 1 || k+d+slowing >= lenCandles {
+		return nil
+	}
+
+	signals := indicator.StochSignals{}
+	// using at SignalTest
+	if lastSignal != nil {
+		signals.StochSignals = append(signals.StochSignals, *lastSignal)
+	}
+
+	kLine, dLine, lookback := cframe.stoch(fast, k, d, slowing)
+
+	for day := startDay; day < lenCandles; day++ {
+		if day <= lookback {
+			continue
+		}
+
+		// %K crosses over %D in oversold zone
+		if kLine[day-1] < dLine[day-1] && kLine[day] >= dLine[day] &&
+			kLine[day] < buyThread && dLine[day] < buyThread && cframe.canEntry("stoch", day) {
+			if entryTime, entryPrice, ok := cframe.entry("stoch", day, nil); ok {
+				signals.Buy(cframe.Symbol, entryTime, entryPrice)
+			}
+		}
+
+		// %K crosses under %D in overbought zone
+		if kLine[day-1] > dLine[day-1] && kLine[day] <= dLine[day] &&
+			kLine[day] > sellThread && dLine[day] > sellThread {
+			signals.Sell(cframe.Symbol, candles[day].Time, candles[day].Close)
+		}
+	}
+
+	return &signals
+}
+
+func (cframe *CandleFrame) optimizeLevel(lowLookback, highLookback int) (bestPerformance float64, bestLookback int) {
+	logrus.Infof("Level backtest start: paramas -> %v, %v", lowLookback, highLookback)
+
+	profit := 0.0
+	bestLookback = 60
+
+	for lookback := lowLookback; lookback <= highLookback; lookback++ {
+		signals := cframe.backtestLevel(1, lookback, nil)
+		if signals == nil {
+			continue
+		}
+		profit = cframe.profit(signals)
+		if bestPerformance < profit {
+			bestPerformance = profit
+			bestLookback = lookback
+		}
+	}
+
+	logrus.Infof("Level backtest end: results -> %v, %v", bestPerformance, bestLookback)
+	return bestPerformance, bestLookback
+}
+
+func (cframe *CandleFrame) backtestLevel(startDay, lookback int, lastSignal *indicator.LevelSignal) *indicator.LevelSignals {
+	candles := cframe.Candles
+	lenCandles := len(candles)
+
+	if lookback <= levelWidth*2 || lookback >= lenCandles {
+		return nil
+	}
+
+	signals := indicator.LevelSignals{}
+	// using at SignalTest
+	if lastSignal != nil {
+		signals.LevelSignals = append(signals.LevelSignals, *lastSignal)
+	}
+
+	opens, highs, lows, closes := cframe.Opens(), cframe.Highs(), cframe.Lows(), cframe.Closes()
+
+	for day := startDay; day < lenCandles; day++ {
+		if day < lookback {
+			continue
+		}
+
+		// levels are detected from the previous days, not including the day
+		support, resistance := nearestLevels(analysis.PivotLevels(
+			highs[day-lookback:day], lows[day-lookback:day], closes[day-lookback:day], levelWidth, levelTolerance))
+
+		// close breaks above resistance
+		breakout := resistance != 0 && closes[day] > resistance
+		// low touches support, and close bounces up
+		bounce := support != 0 && lows[day] <= support*(1+levelTolerance) && closes[day] > support && closes[day] > opens[day]
+		if (breakout || bounce) && cframe.canEntry("level", day) {
+			if entryTime, entryPrice, ok := cframe.entry("level", day, nil); ok {
+				signals.Buy(cframe.Symbol, entryTime, entryPrice)
+			}
+		}
+
+		// close breaks below support
+		if support != 0 && closes[day] < support {
+			signals.Sell(cframe.Symbol, candles[day].Time, candles[day].Close)
+		}
+	}
+
+	return &signals
+}
+
+func (cframe *CandleFrame) backtestRule(
+	startDay int, name string, strategy *rule.Strategy, lastSignal *indicator.RuleSignal) (*indicator.RuleSignals, error) {
+	candles := cframe.Candles
+	lenCandles := len(candles)
+
+	signals := indicator.RuleSignals{Name: name}
+	// using at SignalTest
+	if lastSignal != nil {
+		signals.RuleSignals = append(signals.RuleSignals, *lastSignal)
+	}
+
+	buy, sell, err := strategy.Signals(cframe.ruleData())
+	if err != nil {
+		return nil, err
+	}
+
+	if startDay < 1 {
+		startDay = 1
+	}
+
+	for day := startDay; day < lenCandles; day++ {
+		// already tested before last signal
+		if lastSignal != nil && candles[day].Time <= lastSignal.Time {
+			continue
+		}
+
+		if buy[day] && cframe.canEntry("rule", day) {
+			if entryTime, entryPrice, ok := cframe.entry("rule", day, nil); ok {
+				signals.Buy(cframe.Symbol, entryTime, entryPrice)
+			}
+		}
+
+		if sell[day] {
+			signals.Sell(cframe.Symbol, candles[day].Time, candles[day].Close)
+		}
+	}
+
+	return &signals, nil
+}
@@ -13,17 +13,27 @@ func (suite *ModelsTestSuite) TestDataFrame() {
 	suite.Nil(dframe.OptimizedParamFrame)
 	suite.Nil(dframe.SignalFrame)
 	suite.Nil(dframe.TradeFrame)
+	suite.Nil(dframe.SeriesFrame)
 
 	dframe.AddCandleFrame("VOO", 100)
 	suite.Equal("VOO", dframe.CandleFrame.Symbol)
 	suite.Len(dframe.CandleFrame.Candles, 100)
 
-	dframe.AddSignalFrame("VOO", true, false, false, false, false)
+	dframe.AddSignalFrame("VOO", true, false, false, false, false, false, false, false, false, false, false, false, false, false)
 	suite.NotEmpty(dframe.SignalFrame.Signals.EmaSignals)
 	suite.Empty(dframe.SignalFrame.Signals.BBSignals)
 	suite.Empty(dframe.SignalFrame.Signals.MacdSignals)
 	suite.Empty(dframe.SignalFrame.Signals.RsiSignals)
 	suite.Empty(dframe.SignalFrame.Signals.WillrSignals)
+	suite.Empty(dframe.SignalFrame.Signals.IchimokuSignals)
+	suite.Empty(dframe.SignalFrame.Signals.PsarSignals)
+	suite.Empty(dframe.SignalFrame.Signals.ObvSignals)
+	suite.Empty(dframe.SignalFrame.Signals.MfiSignals)
+	suite.Empty(dframe.SignalFrame.Signals.DonchianSignals)
+	suite.Empty(dframe.SignalFrame.Signals.PatternSignals)
+	suite.Empty(dframe.SignalFrame.Signals.DivergenceSignals)
+	suite.Empty(dframe.SignalFrame.Signals.StochSignals)
+	suite.Empty(dframe.SignalFrame.Signals.LevelSignals)
 
 	dframe.AddOptimizedParamFrame("DAMY")
 	suite.Nil(dframe.OptimizedParamFrame.Param)
@@ -32,4 +42,46 @@ func (suite *ModelsTestSuite) TestDataFrame() {
 
 	dframe.AddTradeFrame("VOO")
 	suite.NotEmpty(dframe.TradeFrame.Trade)
-}
\ No newline at end of file
+
+	dframe.AddSeriesFrame("VOO", 100)
+	suite.Len(dframe.SeriesFrame.Ichimoku.Tenkan, 100)
+	suite.Len(dframe.SeriesFrame.Ichimoku.SenkouA, 100)
+	suite.Len(dframe.SeriesFrame.Psar, 100)
+	suite.Len(dframe.SeriesFrame.Obv.Obv, 100)
+	suite.Len(dframe.SeriesFrame.Mfi, 100)
+	for _, pattern := range dframe.SeriesFrame.Patterns {
+		suite.NotEmpty(pattern.Name)
+	}
+	for _, divergence := range dframe.SeriesFrame.Divergences {
+		suite.True(divergence.FromTime < divergence.ToTime)
+		suite.True(divergence.ToTime < divergence.Time)
+	}
+	suite.Len(dframe.SeriesFrame.Adx.Adx, 100)
+}
+
+func (suite *ModelsTestSuite) TestSeriesFrameOmittedParams() {
+	models.DeleteBacktestResult("VOO")
+	defaults := models.GetSeriesFrame("VOO", 100)
+
+	omitted := backTestParam
+	omitted.Ichimoku = nil
+	omitted.Psar = nil
+	omitted.Obv = nil
+	omitted.Mfi = nil
+	omitted.Pattern = nil
+	omitted.Divergence = nil
+	op, err := omitted.BackTest()
+	suite.Nil(err)
+	suite.Nil(op.CreateBacktestResult())
+
+	// series of strategies omitted at backtest are drawn by default params
+	sframe := models.GetSeriesFrame("VOO", 100)
+	suite.Equal(defaults.Ichimoku, sframe.Ichimoku)
+	suite.Equal(defaults.Psar, sframe.Psar)
+	suite.Equal(defaults.Obv, sframe.Obv)
+	suite.Equal(defaults.Mfi, sframe.Mfi)
+	suite.Equal(defaults.Patterns, sframe.Patterns)
+	suite.Equal(defaults.Divergences, sframe.Divergences)
+
+	models.DeleteBacktestResult("VOO")
+}
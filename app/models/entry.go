@@ -0,0 +1,86 @@
+package models
+
+import (
+	"github.com/sirupsen/logrus"
+
+	"github.com/jumpei00/gostocktrade/app/models/indicator"
+)
+
+// reference price of entry order
+const (
+	// REFCLOSE is close of the signal day
+	REFCLOSE = "close"
+	// REFBBLOWER is lower band of BollingerBand at the signal day, only for bb strategy
+	REFBBLOWER = "bb_lower"
+)
+
+// EntryOrder is setting that buy of strategy is limit or stop order placed at the signal day,
+// which is valid for the next candle only, instead of buying at the close of the signal day,
+// price of order is reference with offset percent, below for limit and above for stop
+type EntryOrder struct {
+	ID        int     `gorm:"primary_key" json:"-"`
+	Symbol    string  `json:"-"`
+	Strategy  string  `json:"strategy"`
+	Type      string  `json:"type"`
+	Reference string  `json:"reference"`
+	Offset    float64 `json:"offset"`
+}
+
+// price returns price of order from reference prices of the signal day, close is used when reference is not found
+func (eo *EntryOrder) price(references map[string]float64) float64 {
+	price, ok := references[eo.Reference]
+	if !ok {
+		price = references[REFCLOSE]
+	}
+
+	if eo.Type == indicator.LIMITORDER {
+		return price * (1 - eo.Offset/100)
+	}
+	return price * (1 + eo.Offset/100)
+}
+
+// setEntryOrders sets entry orders of strategies, and removes previous ones
+func (cframe *CandleFrame) setEntryOrders(entryOrders []EntryOrder) {
+	cframe.entryOrders = map[string]EntryOrder{}
+	for _, entryOrder := range entryOrders {
+		if _, ok := filteredStrategies[entryOrder.Strategy]; !ok {
+			logrus.Warnf("entry order, unknown strategy: %v", entryOrder.Strategy)
+			continue
+		}
+		if entryOrder.Type != indicator.LIMITORDER && entryOrder.Type != indicator.STOPORDER {
+			logrus.Warnf("entry order, unknown type: %v", entryOrder.Type)
+			continue
+		}
+		if entryOrder.Reference == "" {
+			entryOrder.Reference = REFCLOSE
+		}
+		if entryOrder.Reference != REFCLOSE && !(entryOrder.Reference == REFBBLOWER && entryOrder.Strategy == "bb") {
+			logrus.Warnf("entry order, unknown reference: %v", entryOrder.Reference)
+			continue
+		}
+		cframe.entryOrders[entryOrder.Strategy] = entryOrder
+	}
+}
+
+// entry returns time and price of buy of strategy signaled at the day, references are prices of the day
+// other than close, such as lower band, when entry order is set, it is filled in the next candle,
+// otherwise buy is at the close of the day, and false means not filled
+func (cframe *CandleFrame) entry(strategy string, day int, references map[string]float64) (int64, float64, bool) {
+	candles := cframe.Candles
+	entryOrder, ok := cframe.entryOrders[strategy]
+	if !ok {
+		return candles[day].Time, candles[day].Close, true
+	}
+	if day+1 >= len(candles) {
+		return 0, 0, false
+	}
+
+	if references == nil {
+		references = map[string]float64{}
+	}
+	references[REFCLOSE] = candles[day].Close
+
+	next := candles[day+1]
+	price, ok := indicator.FillPrice(entryOrder.Type, indicator.BUY, entryOrder.price(references), next.Open, next.High, next.Low)
+	return next.Time, price, ok
+}
@@ -0,0 +1,47 @@
+package models_test
+
+import (
+	"github.com/jumpei00/gostocktrade/app/models"
+	"github.com/jumpei00/gostocktrade/app/models/indicator"
+)
+
+func (suite *ModelsTestSuite) TestEntryOrders() {
+	ordered := backTestParam
+	ordered.EntryOrders = []models.EntryOrder{
+		{Strategy: "bb", Type: indicator.LIMITORDER, Reference: models.REFBBLOWER},
+		{Strategy: "rsi", Type: indicator.STOPORDER, Offset: 0.5},
+		{Strategy: "ema", Type: indicator.LIMITORDER, Reference: models.REFBBLOWER},
+	}
+	op, _ := ordered.BackTest()
+	suite.Len(op.EntryOrders, 2)
+	suite.Equal(models.REFCLOSE, op.EntryOrders[1].Reference)
+
+	// psar can not be ordered
+	psar := backTestParam
+	psar.EntryOrders = []models.EntryOrder{{Strategy: "psar", Type: indicator.LIMITORDER}}
+	_, err := psar.BackTest()
+	suite.NotNil(err)
+
+	// limit is filled at the open or lower, and stop is filled at the open or higher, within the candle
+	candles := map[int64]models.Candle{}
+	for _, candle := range models.GetCandleFrame("VOO", 500).Candles {
+		candles[candle.Time] = candle
+	}
+	for _, signal := range op.BBSignals {
+		if signal.Action == indicator.BUY {
+			suite.LessOrEqual(signal.Price, candles[signal.Time].Open)
+			suite.GreaterOrEqual(signal.Price, candles[signal.Time].Low)
+		}
+	}
+	for _, signal := range op.RsiSignals {
+		if signal.Action == indicator.BUY {
+			suite.GreaterOrEqual(signal.Price, candles[signal.Time].Open)
+			suite.LessOrEqual(signal.Price, candles[signal.Time].High)
+		}
+	}
+
+	suite.Nil(op.CreateBacktestResult())
+	suite.Len(models.GetOptimizedParamFrame("VOO").Param.EntryOrders, 2)
+
+	models.DeleteBacktestResult("VOO")
+}
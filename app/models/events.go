@@ -10,39 +10,75 @@ import (
 
 // Trade represents whether today is "buy" or "sell" or "no trade"
 type Trade struct {
-	LastEmaTrade   string `json:"last_ema"`
-	IsEmaToday     bool   `json:"today_ema"`
-	LastBBTrade    string `json:"last_bb"`
-	IsBBToday      bool   `json:"today_bb"`
-	LastMacdTrade  string `json:"last_macd"`
-	IsMacdToday    bool   `json:"today_macd"`
-	LastRsiTrade   string `json:"last_rsi"`
-	IsRsiToday     bool   `json:"today_rsi"`
-	LastWillrTrade string `json:"last_willr"`
-	IsWillrToday   bool   `json:"today_willr"`
+	LastEmaTrade        string `json:"last_ema"`
+	IsEmaToday          bool   `json:"today_ema"`
+	LastBBTrade         string `json:"last_bb"`
+	IsBBToday           bool   `json:"today_bb"`
+	LastMacdTrade       string `json:"last_macd"`
+	IsMacdToday         bool   `json:"today_macd"`
+	LastRsiTrade        string `json:"last_rsi"`
+	IsRsiToday          bool   `json:"today_rsi"`
+	LastWillrTrade      string `json:"last_willr"`
+	IsWillrToday        bool   `json:"today_willr"`
+	LastIchimokuTrade   string `json:"last_ichimoku"`
+	IsIchimokuToday     bool   `json:"today_ichimoku"`
+	LastPsarTrade       string `json:"last_psar"`
+	IsPsarToday         bool   `json:"today_psar"`
+	LastObvTrade        string `json:"last_obv"`
+	IsObvToday          bool   `json:"today_obv"`
+	LastMfiTrade        string `json:"last_mfi"`
+	IsMfiToday          bool   `json:"today_mfi"`
+	LastDonchianTrade   string `json:"last_donchian"`
+	IsDonchianToday     bool   `json:"today_donchian"`
+	LastPatternTrade    string `json:"last_pattern"`
+	IsPatternToday      bool   `json:"today_pattern"`
+	LastDivergenceTrade string `json:"last_divergence"`
+	IsDivergenceToday   bool   `json:"today_divergence"`
+	LastStochTrade      string `json:"last_stoch"`
+	IsStochToday        bool   `json:"today_stoch"`
+	LastLevelTrade      string `json:"last_level"`
+	IsLevelToday        bool   `json:"today_level"`
 }
 
 // GetTradeState returns Trade, after examining today trading,
 // the symbol argument is certainly the same to the candle symbol
 func GetTradeState(symbol string) *TradeFrame {
-	signalEvents := GetSignalFrame(symbol, true, true, true, true, true).Signals
-	lastCandleTime, err := LastCandleTime()
+	signalEvents := GetSignalFrame(symbol, true, true, true, true, true, true, true, true, true, true, true, true, true, true).Signals
+	lastCandleTime, err := LastCandleTime(symbol)
 	if err != nil {
 		logrus.Warnf("last candle get error: %v", err)
 		return &TradeFrame{Trade: nil}
 	}
 
 	trade := Trade{
-		LastEmaTrade:   indicator.NOTRADE,
-		IsEmaToday:     false,
-		LastBBTrade:    indicator.NOTRADE,
-		IsBBToday:      false,
-		LastMacdTrade:  indicator.NOTRADE,
-		IsMacdToday:    false,
-		LastRsiTrade:   indicator.NOTRADE,
-		IsRsiToday:     false,
-		LastWillrTrade: indicator.NOTRADE,
-		IsWillrToday:   false,
+		LastEmaTrade:        indicator.NOTRADE,
+		IsEmaToday:          false,
+		LastBBTrade:         indicator.NOTRADE,
+		IsBBToday:           false,
+		LastMacdTrade:       indicator.NOTRADE,
+		IsMacdToday:         false,
+		LastRsiTrade:        indicator.NOTRADE,
+		IsRsiToday:          false,
+		LastWillrTrade:      indicator.NOTRADE,
+		IsWillrToday:        false,
+		LastIchimokuTrade:   indicator.NOTRADE,
+		IsIchimokuToday:     false,
+		LastPsarTrade:       indicator.NOTRADE,
+		IsPsarToday:         false,
+		LastObvTrade:        indicator.NOTRADE,
+		IsObvToday:          false,
+		LastMfiTrade:        indicator.NOTRADE,
+		IsMfiToday:          false,
+		LastDonchianTrade:   indicator.NOTRADE,
+		IsDonchianToday:     false,
+		LastPatternTrade:    indicator.NOTRADE,
+		IsPatternToday:      false,
+		LastDivergenceTrade: indicator.NOTRADE,
+		IsDivergenceToday:   false,
+		LastStochTrade:      indicator.NOTRADE,
+		IsStochToday:        false,
+		LastLevelTrade:      indicator.NOTRADE,
+		IsLevelToday:        false,
 	}
 
 	for signal, time := range signalEvents.LastSignalTimes() {
@@ -71,6 +107,42 @@ func GetTradeState(symbol string) *TradeFrame {
 			lastWillr := signalEvents.WillrSignals[len(signalEvents.WillrSignals)-1]
 			trade.LastWillrTrade = lastWillr.Action
 			trade.IsWillrToday = (lastWillr.Time == lastCandleTime)
+		case "ichimokuTime":
+			lastIchimoku := signalEvents.IchimokuSignals[len(signalEvents.IchimokuSignals)-1]
+			trade.LastIchimokuTrade = lastIchimoku.Action
+			trade.IsIchimokuToday = (lastIchimoku.Time == lastCandleTime)
+		case "psarTime":
+			lastPsar := signalEvents.PsarSignals[len(signalEvents.PsarSignals)-1]
+			trade.LastPsarTrade = lastPsar.Action
+			trade.IsPsarToday = (lastPsar.Time == lastCandleTime)
+		case "obvTime":
+			lastObv := signalEvents.ObvSignals[len(signalEvents.ObvSignals)-1]
+			trade.LastObvTrade = lastObv.Action
+			trade.IsObvToday = (lastObv.Time == lastCandleTime)
+		case "mfiTime":
+			lastMfi := signalEvents.MfiSignals[len(signalEvents.MfiSignals)-1]
+			trade.LastMfiTrade = lastMfi.Action
+			trade.IsMfiToday = (lastMfi.Time == lastCandleTime)
+		case "donchianTime":
+			lastDonchian := signalEvents.DonchianSignals[len(signalEvents.DonchianSignals)-1]
+			trade.LastDonchianTrade = lastDonchian.Action
+			trade.IsDonchianToday = (lastDonchian.Time == lastCandleTime)
+		case "patternTime":
+			lastPattern := signalEvents.PatternSignals[len(signalEvents.PatternSignals)-1]
+			trade.LastPatternTrade = lastPattern.Action
+			trade.IsPatternToday = (lastPattern.Time == lastCandleTime)
+		case "divergenceTime":
+			lastDivergence := signalEvents.DivergenceSignals[len(signalEvents.DivergenceSignals)-1]
+			trade.LastDivergenceTrade = lastDivergence.Action
+			trade.IsDivergenceToday = (lastDivergence.Time == lastCandleTime)
+		case "stochTime":
+			lastStoch := signalEvents.StochSignals[len(signalEvents.StochSignals)-1]
+			trade.LastStochTrade = lastStoch.Action
+			trade.IsStochToday = (lastStoch.Time == lastCandleTime)
+		case "levelTime":
+			lastLevel := signalEvents.LevelSignals[len(signalEvents.LevelSignals)-1]
+			trade.LastLevelTrade = lastLevel.Action
+			trade.IsLevelToday = (lastLevel.Time == lastCandleTime)
 		}
 	}
 
@@ -79,16 +151,25 @@ func GetTradeState(symbol string) *TradeFrame {
 
 // SignalEvents stores a part of signal
 type SignalEvents struct {
-	EmaSignals   []indicator.EmaSignal   `json:"ema_signals,omitempty"`
-	BBSignals    []indicator.BBSignal    `json:"bb_signals,omitempty"`
-	MacdSignals  []indicator.MacdSignal  `json:"macd_signals,omitempty"`
-	RsiSignals   []indicator.RsiSignal   `json:"rsi_signals,omitempty"`
-	WillrSignals []indicator.WillrSignal `json:"willr_signals,omitempty"`
+	EmaSignals        []indicator.EmaSignal        `json:"ema_signals,omitempty"`
+	BBSignals         []indicator.BBSignal         `json:"bb_signals,omitempty"`
+	MacdSignals       []indicator.MacdSignal       `json:"macd_signals,omitempty"`
+	RsiSignals        []indicator.RsiSignal        `json:"rsi_signals,omitempty"`
+	WillrSignals      []indicator.WillrSignal      `json:"willr_signals,omitempty"`
+	IchimokuSignals   []indicator.IchimokuSignal   `json:"ichimoku_signals,omitempty"`
+	PsarSignals       []indicator.PsarSignal       `json:"psar_signals,omitempty"`
+	ObvSignals        []indicator.ObvSignal        `json:"obv_signals,omitempty"`
+	MfiSignals        []indicator.MfiSignal        `json:"mfi_signals,omitempty"`
+	DonchianSignals   []indicator.DonchianSignal   `json:"donchian_signals,omitempty"`
+	PatternSignals    []indicator.PatternSignal    `json:"pattern_signals,omitempty"`
+	DivergenceSignals []indicator.DivergenceSignal `json:"divergence_signals,omitempty"`
+	StochSignals      []indicator.StochSignal      `json:"stoch_signals,omitempty"`
+	LevelSignals      []indicator.LevelSignal      `json:"level_signals,omitempty"`
 }
 
 // GetSignalFrame returns SignalFrame including a part of signal events
-func GetSignalFrame(symbol string, ema, bb, macd, rsi, willr bool) *SignalFrame {
-	if !(ema || bb || macd || rsi || willr) {
+func GetSignalFrame(symbol string, ema, bb, macd, rsi, willr, ichimoku, psar, obv, mfi, donchian, pattern, divergence, stoch, level bool) *SignalFrame {
+	if !(ema || bb || macd || rsi || willr || ichimoku || psar || obv || mfi || donchian || pattern || divergence || stoch || level) {
 		return &SignalFrame{Signals: nil}
 	}
 
@@ -124,22 +205,82 @@ func GetSignalFrame(symbol string, ema, bb, macd, rsi, willr bool) *SignalFrame
 		signalEvents.WillrSignals = willrSignals
 	}
 
-	return &SignalFrame{Signals: signalEvents}
+	if ichimoku {
+		ichimokuSignals := []indicator.IchimokuSignal{}
+		DB.Where("Symbol = ?", symbol).Find(&ichimokuSignals)
+		signalEvents.IchimokuSignals = ichimokuSignals
+	}
+
+	if psar {
+		psarSignals := []indicator.PsarSignal{}
+		DB.Where("Symbol = ?", symbol).Find(&psarSignals)
+		signalEvents.PsarSignals = psarSignals
+	}
+
+	if obv {
+		obvSignals := []indicator.ObvSignal{}
+		DB.Where("Symbol = ?", symbol).Find(&obvSignals)
+		signalEvents.ObvSignals = obvSignals
+	}
+
+	if mfi {
+		mfiSignals := []indicator.MfiSignal{}
+		DB.Where("Symbol = ?", symbol).Find(&mfiSignals)
+		signalEvents.MfiSignals = mfiSignals
+	}
+
+	if donchian {
+		donchianSignals := []indicator.DonchianSignal{}
+		DB.Where("Symbol = ?", symbol).Find(&donchianSignals)
+		signalEvents.DonchianSignals = donchianSignals
+	}
+
+	if pattern {
+		patternSignals := []indicator.PatternSignal{}
+		DB.Where("Symbol = ?", symbol).Find(&patternSignals)
+		signalEvents.PatternSignals = patternSignals
+	}
+
+	if divergence {
+		divergenceSignals := []indicator.DivergenceSignal{}
+		DB.Where("Symbol = ?", symbol).Find(&divergenceSignals)
+		signalEvents.DivergenceSignals = divergenceSignals
+	}
+
+	if stoch {
+		stochSignals := []indicator.StochSignal{}
+		DB.Where("Symbol = ?", symbol).Find(&stochSignals)
+		signalEvents.StochSignals = stochSignals
+	}
+
+	if level {
+		levelSignals := []indicator.LevelSignal{}
+		DB.Where("Symbol = ?", symbol).Find(&levelSignals)
+		signalEvents.LevelSignals = levelSignals
+	}
+
+	return &SignalFrame{Signals: signalEvents, Filter: GetEntryFilter(symbol)}
 }
 
 // SignalTest execute backtest from last signal day, in other words, update each signal event
 func SignalTest(symbol string, period int) bool {
+	cframe := GetCandleFrame(symbol, period)
 	opParam := GetOptimizedParamFrame(symbol).Param
+	if opParam != nil {
+		cframe.setEntryFilters(opParam)
+	}
+	// custom strategies are updated regardless of optimized params
+	cframe.ruleSignalTest()
+
 	if opParam == nil {
 		return false
 	}
 
-	cframe := GetCandleFrame(symbol, period)
-	signalEvents := GetSignalFrame(symbol, true, true, true, true, true).Signals
+	signalEvents := GetSignalFrame(symbol, true, true, true, true, true, true, true, true, true, true, true, true, true, true).Signals
 
 	firstTime := cframe.Candles[0].ID
 	for k, v := range signalEvents.LastSignalTimes() {
-		machID, err := MatchTime(v)
+		machID, err := MatchTime(symbol, v)
 		if err != nil {
 			continue
 		}
@@ -166,6 +307,43 @@ func SignalTest(symbol string, period int) bool {
 			willrSignals := cframe.backtestWillr(
 				startDay, opParam.WillrPeriod, opParam.WillrBuyThread, opParam.WillrSellThread, &signalEvents.WillrSignals[len(signalEvents.WillrSignals)-1]).WillrSignals
 			DB.Model(opParam).Association("WillrSignals").Append(willrSignals)
+		case "ichimokuTime":
+			ichimokuSignals := cframe.backtestIchimoku(
+				startDay, opParam.IchimokuTenkan, opParam.IchimokuKijun, opParam.IchimokuSenkou, &signalEvents.IchimokuSignals[len(signalEvents.IchimokuSignals)-1]).IchimokuSignals
+			DB.Model(opParam).Association("IchimokuSignals").Append(ichimokuSignals)
+		case "psarTime":
+			psarSignals := cframe.backtestPsar(
+				startDay, opParam.PsarStep, opParam.PsarMax, &signalEvents.PsarSignals[len(signalEvents.PsarSignals)-1]).PsarSignals
+			DB.Model(opParam).Association("PsarSignals").Append(psarSignals)
+		case "obvTime":
+			obvSignals := cframe.backtestObv(
+				startDay, opParam.ObvPeriod, &signalEvents.ObvSignals[len(signalEvents.ObvSignals)-1]).ObvSignals
+			DB.Model(opParam).Association("ObvSignals").Append(obvSignals)
+		case "mfiTime":
+			mfiSignals := cframe.backtestMfi(
+				startDay, opParam.MfiPeriod, opParam.MfiBuyThread, opParam.MfiSellThread, &signalEvents.MfiSignals[len(signalEvents.MfiSignals)-1]).MfiSignals
+			DB.Model(opParam).Association("MfiSignals").Append(mfiSignals)
+		case "donchianTime":
+			donchianSignals := cframe.backtestDonchian(
+				startDay, opParam.DonchianEntry, opParam.DonchianExit, opParam.DonchianAtr, &signalEvents.DonchianSignals[len(signalEvents.DonchianSignals)-1]).DonchianSignals
+			DB.Model(opParam).Association("DonchianSignals").Append(donchianSignals)
+		case "patternTime":
+			patternSignals := cframe.backtestPattern(
+				startDay, opParam.PatternTrend, &signalEvents.PatternSignals[len(signalEvents.PatternSignals)-1]).PatternSignals
+			DB.Model(opParam).Association("PatternSignals").Append(patternSignals)
+		case "divergenceTime":
+			divergenceSignals := cframe.backtestDivergence(
+				startDay, opParam.DivergenceWidth, opParam.DivergenceSource, &signalEvents.DivergenceSignals[len(signalEvents.DivergenceSignals)-1]).DivergenceSignals
+			DB.Model(opParam).Association("DivergenceSignals").Append(divergenceSignals)
+		case "stochTime":
+			stochSignals := cframe.backtestStoch(
+				startDay, opParam.StochFast, opParam.StochK, opParam.StochD, opParam.StochSlowing, opParam.StochBuyThread, opParam.StochSellThread,
+				&signalEvents.StochSignals[len(signalEvents.StochSignals)-1]).StochSignals
+			DB.Model(opParam).Association("StochSignals").Append(stochSignals)
+		case "levelTime":
+			levelSignals := cframe.backtestLevel(
+				startDay, opParam.LevelLookback, &signalEvents.LevelSignals[len(signalEvents.LevelSignals)-1]).LevelSignals
+			DB.Model(opParam).Association("LevelSignals").Append(levelSignals)
 
 		}
 	}
@@ -188,11 +366,20 @@ func (sg *SignalEvents) LastSignalTimes() map[string]int64 {
 	}
 
 	return map[string]int64{
-		"emaTime":   lastTimes[0],
-		"bbTime":    lastTimes[1],
-		"macdTime":  lastTimes[2],
-		"rsiTime":   lastTimes[3],
-		"willrTime": lastTimes[4],
+		"emaTime":        lastTimes[0],
+		"bbTime":         lastTimes[1],
+		"macdTime":       lastTimes[2],
+		"rsiTime":        lastTimes[3],
+		"willrTime":      lastTimes[4],
+		"ichimokuTime":   lastTimes[5],
+		"psarTime":       lastTimes[6],
+		"obvTime":        lastTimes[7],
+		"mfiTime":        lastTimes[8],
+		"donchianTime":   lastTimes[9],
+		"patternTime":    lastTimes[10],
+		"divergenceTime": lastTimes[11],
+		"stochTime":      lastTimes[12],
+		"levelTime":      lastTimes[13],
 	}
 
 }
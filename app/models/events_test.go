@@ -10,7 +10,7 @@ func (suite *ModelsTestSuite) TestGetTradeState() {
 	suite.Op.CreateBacktestResult()
 
 	// As test, create Ema signal due to doing same time to last candle time
-	lastTime, _ := models.LastCandleTime()
+	lastTime, _ := models.LastCandleTime("VOO")
 	emaSignal := indicator.EmaSignal{
 		Symbol: "VOO",
 		Time:   lastTime,
@@ -37,20 +37,28 @@ func (suite *ModelsTestSuite) TestGetSignalFrame() {
 	// initializing
 	suite.Op.CreateBacktestResult()
 
-	signalFrame := models.GetSignalFrame("VOO", false, false, false, false, false)
+	signalFrame := models.GetSignalFrame("VOO", false, false, false, false, false, false, false, false, false, false, false, false, false, false)
 	suite.Nil(signalFrame.Signals)
 
-	signalFrame = models.GetSignalFrame("VOO", true, false, false, false, false)
+	signalFrame = models.GetSignalFrame("VOO", true, false, false, false, false, false, false, false, false, false, false, false, false, false)
 	suite.NotNil(signalFrame.Signals)
 	suite.NotEmpty(signalFrame.Signals.EmaSignals)
 
-	signalFrame = models.GetSignalFrame("VOO", true, true, true, true, true)
+	signalFrame = models.GetSignalFrame("VOO", true, true, true, true, true, true, true, true, true, true, true, true, true, true)
 	suite.NotNil(signalFrame.Signals)
 	suite.NotEmpty(signalFrame.Signals.EmaSignals)
 	suite.NotEmpty(signalFrame.Signals.BBSignals)
 	suite.NotEmpty(signalFrame.Signals.MacdSignals)
 	suite.NotEmpty(signalFrame.Signals.RsiSignals)
 	suite.NotEmpty(signalFrame.Signals.WillrSignals)
+	suite.NotEmpty(signalFrame.Signals.IchimokuSignals)
+	suite.NotEmpty(signalFrame.Signals.PsarSignals)
+	suite.NotEmpty(signalFrame.Signals.ObvSignals)
+	suite.NotEmpty(signalFrame.Signals.MfiSignals)
+	suite.NotEmpty(signalFrame.Signals.DonchianSignals)
+	suite.NotEmpty(signalFrame.Signals.PatternSignals)
+	suite.NotEmpty(signalFrame.Signals.DivergenceSignals)
+	suite.NotEmpty(signalFrame.Signals.StochSignals)
 
 	models.DeleteBacktestResult("VOO")
 }
@@ -65,9 +73,9 @@ func (suite *ModelsTestSuite) TestSignalTest() {
 	suite.True(models.SignalTest("VOO", 500))
 
 	trades := models.GetTradeState("VOO").Trade
-	signals := models.GetSignalFrame("VOO", true, true, true, true, true).Signals
+	signals := models.GetSignalFrame("VOO", true, true, true, true, true, true, true, true, true, true, true, true, true, true).Signals
 	signalsLastTime := signals.LastSignalTimes()
-	candleLastTime, _ := models.LastCandleTime()
+	candleLastTime, _ := models.LastCandleTime("VOO")
 	if len(signals.EmaSignals) != 0 {
 		suite.Equal(signals.EmaSignals[len(signals.EmaSignals)-1].Action, trades.LastEmaTrade)
 		suite.Equal(signalsLastTime["emaTime"] == candleLastTime, trades.IsEmaToday)
@@ -108,6 +116,78 @@ func (suite *ModelsTestSuite) TestSignalTest() {
 		suite.False(trades.IsWillrToday)
 	}
 
+	if len(signals.IchimokuSignals) != 0 {
+		suite.Equal(signals.IchimokuSignals[len(signals.IchimokuSignals)-1].Action, trades.LastIchimokuTrade)
+		suite.Equal(signalsLastTime["ichimokuTime"] == candleLastTime, trades.IsIchimokuToday)
+	} else {
+		suite.Equal(indicator.NOTRADE, trades.LastIchimokuTrade)
+		suite.False(trades.IsIchimokuToday)
+	}
+
+	if len(signals.PsarSignals) != 0 {
+		suite.Equal(signals.PsarSignals[len(signals.PsarSignals)-1].Action, trades.LastPsarTrade)
+		suite.Equal(signalsLastTime["psarTime"] == candleLastTime, trades.IsPsarToday)
+	} else {
+		suite.Equal(indicator.NOTRADE, trades.LastPsarTrade)
+		suite.False(trades.IsPsarToday)
+	}
+
+	if len(signals.ObvSignals) != 0 {
+		suite.Equal(signals.ObvSignals[len(signals.ObvSignals)-1].Action, trades.LastObvTrade)
+		suite.Equal(signalsLastTime["obvTime"] == candleLastTime, trades.IsObvToday)
+	} else {
+		suite.Equal(indicator.NOTRADE, trades.LastObvTrade)
+		suite.False(trades.IsObvToday)
+	}
+
+	if len(signals.MfiSignals) != 0 {
+		suite.Equal(signals.MfiSignals[len(signals.MfiSignals)-1].Action, trades.LastMfiTrade)
+		suite.Equal(signalsLastTime["mfiTime"] == candleLastTime, trades.IsMfiToday)
+	} else {
+		suite.Equal(indicator.NOTRADE, trades.LastMfiTrade)
+		suite.False(trades.IsMfiToday)
+	}
+
+	if len(signals.DonchianSignals) != 0 {
+		suite.Equal(signals.DonchianSignals[len(signals.DonchianSignals)-1].Action, trades.LastDonchianTrade)
+		suite.Equal(signalsLastTime["donchianTime"] == candleLastTime, trades.IsDonchianToday)
+	} else {
+		suite.Equal(indicator.NOTRADE, trades.LastDonchianTrade)
+		suite.False(trades.IsDonchianToday)
+	}
+
+	if len(signals.PatternSignals) != 0 {
+		suite.Equal(signals.PatternSignals[len(signals.PatternSignals)-1].Action, trades.LastPatternTrade)
+		suite.Equal(signalsLastTime["patternTime"] == candleLastTime, trades.IsPatternToday)
+	} else {
+		suite.Equal(indicator.NOTRADE, trades.LastPatternTrade)
+		suite.False(trades.IsPatternToday)
+	}
+
+	if len(signals.DivergenceSignals) != 0 {
+		suite.Equal(signals.DivergenceSignals[len(signals.DivergenceSignals)-1].Action, trades.LastDivergenceTrade)
+		suite.Equal(signalsLastTime["divergenceTime"] == candleLastTime, trades.IsDivergenceToday)
+	} else {
+		suite.Equal(indicator.NOTRADE, trades.LastDivergenceTrade)
+		suite.False(trades.IsDivergenceToday)
+	}
+
+	if len(signals.StochSignals) != 0 {
+		suite.Equal(signals.StochSignals[len(signals.StochSignals)-1].Action, trades.LastStochTrade)
+		suite.Equal(signalsLastTime["stochTime"] == candleLastTime, trades.IsStochToday)
+	} else {
+		suite.Equal(indicator.NOTRADE, trades.LastStochTrade)
+		suite.False(trades.IsStochToday)
+	}
+
+	if len(signals.LevelSignals) != 0 {
+		suite.Equal(signals.LevelSignals[len(signals.LevelSignals)-1].Action, trades.LastLevelTrade)
+		suite.Equal(signalsLastTime["levelTime"] == candleLastTime, trades.IsLevelToday)
+	} else {
+		suite.Equal(indicator.NOTRADE, trades.LastLevelTrade)
+		suite.False(trades.IsLevelToday)
+	}
+
 	models.DeleteBacktestResult("VOO")
 }
 
@@ -115,7 +195,7 @@ func (suite *ModelsTestSuite) TestLastSignalTimes() {
 	// initializing
 	suite.Op.CreateBacktestResult()
 
-	signalEvents := models.GetSignalFrame("VOO", true, true, true, true, true).Signals
+	signalEvents := models.GetSignalFrame("VOO", true, true, true, true, true, true, true, true, true, true, true, true, true, true).Signals
 	lastTimeMap := signalEvents.LastSignalTimes()
 
 	suite.Equal(signalEvents.EmaSignals[len(signalEvents.EmaSignals)-1].Time, lastTimeMap["emaTime"])
@@ -123,6 +203,14 @@ func (suite *ModelsTestSuite) TestLastSignalTimes() {
 	suite.Equal(signalEvents.MacdSignals[len(signalEvents.MacdSignals)-1].Time, lastTimeMap["macdTime"])
 	suite.Equal(signalEvents.RsiSignals[len(signalEvents.RsiSignals)-1].Time, lastTimeMap["rsiTime"])
 	suite.Equal(signalEvents.WillrSignals[len(signalEvents.WillrSignals)-1].Time, lastTimeMap["willrTime"])
+	suite.Equal(signalEvents.IchimokuSignals[len(signalEvents.IchimokuSignals)-1].Time, lastTimeMap["ichimokuTime"])
+	suite.Equal(signalEvents.PsarSignals[len(signalEvents.PsarSignals)-1].Time, lastTimeMap["psarTime"])
+	suite.Equal(signalEvents.ObvSignals[len(signalEvents.ObvSignals)-1].Time, lastTimeMap["obvTime"])
+	suite.Equal(signalEvents.MfiSignals[len(signalEvents.MfiSignals)-1].Time, lastTimeMap["mfiTime"])
+	suite.Equal(signalEvents.DonchianSignals[len(signalEvents.DonchianSignals)-1].Time, lastTimeMap["donchianTime"])
+	suite.Equal(signalEvents.PatternSignals[len(signalEvents.PatternSignals)-1].Time, lastTimeMap["patternTime"])
+	suite.Equal(signalEvents.DivergenceSignals[len(signalEvents.DivergenceSignals)-1].Time, lastTimeMap["divergenceTime"])
+	suite.Equal(signalEvents.StochSignals[len(signalEvents.StochSignals)-1].Time, lastTimeMap["stochTime"])
 
 	models.DeleteBacktestResult("VOO")
 }
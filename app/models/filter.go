@@ -0,0 +1,175 @@
+package models
+
+import (
+	"strings"
+
+	"github.com/jumpei00/gostocktrade/app/models/indicator"
+	"github.com/markcheno/go-talib"
+	"github.com/sirupsen/logrus"
+)
+
+// filteredStrategies is strategies which buy can be filtered,
+// the key is json key used for BackTestParam and the value is prefix of fields of OptimizedParam,
+// rule is all custom strategies filtered by the optimized params of the symbol, which has no fields,
+// psar is not included, because psar is always in the market and buy also closes short
+var filteredStrategies = map[string]string{
+	"ema":        "Ema",
+	"bb":         "BB",
+	"macd":       "Macd",
+	"rsi":        "Rsi",
+	"willr":      "Willr",
+	"ichimoku":   "Ichimoku",
+	"obv":        "Obv",
+	"mfi":        "Mfi",
+	"donchian":   "Donchian",
+	"divergence": "Divergence",
+	"stoch":      "Stoch",
+	"level":      "Level",
+	"pattern":    "Pattern",
+	"rule":       "",
+}
+
+// EntryFilter is state of entry filters used for signals
+type EntryFilter struct {
+	Pattern       bool     `json:"pattern"`
+	AdxPeriod     int      `json:"adx_period,omitempty"`
+	AdxThread     float64  `json:"adx_thread,omitempty"`
+	AdxDI         bool     `json:"adx_di,omitempty"`
+	AdxStrategies []string `json:"adx_strategies,omitempty"`
+	// Trending is whether the last candle is in trending market by adx filter
+	Trending      bool           `json:"trending"`
+	Confirmations []Confirmation `json:"confirmations,omitempty"`
+}
+
+// adxCandles is number of candles used for judging current regime
+const adxCandles = 250
+
+// GetEntryFilter returns state of entry filters of optimized params, if no backtest, returns nil
+func GetEntryFilter(symbol string) *EntryFilter {
+	op := GetOptimizedParamFrame(symbol).Param
+	if op == nil {
+		return nil
+	}
+
+	filter := EntryFilter{Pattern: op.PatternFilter, Confirmations: op.Confirmations}
+	if op.AdxPeriod == 0 {
+		return &filter
+	}
+
+	filter.AdxPeriod = op.AdxPeriod
+	filter.AdxThread = op.AdxThread
+	filter.AdxDI = op.AdxDI
+	filter.AdxStrategies = splitStrategies(op.AdxStrategies)
+
+	cframe := GetCandleFrame(symbol, adxCandles)
+	if trending := cframe.adxRegime(op.AdxPeriod, op.AdxThread, op.AdxDI); len(trending) != 0 {
+		filter.Trending = trending[len(trending)-1]
+	}
+
+	return &filter
+}
+
+// canEntry judges whether buy of strategy is allowed at the day by entry filters
+func (cframe *CandleFrame) canEntry(strategy string, day int) bool {
+	for _, key := range []string{"", strategy} {
+		if filter, ok := cframe.entryFilters[key]; ok && !filter[day] {
+			return false
+		}
+	}
+	return true
+}
+
+// addEntryFilter adds filter for strategy, "" is for all strategies,
+// when strategy has already been filtered, buy is allowed only at the days both filters allow
+func (cframe *CandleFrame) addEntryFilter(strategy string, allowed []bool) {
+	if cframe.entryFilters == nil {
+		cframe.entryFilters = map[string][]bool{}
+	}
+	if current, ok := cframe.entryFilters[strategy]; ok {
+		for day := range allowed {
+			allowed[day] = allowed[day] && current[day]
+		}
+	}
+	cframe.entryFilters[strategy] = allowed
+}
+
+// resetEntryFilters removes all entry filters
+func (cframe *CandleFrame) resetEntryFilters() {
+	cframe.entryFilters = nil
+}
+
+// patterns returns candlestick patterns of candles
+func (cframe *CandleFrame) patterns(trend int) []indicator.Pattern {
+	return indicator.Patterns(cframe.Opens(), cframe.Highs(), cframe.Lows(), cframe.Closes(), trend)
+}
+
+// setPatternFilter allows buy only when bullish pattern is completed at the day or the day before
+func (cframe *CandleFrame) setPatternFilter(trend int) {
+	directions := indicator.PatternDirections(cframe.patterns(trend), len(cframe.Candles))
+	allowed := make([]bool, len(cframe.Candles))
+	for day := range directions {
+		allowed[day] = directions[day] > 0 || (day > 0 && directions[day-1] > 0)
+	}
+	cframe.addEntryFilter("", allowed)
+}
+
+// adxRegime returns whether each day is trending market,
+// that is, ADX is over thread, and +DI is over -DI if di is true
+func (cframe *CandleFrame) adxRegime(period int, thread float64, di bool) []bool {
+	trending := make([]bool, len(cframe.Candles))
+	if period < 2 || len(cframe.Candles) <= period*2 {
+		return trending
+	}
+
+	adx := talib.Adx(cframe.Highs(), cframe.Lows(), cframe.Closes(), period)
+	plusDI := talib.PlusDI(cframe.Highs(), cframe.Lows(), cframe.Closes(), period)
+	minusDI := talib.MinusDI(cframe.Highs(), cframe.Lows(), cframe.Closes(), period)
+
+	for day := range trending {
+		// adx is not calculated
+		if adx[day] == 0 {
+			continue
+		}
+		trending[day] = adx[day] >= thread && (!di || plusDI[day] > minusDI[day])
+	}
+
+	return trending
+}
+
+// setAdxFilter allows buy of strategies only in trending market,
+// when strategies is empty, all strategies are filtered
+func (cframe *CandleFrame) setAdxFilter(strategies []string, period int, thread float64, di bool) {
+	if len(strategies) == 0 {
+		cframe.addEntryFilter("", cframe.adxRegime(period, thread, di))
+		return
+	}
+
+	for _, strategy := range strategies {
+		if _, ok := filteredStrategies[strategy]; !ok {
+			logrus.Warnf("adx filter, unknown strategy: %v", strategy)
+			continue
+		}
+		cframe.addEntryFilter(strategy, cframe.adxRegime(period, thread, di))
+	}
+}
+
+// setEntryFilters sets entry filters and entry orders of optimized params, used for SignalTest
+func (cframe *CandleFrame) setEntryFilters(op *OptimizedParam) {
+	cframe.resetEntryFilters()
+	if op.PatternFilter {
+		cframe.setPatternFilter(op.PatternTrend)
+	}
+	if op.AdxPeriod != 0 {
+		cframe.setAdxFilter(splitStrategies(op.AdxStrategies), op.AdxPeriod, op.AdxThread, op.AdxDI)
+	}
+	cframe.setConfirmationFilter(op.Confirmations)
+	cframe.setEntryOrders(op.EntryOrders)
+}
+
+// splitStrategies splits comma separated strategies saved in OptimizedParam
+func splitStrategies(strategies string) []string {
+	if strategies == "" {
+		return []string{}
+	}
+	return strings.Split(strategies, ",")
+}
@@ -0,0 +1,12 @@
+package indicator
+
+// AdxBacktestParam represents some parameters used for regime filter by ADX,
+// buy of strategies is allowed only when ADX is over thread, and +DI is over -DI if di is true,
+// strategies are json keys of filtered strategies, empty means all strategies
+type AdxBacktestParam struct {
+	AdxPeriod     int      `json:"period"`
+	AdxThreadLow  float64  `json:"thread_low"`
+	AdxThreadHigh float64  `json:"thread_high"`
+	AdxDI         bool     `json:"di"`
+	AdxStrategies []string `json:"strategies"`
+}
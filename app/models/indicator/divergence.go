@@ -0,0 +1,242 @@
+package indicator
+
+// kinds of divergence
+const (
+	REGULAR = "Regular"
+	HIDDEN  = "Hidden"
+)
+
+// oscillators used for divergence
+const (
+	DIVERGENCERSI  = "rsi"
+	DIVERGENCEMACD = "macd"
+)
+
+// DivergenceBacktestParam represents some parameters used for backtest,
+// width is days of both sides to judge a swing point
+type DivergenceBacktestParam struct {
+	DivergenceWidthLow  int `json:"width_low"`
+	DivergenceWidthHigh int `json:"width_high"`
+}
+
+// Divergence is divergence between two swing points of price and oscillator,
+// From and To are indexes of price swing points,
+// Index is the day when the divergence is confirmed, that is, the day after To swing point is fixed
+type Divergence struct {
+	Index     int
+	From      int
+	To        int
+	Kind      string
+	Direction int
+}
+
+// SwingPoints returns indexes of swing highs and swing lows,
+// a swing high is the highest value in width days of both sides, a swing low is the lowest,
+// so the last width days can not be swing points
+func SwingPoints(values []float64, width int) (highs, lows []int) {
+	if width < 1 {
+		return highs, lows
+	}
+
+	for i := width; i+width < len(values); i++ {
+		isHigh, isLow := true, true
+		for j := i - width; j <= i+width; j++ {
+			if j == i {
+				continue
+			}
+			// for the same values, the first one is the swing point
+			if (j < i && values[j] >= values[i]) || (j > i && values[j] > values[i]) {
+				isHigh = false
+			}
+			if (j < i && values[j] <= values[i]) || (j > i && values[j] < values[i]) {
+				isLow = false
+			}
+		}
+		if isHigh {
+			highs = append(highs, i)
+		}
+		if isLow {
+			lows = append(lows, i)
+		}
+	}
+
+	return highs, lows
+}
+
+// Divergences finds divergences between price and oscillator at successive swing points,
+// swing highs of price are from high and swing lows are from low,
+// each of them is paired with the nearest swing point of oscillator within width days,
+// 0 of oscillator means no value
+//
+// regular bullish: price makes lower low, oscillator makes higher low
+// hidden bullish: price makes higher low, oscillator makes lower low
+// regular bearish: price makes higher high, oscillator makes lower high
+// hidden bearish: price makes lower high, oscillator makes higher high
+func Divergences(high, low, oscillator []float64, width int) []Divergence {
+	divergences := []Divergence{}
+
+	priceHighs, _ := SwingPoints(high, width)
+	_, priceLows := SwingPoints(low, width)
+	oscHighs, oscLows := SwingPoints(oscillator, width)
+
+	find := func(price []float64, priceSwings, oscSwings []int, direction int) {
+		before, beforeOsc := -1, -1
+		for _, p := range priceSwings {
+			q := nearest(oscSwings, p, width)
+			if q < 0 || oscillator[q] == 0 {
+				continue
+			}
+
+			if before >= 0 {
+				priceUp := price[p] > price[before]
+				oscUp := oscillator[q] > oscillator[beforeOsc]
+				priceDown := price[p] < price[before]
+				oscDown := oscillator[q] < oscillator[beforeOsc]
+
+				kind := ""
+				if direction == BULLISH {
+					if priceDown && oscUp {
+						kind = REGULAR
+					} else if priceUp && oscDown {
+						kind = HIDDEN
+					}
+				} else {
+					if priceUp && oscDown {
+						kind = REGULAR
+					} else if priceDown && oscUp {
+						kind = HIDDEN
+					}
+				}
+
+				if kind != "" {
+					confirmed := p
+					if q > confirmed {
+						confirmed = q
+					}
+					divergences = append(divergences, Divergence{
+						Index: confirmed + width, From: before, To: p, Kind: kind, Direction: direction})
+				}
+			}
+			before, beforeOsc = p, q
+		}
+	}
+	find(low, priceLows, oscLows, BULLISH)
+	find(high, priceHighs, oscHighs, BEARISH)
+
+	// in order of confirmed day
+	for i := 1; i < len(divergences); i++ {
+		for j := i; j > 0 && divergences[j].Index < divergences[j-1].Index; j-- {
+			divergences[j], divergences[j-1] = divergences[j-1], divergences[j]
+		}
+	}
+
+	return divergences
+}
+
+// nearest returns the nearest index of swings to i within width, if nothing, returns -1
+func nearest(swings []int, i, width int) int {
+	found := -1
+	for _, s := range swings {
+		distance := abs(s - i)
+		if distance > width {
+			continue
+		}
+		if found < 0 || distance < abs(found-i) {
+			found = s
+		}
+	}
+	return found
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
+
+// DivergenceSignals stores DivergenceSignal
+type DivergenceSignals struct {
+	DivergenceSignals []DivergenceSignal
+}
+
+// DivergenceSignal is signal results of backtest
+type DivergenceSignal struct {
+	ID     int     `gorm:"primary_key" json:"-"`
+	Symbol string  `json:"-"`
+	Time   int64   `json:"time"`
+	Price  float64 `json:"-"`
+	Action string  `json:"action"`
+}
+
+// Buy appends buy-signal to Signals, if can not buy, return false
+func (dv *DivergenceSignals) Buy(symbol string, time int64, price float64) bool {
+	if !(dv.CanBuy()) {
+		return false
+	}
+	dv.DivergenceSignals = append(dv.DivergenceSignals, DivergenceSignal{Symbol: symbol, Time: time, Price: price, Action: BUY})
+	return true
+}
+
+// CanBuy judges whether buy or not
+func (dv *DivergenceSignals) CanBuy() bool {
+	lenSignals := len(dv.DivergenceSignals)
+	// not buy or sell
+	if lenSignals == 0 {
+		return true
+	}
+
+	if dv.DivergenceSignals[lenSignals-1].Action == SELL {
+		return true
+	}
+
+	return false
+}
+
+// Sell appends sell-signal to Signals, if can not sell, return false
+func (dv *DivergenceSignals) Sell(symbol string, time int64, price float64) bool {
+	if !(dv.CanSell()) {
+		return false
+	}
+	dv.DivergenceSignals = append(dv.DivergenceSignals, DivergenceSignal{Symbol: symbol, Time: time, Price: price, Action: SELL})
+	return true
+}
+
+// CanSell judges whether sell or not
+func (dv *DivergenceSignals) CanSell() bool {
+	lenSignals := len(dv.DivergenceSignals)
+	// not buy or sell
+	if lenSignals == 0 {
+		return false
+	}
+
+	if dv.DivergenceSignals[lenSignals-1].Action == BUY {
+		return true
+	}
+
+	return false
+}
+
+// Profit calculates profit for backtest
+func (dv *DivergenceSignals) Profit() float64 {
+	profit := 0.0
+	afterSell := 0.0
+	isHolding := false
+
+	for _, signal := range dv.DivergenceSignals {
+		if signal.Action == BUY {
+			profit -= signal.Price
+			isHolding = true
+		} else if signal.Action == SELL {
+			profit += signal.Price
+			afterSell = profit
+			isHolding = false
+		}
+	}
+
+	if isHolding {
+		return afterSell
+	}
+
+	return profit
+}
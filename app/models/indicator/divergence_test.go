@@ -0,0 +1,82 @@
+package indicator_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/jumpei00/gostocktrade/app/models/indicator"
+	"testing"
+)
+
+func TestDivergenceBuyAndSell(t *testing.T) {
+	assert := assert.New(t)
+
+	signals := indicator.DivergenceSignals{}
+	// when empty
+	assert.False(signals.Sell("VOO", 0, 100))
+	assert.True(signals.Buy("VOO", 0, 100))
+
+	// when last is BUY
+	assert.False(signals.Buy("VOO", 1, 100))
+	assert.True(signals.Sell("VOO", 1, 100))
+
+	// when last is SELL
+	assert.False(signals.Sell("VOO", 2, 100))
+	assert.True(signals.Buy("VOO", 2, 100))
+}
+
+func TestDivergenceProfit(t *testing.T) {
+	assert := assert.New(t)
+
+	signals := indicator.DivergenceSignals{}
+	signals.Buy("VOO", 0, 100)
+	signals.Sell("VOO", 1, 150)
+	signals.Buy("VOO", 2, 120)
+
+	// when buy at 100, sell at 150, buy at 120,
+	// expected profit is 50
+	assert.Equal(50.0, signals.Profit())
+}
+
+func TestSwingPoints(t *testing.T) {
+	assert := assert.New(t)
+
+	highs, lows := indicator.SwingPoints([]float64{1, 3, 2.5, 2, 1, 4, 1}, 1)
+	assert.Equal([]int{1, 5}, highs)
+	assert.Equal([]int{4}, lows)
+
+	// the same values, the first one is swing point
+	highs, _ = indicator.SwingPoints([]float64{1, 3, 3, 1}, 1)
+	assert.Equal([]int{1}, highs)
+
+	// last width days are not fixed
+	highs, _ = indicator.SwingPoints([]float64{1, 2, 3, 4, 5}, 2)
+	assert.Empty(highs)
+}
+
+func TestDivergences(t *testing.T) {
+	assert := assert.New(t)
+
+	// price makes lower low, oscillator makes higher low
+	low := []float64{10, 8, 5, 8, 10, 9, 4, 9, 10}
+	high := []float64{11, 9, 6, 9, 11, 10, 5, 10, 11}
+	oscillator := []float64{50, 40, 20, 40, 50, 45, 30, 45, 50}
+	assert.Equal([]indicator.Divergence{
+		{Index: 7, From: 2, To: 6, Kind: indicator.REGULAR, Direction: indicator.BULLISH},
+	}, indicator.Divergences(high, low, oscillator, 1))
+
+	// price makes higher low, oscillator makes lower low
+	low = []float64{10, 8, 4, 8, 10, 9, 5, 9, 10}
+	high = []float64{11, 9, 5, 9, 11, 10, 6, 10, 11}
+	oscillator = []float64{50, 40, 30, 40, 50, 45, 20, 45, 50}
+	assert.Equal([]indicator.Divergence{
+		{Index: 7, From: 2, To: 6, Kind: indicator.HIDDEN, Direction: indicator.BULLISH},
+	}, indicator.Divergences(high, low, oscillator, 1))
+
+	// price makes higher high, oscillator makes lower high,
+	// swing of oscillator is 1 day late
+	high = []float64{1, 3, 5, 3, 1, 2, 6, 4, 1, 1}
+	low = []float64{0, 2, 4, 2, 0, 1, 5, 3, 0, 0}
+	oscillator = []float64{10, 30, 70, 30, 10, 20, 50, 60, 20, 10}
+	assert.Equal([]indicator.Divergence{
+		{Index: 8, From: 2, To: 6, Kind: indicator.REGULAR, Direction: indicator.BEARISH},
+	}, indicator.Divergences(high, low, oscillator, 1))
+}
@@ -0,0 +1,115 @@
+package indicator
+
+// DonchianBacktestParam represents some parameters used for backtest,
+// entry is days of high for buying, exit is days of low for selling,
+// atr is period of ATR used for position size
+type DonchianBacktestParam struct {
+	DonchianEntryLow  int `json:"entry_low"`
+	DonchianEntryHigh int `json:"entry_high"`
+	DonchianExitLow   int `json:"exit_low"`
+	DonchianExitHigh  int `json:"exit_high"`
+	DonchianAtrLow    int `json:"atr_low"`
+	DonchianAtrHigh   int `json:"atr_high"`
+}
+
+// DonchianRisk is the ratio of price which a position risks per ATR,
+// so position size is (price * DonchianRisk) / ATR
+const DonchianRisk = 0.02
+
+// DonchianSize returns position size scaled by ATR,
+// when volatility is high, size becomes small
+func DonchianSize(price, atr float64) float64 {
+	if atr <= 0 {
+		return 0
+	}
+	return price * DonchianRisk / atr
+}
+
+// DonchianSignals stores DonchianSignal
+type DonchianSignals struct {
+	DonchianSignals []DonchianSignal
+}
+
+// DonchianSignal is signal results of backtest, size is position size of the trade
+type DonchianSignal struct {
+	ID     int     `gorm:"primary_key" json:"-"`
+	Symbol string  `json:"-"`
+	Time   int64   `json:"time"`
+	Price  float64 `json:"-"`
+	Size   float64 `json:"size"`
+	Action string  `json:"action"`
+}
+
+// Buy appends buy-signal of size to Signals, if can not buy, return false
+func (dc *DonchianSignals) Buy(symbol string, time int64, price, size float64) bool {
+	if !(dc.CanBuy()) || size <= 0 {
+		return false
+	}
+	dc.DonchianSignals = append(dc.DonchianSignals, DonchianSignal{Symbol: symbol, Time: time, Price: price, Size: size, Action: BUY})
+	return true
+}
+
+// CanBuy judges whether buy or not
+func (dc *DonchianSignals) CanBuy() bool {
+	lenSignals := len(dc.DonchianSignals)
+	// not buy or sell
+	if lenSignals == 0 {
+		return true
+	}
+
+	if dc.DonchianSignals[lenSignals-1].Action == SELL {
+		return true
+	}
+
+	return false
+}
+
+// Sell appends sell-signal to Signals, the size is the same to the last buy,
+// if can not sell, return false
+func (dc *DonchianSignals) Sell(symbol string, time int64, price float64) bool {
+	if !(dc.CanSell()) {
+		return false
+	}
+	size := dc.DonchianSignals[len(dc.DonchianSignals)-1].Size
+	dc.DonchianSignals = append(dc.DonchianSignals, DonchianSignal{Symbol: symbol, Time: time, Price: price, Size: size, Action: SELL})
+	return true
+}
+
+// CanSell judges whether sell or not
+func (dc *DonchianSignals) CanSell() bool {
+	lenSignals := len(dc.DonchianSignals)
+	// not buy or sell
+	if lenSignals == 0 {
+		return false
+	}
+
+	if dc.DonchianSignals[lenSignals-1].Action == BUY {
+		return true
+	}
+
+	return false
+}
+
+// Profit calculates profit for backtest, each price is multiplied by size
+func (dc *DonchianSignals) Profit() float64 {
+	profit := 0.0
+	afterSell := 0.0
+	isHolding := false
+
+	for _, signal := range dc.DonchianSignals {
+		if signal.Action == BUY {
+			profit -= signal.Price * signal.Size
+			isHolding = true
+		} else if signal.Action == SELL {
+			profit += signal.Price * signal.Size
+			afterSell = profit
+			isHolding = false
+		}
+	}
+
+	if isHolding {
+		return afterSell
+	}
+
+	return profit
+}
@@ -0,0 +1,59 @@
+package indicator_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/jumpei00/gostocktrade/app/models/indicator"
+	"testing"
+)
+
+func TestDonchianBuyAndSell(t *testing.T) {
+	assert := assert.New(t)
+
+	signals := indicator.DonchianSignals{}
+	// when empty
+	assert.False(signals.Sell("VOO", 0, 100))
+	assert.False(signals.Buy("VOO", 0, 100, 0))
+	assert.True(signals.Buy("VOO", 0, 100, 2))
+
+	// when last is BUY
+	assert.False(signals.Buy("VOO", 1, 100, 2))
+	assert.True(signals.Sell("VOO", 1, 100))
+	// size of sell is the same to buy
+	assert.Equal(2.0, signals.DonchianSignals[1].Size)
+
+	// when last is SELL
+	assert.False(signals.Sell("VOO", 2, 100))
+	assert.True(signals.Buy("VOO", 2, 100, 1))
+}
+
+func TestDonchianSize(t *testing.T) {
+	assert := assert.New(t)
+
+	// 2% of price 100 is 2, ATR 4 is twice of it
+	assert.Equal(0.5, indicator.DonchianSize(100, 4))
+	assert.Equal(2.0, indicator.DonchianSize(100, 1))
+	assert.Equal(0.0, indicator.DonchianSize(100, 0))
+}
+
+func TestDonchianProfit(t *testing.T) {
+	assert := assert.New(t)
+
+	signals := indicator.DonchianSignals{}
+	signals.Buy("VOO", 0, 100, 2)
+	signals.Sell("VOO", 1, 150)
+
+	// when buy 2 at 100, sell 2 at 150,
+	// expected profit is 100
+	assert.Equal(100.0, signals.Profit())
+
+	signals.Buy("VOO", 2, 120, 0.5)
+
+	// when holding, profit is until last sell
+	assert.Equal(100.0, signals.Profit())
+
+	signals.Sell("VOO", 3, 100)
+
+	// when buy 0.5 at 120, sell 0.5 at 100,
+	// expected profit is 100 - 10
+	assert.Equal(90.0, signals.Profit())
+}
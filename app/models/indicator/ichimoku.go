@@ -0,0 +1,147 @@
+package indicator
+
+import "math"
+
+// IchimokuBacktestParam represents some parameters used for backtest
+type IchimokuBacktestParam struct {
+	IchimokuTenkanLow  int `json:"tenkan_low"`
+	IchimokuTenkanHigh int `json:"tenkan_high"`
+	IchimokuKijunLow   int `json:"kijun_low"`
+	IchimokuKijunHigh  int `json:"kijun_high"`
+	IchimokuSenkouLow  int `json:"senkou_low"`
+	IchimokuSenkouHigh int `json:"senkou_high"`
+}
+
+// IchimokuSignals stores IchimokuSignal
+type IchimokuSignals struct {
+	IchimokuSignals []IchimokuSignal
+}
+
+// IchimokuSignal is signal results of backtest
+type IchimokuSignal struct {
+	ID     int     `gorm:"primary_key" json:"-"`
+	Symbol string  `json:"-"`
+	Time   int64   `json:"time"`
+	Price  float64 `json:"-"`
+	Action string  `json:"action"`
+}
+
+// Buy appends buy-signal to Signals, if can not buy, return false
+func (ic *IchimokuSignals) Buy(symbol string, time int64, price float64) bool {
+	if !(ic.CanBuy()) {
+		return false
+	}
+	ic.IchimokuSignals = append(ic.IchimokuSignals, IchimokuSignal{Symbol: symbol, Time: time, Price: price, Action: BUY})
+	return true
+}
+
+// CanBuy judges whether buy or not
+func (ic *IchimokuSignals) CanBuy() bool {
+	lenSignals := len(ic.IchimokuSignals)
+	// not buy or sell
+	if lenSignals == 0 {
+		return true
+	}
+
+	if ic.IchimokuSignals[lenSignals-1].Action == SELL {
+		return true
+	}
+
+	return false
+}
+
+// Sell appends sell-signal to Signals, if can not sell, return false
+func (ic *IchimokuSignals) Sell(symbol string, time int64, price float64) bool {
+	if !(ic.CanSell()) {
+		return false
+	}
+	ic.IchimokuSignals = append(ic.IchimokuSignals, IchimokuSignal{Symbol: symbol, Time: time, Price: price, Action: SELL})
+	return true
+}
+
+// CanSell judges whether sell or not
+func (ic *IchimokuSignals) CanSell() bool {
+	lenSignals := len(ic.IchimokuSignals)
+	// not buy or sell
+	if lenSignals == 0 {
+		return false
+	}
+
+	if ic.IchimokuSignals[lenSignals-1].Action == BUY {
+		return true
+	}
+
+	return false
+}
+
+// Profit calculates profit for backtest
+func (ic *IchimokuSignals) Profit() float64 {
+	profit := 0.0
+	afterSell := 0.0
+	isHolding := false
+
+	for _, signal := range ic.IchimokuSignals {
+		if signal.Action == BUY {
+			profit -= signal.Price
+			isHolding = true
+		} else if signal.Action == SELL {
+			profit += signal.Price
+			afterSell = profit
+			isHolding = false
+		}
+	}
+
+	if isHolding {
+		return afterSell
+	}
+
+	return profit
+}
+
+// Ichimoku calculates lines of Ichimoku Kinko Hyo,
+// senkou spans are displaced forward by kijun, chikou is displaced backward by kijun,
+// so every line is aligned to the candle at the same index, and 0 means no value
+func Ichimoku(high, low, close []float64, tenkan, kijun, senkou int) (tenkanSen, kijunSen, senkouA, senkouB, chikou []float64) {
+	length := len(close)
+	tenkanSen = midpoint(high, low, tenkan)
+	kijunSen = midpoint(high, low, kijun)
+	senkouBase := midpoint(high, low, senkou)
+
+	senkouA = make([]float64, length)
+	senkouB = make([]float64, length)
+	chikou = make([]float64, length)
+
+	for i := 0; i < length; i++ {
+		if base := i - kijun; base >= 0 {
+			if tenkanSen[base] != 0 && kijunSen[base] != 0 {
+				senkouA[i] = (tenkanSen[base] + kijunSen[base]) / 2
+			}
+			senkouB[i] = senkouBase[base]
+		}
+		if i+kijun < length {
+			chikou[i] = close[i+kijun]
+		}
+	}
+
+	return tenkanSen, kijunSen, senkouA, senkouB, chikou
+}
+
+// midpoint returns (highest high + lowest low) / 2 for last period
+func midpoint(high, low []float64, period int) []float64 {
+	mid := make([]float64, len(high))
+	if period < 1 {
+		return mid
+	}
+
+	for i := period - 1; i < len(high); i++ {
+		highest := high[i]
+		lowest := low[i]
+		for j := i - period + 1; j < i; j++ {
+			highest = math.Max(highest, high[j])
+			lowest = math.Min(lowest, low[j])
+		}
+		mid[i] = (highest + lowest) / 2
+	}
+
+	return mid
+}
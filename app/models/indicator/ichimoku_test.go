@@ -0,0 +1,77 @@
+package indicator_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/jumpei00/gostocktrade/app/models/indicator"
+	"testing"
+)
+
+func TestIchimokuBuyAndSell(t *testing.T) {
+	assert := assert.New(t)
+
+	signals := indicator.IchimokuSignals{}
+	// when empty
+	assert.False(signals.Sell("VOO", 0, 100))
+	assert.True(signals.Buy("VOO", 0, 100))
+
+	// when last is BUY
+	assert.False(signals.Buy("VOO", 1, 100))
+	assert.True(signals.Sell("VOO", 1, 100))
+
+	// when last is SELL
+	assert.False(signals.Sell("VOO", 2, 100))
+	assert.True(signals.Buy("VOO", 2, 100))
+}
+
+func TestIchimokuProfit(t *testing.T) {
+	assert := assert.New(t)
+
+	signals := indicator.IchimokuSignals{
+		IchimokuSignals: []indicator.IchimokuSignal{
+			indicator.IchimokuSignal{
+				Symbol: "VOO",
+				Time: 0,
+				Price: 100,
+				Action: indicator.BUY,
+			},
+			indicator.IchimokuSignal{
+				Symbol: "VOO",
+				Time: 1,
+				Price: 150,
+				Action: indicator.SELL,
+			},
+		},
+	}
+
+	// when buy at 100, sell at 150,
+	// expected profit is 50
+	assert.Equal(50.0, signals.Profit())
+
+	signals.IchimokuSignals = append(signals.IchimokuSignals, indicator.IchimokuSignal{
+		Symbol: "VOO", Time: 2, Price: 100, Action: indicator.BUY,
+	})
+	
+	// when buy at 100, sell at 150, buy at 100,
+	// expected profit is 50
+	assert.Equal(50.0, signals.Profit())
+}
+func TestIchimoku(t *testing.T) {
+	assert := assert.New(t)
+
+	high := []float64{10, 12, 14, 16, 18, 20, 22, 24}
+	low := []float64{8, 10, 12, 14, 16, 18, 20, 22}
+	close := []float64{9, 11, 13, 15, 17, 19, 21, 23}
+
+	tenkan, kijun, senkouA, senkouB, chikou := indicator.Ichimoku(high, low, close, 2, 3, 4)
+
+	// tenkan: (highest + lowest) / 2 for 2 candles
+	assert.Equal([]float64{0, 10, 12, 14, 16, 18, 20, 22}, tenkan)
+	// kijun: (highest + lowest) / 2 for 3 candles
+	assert.Equal([]float64{0, 0, 11, 13, 15, 17, 19, 21}, kijun)
+	// senkou A: (tenkan + kijun) / 2, displaced forward by 3
+	assert.Equal([]float64{0, 0, 0, 0, 0, 11.5, 13.5, 15.5}, senkouA)
+	// senkou B: (highest + lowest) / 2 for 4 candles, displaced forward by 3
+	assert.Equal([]float64{0, 0, 0, 0, 0, 0, 12, 14}, senkouB)
+	// chikou: close displaced backward by 3
+	assert.Equal([]float64{15, 17, 19, 21, 23, 0, 0, 0}, chikou)
+}
@@ -0,0 +1,94 @@
+package indicator
+
+// LevelBacktestParam represents some parameters used for backtest,
+// lookback is number of days from which support and resistance levels are detected
+type LevelBacktestParam struct {
+	LevelLookbackLow  int `json:"lookback_low"`
+	LevelLookbackHigh int `json:"lookback_high"`
+}
+
+// LevelSignals stores LevelSignal
+type LevelSignals struct {
+	LevelSignals []LevelSignal
+}
+
+// LevelSignal is signal results of backtest
+type LevelSignal struct {
+	ID     int     `gorm:"primary_key" json:"-"`
+	Symbol string  `json:"-"`
+	Time   int64   `json:"time"`
+	Price  float64 `json:"-"`
+	Action string  `json:"action"`
+}
+
+// Buy appends buy-signal to Signals, if can not buy, return false
+func (level *LevelSignals) Buy(symbol string, time int64, price float64) bool {
+	if !(level.CanBuy()) {
+		return false
+	}
+	level.LevelSignals = append(level.LevelSignals, LevelSignal{Symbol: symbol, Time: time, Price: price, Action: BUY})
+	return true
+}
+
+// CanBuy judges whether buy or not
+func (level *LevelSignals) CanBuy() bool {
+	lenSignals := len(level.LevelSignals)
+	// not buy or sell
+	if lenSignals == 0 {
+		return true
+	}
+
+	if level.LevelSignals[lenSignals-1].Action == SELL {
+		return true
+	}
+
+	return false
+}
+
+// Sell appends sell-signal to Signals, if can not sell, return false
+func (level *LevelSignals) Sell(symbol string, time int64, price float64) bool {
+	if !(level.CanSell()) {
+		return false
+	}
+	level.LevelSignals = append(level.LevelSignals, LevelSignal{Symbol: symbol, Time: time, Price: price, Action: SELL})
+	return true
+}
+
+// CanSell judges whether sell or not
+func (level *LevelSignals) CanSell() bool {
+	lenSignals := len(level.LevelSignals)
+	// not buy or sell
+	if lenSignals == 0 {
+		return false
+	}
+
+	if level.LevelSignals[lenSignals-1].Action == BUY {
+		return true
+	}
+
+	return false
+}
+
+// Profit calculates profit for backtest
+func (level *LevelSignals) Profit() float64 {
+	profit := 0.0
+	afterSell := 0.0
+	isHolding := false
+
+	for _, signal := range level.LevelSignals {
+		if signal.Action == BUY {
+			profit -= signal.Price
+			isHolding = true
+		} else if signal.Action == SELL {
+			profit += signal.Price
+			afterSell = profit
+			isHolding = false
+		}
+	}
+
+	if isHolding {
+		return afterSell
+	}
+
+	return profit
+}
@@ -0,0 +1,57 @@
+package indicator_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/jumpei00/gostocktrade/app/models/indicator"
+	"testing"
+)
+
+func TestLevelBuyAndSell(t *testing.T) {
+	assert := assert.New(t)
+
+	signals := indicator.LevelSignals{}
+	// when empty
+	assert.False(signals.Sell("VOO", 0, 100))
+	assert.True(signals.Buy("VOO", 0, 100))
+
+	// when last is BUY
+	assert.False(signals.Buy("VOO", 1, 100))
+	assert.True(signals.Sell("VOO", 1, 100))
+
+	// when last is SELL
+	assert.False(signals.Sell("VOO", 2, 100))
+	assert.True(signals.Buy("VOO", 2, 100))
+}
+
+func TestLevelProfit(t *testing.T) {
+	assert := assert.New(t)
+
+	signals := indicator.LevelSignals{
+		LevelSignals: []indicator.LevelSignal{
+			indicator.LevelSignal{
+				Symbol: "VOO",
+				Time: 0,
+				Price: 100,
+				Action: indicator.BUY,
+			},
+			indicator.LevelSignal{
+				Symbol: "VOO",
+				Time: 1,
+				Price: 150,
+				Action: indicator.SELL,
+			},
+		},
+	}
+
+	// when buy at 100, sell at 150,
+	// expected profit is 50
+	assert.Equal(50.0, signals.Profit())
+
+	signals.LevelSignals = append(signals.LevelSignals, indicator.LevelSignal{
+		Symbol: "VOO", Time: 2, Price: 100, Action: indicator.BUY,
+	})
+	
+	// when buy at 100, sell at 150, buy at 100,
+	// expected profit is 50
+	assert.Equal(50.0, signals.Profit())
+}
\ No newline at end of file
@@ -0,0 +1,97 @@
+package indicator
+
+// MfiBacktestParam represents some parameters used for backtest
+type MfiBacktestParam struct {
+	MfiPeriodLow      int     `json:"period_low"`
+	MfiPeriodHigh     int     `json:"period_high"`
+	MfiBuyThreadLow   float64 `json:"buy_low"`
+	MfiBuyThreadHigh  float64 `json:"buy_high"`
+	MfiSellThreadLow  float64 `json:"sell_low"`
+	MfiSellThreadHigh float64 `json:"sell_high"`
+}
+
+// MfiSignals stores MfiSignal
+type MfiSignals struct {
+	MfiSignals []MfiSignal
+}
+
+// MfiSignal is signal results of backtest
+type MfiSignal struct {
+	ID     int     `gorm:"primary_key" json:"-"`
+	Symbol string  `json:"-"`
+	Time   int64   `json:"time"`
+	Price  float64 `json:"-"`
+	Action string  `json:"action"`
+}
+
+// Buy appends buy-signal to Signals, if can not buy, return false
+func (mfi *MfiSignals) Buy(symbol string, time int64, price float64) bool {
+	if !(mfi.CanBuy()) {
+		return false
+	}
+	mfi.MfiSignals = append(mfi.MfiSignals, MfiSignal{Symbol: symbol, Time: time, Price: price, Action: BUY})
+	return true
+}
+
+// CanBuy judges whether buy or not
+func (mfi *MfiSignals) CanBuy() bool {
+	lenSignals := len(mfi.MfiSignals)
+	// not buy or sell
+	if lenSignals == 0 {
+		return true
+	}
+
+	if mfi.MfiSignals[lenSignals-1].Action == SELL {
+		return true
+	}
+
+	return false
+}
+
+// Sell appends sell-signal to Signals, if can not sell, return false
+func (mfi *MfiSignals) Sell(symbol string, time int64, price float64) bool {
+	if !(mfi.CanSell()) {
+		return false
+	}
+	mfi.MfiSignals = append(mfi.MfiSignals, MfiSignal{Symbol: symbol, Time: time, Price: price, Action: SELL})
+	return true
+}
+
+// CanSell judges whether sell or not
+func (mfi *MfiSignals) CanSell() bool {
+	lenSignals := len(mfi.MfiSignals)
+	// not buy or sell
+	if lenSignals == 0 {
+		return false
+	}
+
+	if mfi.MfiSignals[lenSignals-1].Action == BUY {
+		return true
+	}
+
+	return false
+}
+
+// Profit calculates profit for backtest
+func (mfi *MfiSignals) Profit() float64 {
+	profit := 0.0
+	afterSell := 0.0
+	isHolding := false
+
+	for _, signal := range mfi.MfiSignals {
+		if signal.Action == BUY {
+			profit -= signal.Price
+			isHolding = true
+		} else if signal.Action == SELL {
+			profit += signal.Price
+			afterSell = profit
+			isHolding = false
+		}
+	}
+
+	if isHolding {
+		return afterSell
+	}
+
+	return profit
+}
@@ -0,0 +1,94 @@
+package indicator
+
+// ObvBacktestParam represents some parameters used for backtest,
+// period is of EMA which On-Balance Volume is compared with
+type ObvBacktestParam struct {
+	ObvPeriodLow  int `json:"period_low"`
+	ObvPeriodHigh int `json:"period_high"`
+}
+
+// ObvSignals stores ObvSignal
+type ObvSignals struct {
+	ObvSignals []ObvSignal
+}
+
+// ObvSignal is signal results of backtest
+type ObvSignal struct {
+	ID     int     `gorm:"primary_key" json:"-"`
+	Symbol string  `json:"-"`
+	Time   int64   `json:"time"`
+	Price  float64 `json:"-"`
+	Action string  `json:"action"`
+}
+
+// Buy appends buy-signal to Signals, if can not buy, return false
+func (obv *ObvSignals) Buy(symbol string, time int64, price float64) bool {
+	if !(obv.CanBuy()) {
+		return false
+	}
+	obv.ObvSignals = append(obv.ObvSignals, ObvSignal{Symbol: symbol, Time: time, Price: price, Action: BUY})
+	return true
+}
+
+// CanBuy judges whether buy or not
+func (obv *ObvSignals) CanBuy() bool {
+	lenSignals := len(obv.ObvSignals)
+	// not buy or sell
+	if lenSignals == 0 {
+		return true
+	}
+
+	if obv.ObvSignals[lenSignals-1].Action == SELL {
+		return true
+	}
+
+	return false
+}
+
+// Sell appends sell-signal to Signals, if can not sell, return false
+func (obv *ObvSignals) Sell(symbol string, time int64, price float64) bool {
+	if !(obv.CanSell()) {
+		return false
+	}
+	obv.ObvSignals = append(obv.ObvSignals, ObvSignal{Symbol: symbol, Time: time, Price: price, Action: SELL})
+	return true
+}
+
+// CanSell judges whether sell or not
+func (obv *ObvSignals) CanSell() bool {
+	lenSignals := len(obv.ObvSignals)
+	// not buy or sell
+	if lenSignals == 0 {
+		return false
+	}
+
+	if obv.ObvSignals[lenSignals-1].Action == BUY {
+		return true
+	}
+
+	return false
+}
+
+// Profit calculates profit for backtest
+func (obv *ObvSignals) Profit() float64 {
+	profit := 0.0
+	afterSell := 0.0
+	isHolding := false
+
+	for _, signal := range obv.ObvSignals {
+		if signal.Action == BUY {
+			profit -= signal.Price
+			isHolding = true
+		} else if signal.Action == SELL {
+			profit += signal.Price
+			afterSell = profit
+			isHolding = false
+		}
+	}
+
+	if isHolding {
+		return afterSell
+	}
+
+	return profit
+}
@@ -0,0 +1,53 @@
+package indicator
+
+import "math"
+
+// type of order
+const (
+	// MARKETORDER is filled at the open of the candle
+	MARKETORDER = "market"
+	// LIMITORDER buys at the price or lower, sells at the price or higher
+	LIMITORDER = "limit"
+	// STOPORDER buys at the price or higher, sells at the price or lower
+	STOPORDER = "stop"
+)
+
+// FillPrice returns price of order of side filled in the candle of open, high and low, and false when not filled.
+// Order of prices within the candle is unknown, so it is assumed conservatively,
+// limit is filled only when the price is traded through, not touched,
+// and when the candle opens beyond the price, it is filled at the open,
+// which is better price for limit and worse for stop
+func FillPrice(orderType, side string, price, open, high, low float64) (float64, bool) {
+	switch {
+	case orderType == MARKETORDER:
+		return open, true
+
+	case orderType == LIMITORDER && side == BUY:
+		if open <= price {
+			return open, true
+		}
+		if low < price {
+			return price, true
+		}
+
+	case orderType == LIMITORDER && side == SELL:
+		if open >= price {
+			return open, true
+		}
+		if high > price {
+			return price, true
+		}
+
+	case orderType == STOPORDER && side == BUY:
+		if high >= price {
+			return math.Max(open, price), true
+		}
+
+	case orderType == STOPORDER && side == SELL:
+		if low <= price {
+			return math.Min(open, price), true
+		}
+	}
+
+	return 0, false
+}
@@ -0,0 +1,35 @@
+package indicator_test
+
+import (
+	"github.com/jumpei00/gostocktrade/app/models/indicator"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestFillPrice(t *testing.T) {
+	assert := assert.New(t)
+
+	// candle of open 100, high 105, low 95
+	cases := []struct {
+		orderType, side string
+		price, expected float64
+		filled          bool
+	}{
+		{indicator.MARKETORDER, indicator.BUY, 0, 100, true},
+		{indicator.LIMITORDER, indicator.BUY, 97, 97, true},
+		{indicator.LIMITORDER, indicator.BUY, 95, 0, false},
+		{indicator.LIMITORDER, indicator.BUY, 102, 100, true},
+		{indicator.LIMITORDER, indicator.SELL, 103, 103, true},
+		{indicator.LIMITORDER, indicator.SELL, 98, 100, true},
+		{indicator.STOPORDER, indicator.BUY, 105, 105, true},
+		{indicator.STOPORDER, indicator.BUY, 98, 100, true},
+		{indicator.STOPORDER, indicator.BUY, 106, 0, false},
+		{indicator.STOPORDER, indicator.SELL, 96, 96, true},
+		{indicator.STOPORDER, indicator.SELL, 102, 100, true},
+	}
+	for _, c := range cases {
+		price, filled := indicator.FillPrice(c.orderType, c.side, c.price, 100, 105, 95)
+		assert.Equal(c.filled, filled, c)
+		assert.Equal(c.expected, price, c)
+	}
+}
@@ -0,0 +1,113 @@
+package indicator
+
+const (
+	// LONGSPREAD represents buying y and selling x, expecting the spread to rise
+	LONGSPREAD = "LONG_SPREAD"
+	// SHORTSPREAD represents selling y and buying x, expecting the spread to fall
+	SHORTSPREAD = "SHORT_SPREAD"
+)
+
+// reasons of closing pair trade
+const (
+	// REVERSION represents that z-score reverted to exit threshold
+	REVERSION = "REVERSION"
+	// STOP represents that z-score reached stop threshold
+	STOP = "STOP"
+)
+
+// PairBacktestParam represents some parameters used for backtest of pair trading,
+// lookback is of hedge ratio and z-score, entry, exit and stop are thresholds of absolute z-score
+type PairBacktestParam struct {
+	PairLookbackLow  int     `json:"lookback_low"`
+	PairLookbackHigh int     `json:"lookback_high"`
+	PairEntryLow     float64 `json:"entry_low"`
+	PairEntryHigh    float64 `json:"entry_high"`
+	PairExitLow      float64 `json:"exit_low"`
+	PairExitHigh     float64 `json:"exit_high"`
+	PairStopLow      float64 `json:"stop_low"`
+	PairStopHigh     float64 `json:"stop_high"`
+}
+
+// PairTrades stores PairTrade
+type PairTrades struct {
+	PairTrades []PairTrade
+}
+
+// PairTrade is a trade of two legs opened and closed at the same time,
+// quantities are signed, positive is long, and y quantity is always 1 or -1,
+// while exit time is 0, the trade is open
+type PairTrade struct {
+	ID        int     `gorm:"primary_key" json:"-"`
+	SymbolY   string  `json:"y"`
+	SymbolX   string  `json:"x"`
+	Side      string  `json:"side"`
+	QuantityY float64 `json:"quantity_y"`
+	QuantityX float64 `json:"quantity_x"`
+	EntryTime int64   `json:"entry_time"`
+	EntryY    float64 `json:"entry_y"`
+	EntryX    float64 `json:"entry_x"`
+	EntryZ    float64 `json:"entry_z"`
+	ExitTime  int64   `json:"exit_time,omitempty"`
+	ExitY     float64 `json:"exit_y,omitempty"`
+	ExitX     float64 `json:"exit_x,omitempty"`
+	ExitZ     float64 `json:"exit_z,omitempty"`
+	Reason    string  `json:"reason,omitempty"`
+}
+
+// Profit returns total profit of both legs, if the trade is open, returns 0
+func (pt *PairTrade) Profit() float64 {
+	if pt.ExitTime == 0 {
+		return 0
+	}
+	return pt.QuantityY*(pt.ExitY-pt.EntryY) + pt.QuantityX*(pt.ExitX-pt.EntryX)
+}
+
+// Open appends new trade, ratio is hedge ratio of y against x, if a trade is already open, return false
+func (pa *PairTrades) Open(y, x, side string, ratio float64, time int64, priceY, priceX, z float64) bool {
+	if pa.IsOpen() {
+		return false
+	}
+
+	trade := PairTrade{SymbolY: y, SymbolX: x, Side: side, EntryTime: time, EntryY: priceY, EntryX: priceX, EntryZ: z}
+	if side == LONGSPREAD {
+		trade.QuantityY, trade.QuantityX = 1, -ratio
+	} else {
+		trade.QuantityY, trade.QuantityX = -1, ratio
+	}
+	pa.PairTrades = append(pa.PairTrades, trade)
+	return true
+}
+
+// Close closes the open trade, if no trade is open, return false
+func (pa *PairTrades) Close(time int64, priceY, priceX, z float64, reason string) bool {
+	if !(pa.IsOpen()) {
+		return false
+	}
+
+	trade := &pa.PairTrades[len(pa.PairTrades)-1]
+	trade.ExitTime, trade.ExitY, trade.ExitX, trade.ExitZ, trade.Reason = time, priceY, priceX, z, reason
+	return true
+}
+
+// IsOpen judges whether the last trade is open
+func (pa *PairTrades) IsOpen() bool {
+	lenTrades := len(pa.PairTrades)
+	return lenTrades != 0 && pa.PairTrades[lenTrades-1].ExitTime == 0
+}
+
+// Side returns side of the open trade, if no trade is open, returns empty
+func (pa *PairTrades) Side() string {
+	if !(pa.IsOpen()) {
+		return ""
+	}
+	return pa.PairTrades[len(pa.PairTrades)-1].Side
+}
+
+// Profit calculates profit of closed trades for backtest
+func (pa *PairTrades) Profit() float64 {
+	profit := 0.0
+	for i := range pa.PairTrades {
+		profit += pa.PairTrades[i].Profit()
+	}
+	return profit
+}
@@ -0,0 +1,49 @@
+package indicator_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/jumpei00/gostocktrade/app/models/indicator"
+	"testing"
+)
+
+func TestPairOpenAndClose(t *testing.T) {
+	assert := assert.New(t)
+
+	trades := indicator.PairTrades{}
+	// when empty
+	assert.False(trades.Close(0, 100, 50, 0, indicator.REVERSION))
+	assert.Equal("", trades.Side())
+	assert.True(trades.Open("KO", "PEP", indicator.LONGSPREAD, 2, 0, 100, 50, -2))
+
+	// when open
+	assert.False(trades.Open("KO", "PEP", indicator.SHORTSPREAD, 2, 1, 100, 50, 2))
+	assert.Equal(indicator.LONGSPREAD, trades.Side())
+	assert.Equal(1.0, trades.PairTrades[0].QuantityY)
+	assert.Equal(-2.0, trades.PairTrades[0].QuantityX)
+	assert.True(trades.Close(1, 110, 52, 0, indicator.REVERSION))
+
+	// when closed
+	assert.False(trades.IsOpen())
+	assert.True(trades.Open("KO", "PEP", indicator.SHORTSPREAD, 2, 2, 110, 52, 2))
+	assert.Equal(-1.0, trades.PairTrades[1].QuantityY)
+	assert.Equal(2.0, trades.PairTrades[1].QuantityX)
+}
+
+func TestPairProfit(t *testing.T) {
+	assert := assert.New(t)
+
+	trades := indicator.PairTrades{}
+	// y +10, x +2, hedged by 2 shares of x, so 10 - 2 * 2
+	trades.Open("KO", "PEP", indicator.LONGSPREAD, 2, 0, 100, 50, -2)
+	trades.Close(1, 110, 52, 0, indicator.REVERSION)
+	assert.InDelta(6, trades.Profit(), 1e-9)
+
+	// y +5, x -1, short spread loses 5 + 2 * 1
+	trades.Open("KO", "PEP", indicator.SHORTSPREAD, 2, 2, 110, 52, 2)
+	trades.Close(3, 115, 51, 3, indicator.STOP)
+	assert.InDelta(-1, trades.Profit(), 1e-9)
+
+	// open trade is not counted
+	trades.Open("KO", "PEP", indicator.LONGSPREAD, 2, 4, 115, 51, -2)
+	assert.InDelta(-1, trades.Profit(), 1e-9)
+}
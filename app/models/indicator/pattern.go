@@ -0,0 +1,201 @@
+package indicator
+
+import "math"
+
+// names of candlestick patterns
+const (
+	DOJI             = "Doji"
+	HAMMER           = "Hammer"
+	SHOOTINGSTAR     = "ShootingStar"
+	BULLISHENGULFING = "BullishEngulfing"
+	BEARISHENGULFING = "BearishEngulfing"
+	MORNINGSTAR      = "MorningStar"
+	EVENINGSTAR      = "EveningStar"
+)
+
+// directions of candlestick patterns
+const (
+	BULLISH = 1
+	BEARISH = -1
+	NEUTRAL = 0
+)
+
+// PatternBacktestParam represents some parameters used for backtest,
+// trend is days to judge the trend before the pattern
+type PatternBacktestParam struct {
+	PatternTrendLow  int `json:"trend_low"`
+	PatternTrendHigh int `json:"trend_high"`
+}
+
+// Pattern is candlestick pattern completed at the candle of Index
+type Pattern struct {
+	Index     int
+	Name      string
+	Direction int
+}
+
+// Patterns scans candles and returns found patterns in order of index,
+// reversal patterns are found only after the opposite trend,
+// the trend is judged by the close of trend days before the pattern
+func Patterns(open, high, low, close []float64, trend int) []Pattern {
+	patterns := []Pattern{}
+	if trend < 1 {
+		return patterns
+	}
+
+	// down or up trend before the pattern starting at start
+	downTrend := func(start int) bool {
+		return start-1-trend >= 0 && close[start-1] < close[start-1-trend]
+	}
+	upTrend := func(start int) bool {
+		return start-1-trend >= 0 && close[start-1] > close[start-1-trend]
+	}
+	body := func(i int) float64 { return math.Abs(close[i] - open[i]) }
+	upperShadow := func(i int) float64 { return high[i] - math.Max(open[i], close[i]) }
+	lowerShadow := func(i int) float64 { return math.Min(open[i], close[i]) - low[i] }
+
+	for i := range close {
+		candleRange := high[i] - low[i]
+		if candleRange <= 0 {
+			continue
+		}
+
+		if body(i) <= candleRange*0.1 {
+			patterns = append(patterns, Pattern{Index: i, Name: DOJI, Direction: NEUTRAL})
+		} else if lowerShadow(i) >= body(i)*2 && upperShadow(i) <= body(i) && downTrend(i) {
+			patterns = append(patterns, Pattern{Index: i, Name: HAMMER, Direction: BULLISH})
+		} else if upperShadow(i) >= body(i)*2 && lowerShadow(i) <= body(i) && upTrend(i) {
+			patterns = append(patterns, Pattern{Index: i, Name: SHOOTINGSTAR, Direction: BEARISH})
+		}
+
+		if i < 1 {
+			continue
+		}
+
+		// the body of today wraps the opposite body of yesterday
+		if close[i-1] < open[i-1] && close[i] > open[i] &&
+			open[i] <= close[i-1] && close[i] >= open[i-1] && downTrend(i-1) {
+			patterns = append(patterns, Pattern{Index: i, Name: BULLISHENGULFING, Direction: BULLISH})
+		}
+		if close[i-1] > open[i-1] && close[i] < open[i] &&
+			open[i] >= close[i-1] && close[i] <= open[i-1] && upTrend(i-1) {
+			patterns = append(patterns, Pattern{Index: i, Name: BEARISHENGULFING, Direction: BEARISH})
+		}
+
+		if i < 2 {
+			continue
+		}
+
+		// long body, small body, and opposite body closing beyond the middle of the first
+		first, star := i-2, i-1
+		if body(first) >= (high[first]-low[first])*0.5 && body(star) <= body(first)*0.3 {
+			middle := (open[first] + close[first]) / 2
+			if close[first] < open[first] && close[i] > open[i] && close[i] > middle && downTrend(first) {
+				patterns = append(patterns, Pattern{Index: i, Name: MORNINGSTAR, Direction: BULLISH})
+			}
+			if close[first] > open[first] && close[i] < open[i] && close[i] < middle && upTrend(first) {
+				patterns = append(patterns, Pattern{Index: i, Name: EVENINGSTAR, Direction: BEARISH})
+			}
+		}
+	}
+
+	return patterns
+}
+
+// PatternDirections returns sum of directions of patterns completed at each index,
+// positive means bullish, negative means bearish
+func PatternDirections(patterns []Pattern, length int) []int {
+	directions := make([]int, length)
+	for _, pattern := range patterns {
+		if pattern.Index < length {
+			directions[pattern.Index] += pattern.Direction
+		}
+	}
+	return directions
+}
+
+// PatternSignals stores PatternSignal
+type PatternSignals struct {
+	PatternSignals []PatternSignal
+}
+
+// PatternSignal is signal results of backtest
+type PatternSignal struct {
+	ID     int     `gorm:"primary_key" json:"-"`
+	Symbol string  `json:"-"`
+	Time   int64   `json:"time"`
+	Price  float64 `json:"-"`
+	Action string  `json:"action"`
+}
+
+// Buy appends buy-signal to Signals, if can not buy, return false
+func (pt *PatternSignals) Buy(symbol string, time int64, price float64) bool {
+	if !(pt.CanBuy()) {
+		return false
+	}
+	pt.PatternSignals = append(pt.PatternSignals, PatternSignal{Symbol: symbol, Time: time, Price: price, Action: BUY})
+	return true
+}
+
+// CanBuy judges whether buy or not
+func (pt *PatternSignals) CanBuy() bool {
+	lenSignals := len(pt.PatternSignals)
+	// not buy or sell
+	if lenSignals == 0 {
+		return true
+	}
+
+	if pt.PatternSignals[lenSignals-1].Action == SELL {
+		return true
+	}
+
+	return false
+}
+
+// Sell appends sell-signal to Signals, if can not sell, return false
+func (pt *PatternSignals) Sell(symbol string, time int64, price float64) bool {
+	if !(pt.CanSell()) {
+		return false
+	}
+	pt.PatternSignals = append(pt.PatternSignals, PatternSignal{Symbol: symbol, Time: time, Price: price, Action: SELL})
+	return true
+}
+
+// CanSell judges whether sell or not
+func (pt *PatternSignals) CanSell() bool {
+	lenSignals := len(pt.PatternSignals)
+	// not buy or sell
+	if lenSignals == 0 {
+		return false
+	}
+
+	if pt.PatternSignals[lenSignals-1].Action == BUY {
+		return true
+	}
+
+	return false
+}
+
+// Profit calculates profit for backtest
+func (pt *PatternSignals) Profit() float64 {
+	profit := 0.0
+	afterSell := 0.0
+	isHolding := false
+
+	for _, signal := range pt.PatternSignals {
+		if signal.Action == BUY {
+			profit -= signal.Price
+			isHolding = true
+		} else if signal.Action == SELL {
+			profit += signal.Price
+			afterSell = profit
+			isHolding = false
+		}
+	}
+
+	if isHolding {
+		return afterSell
+	}
+
+	return profit
+}
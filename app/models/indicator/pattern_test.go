@@ -0,0 +1,83 @@
+package indicator_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/jumpei00/gostocktrade/app/models/indicator"
+	"testing"
+)
+
+func TestPatternBuyAndSell(t *testing.T) {
+	assert := assert.New(t)
+
+	signals := indicator.PatternSignals{}
+	// when empty
+	assert.False(signals.Sell("VOO", 0, 100))
+	assert.True(signals.Buy("VOO", 0, 100))
+
+	// when last is BUY
+	assert.False(signals.Buy("VOO", 1, 100))
+	assert.True(signals.Sell("VOO", 1, 100))
+
+	// when last is SELL
+	assert.False(signals.Sell("VOO", 2, 100))
+	assert.True(signals.Buy("VOO", 2, 100))
+}
+
+func TestPatternProfit(t *testing.T) {
+	assert := assert.New(t)
+
+	signals := indicator.PatternSignals{}
+	signals.Buy("VOO", 0, 100)
+	signals.Sell("VOO", 1, 150)
+	signals.Buy("VOO", 2, 120)
+
+	// when buy at 100, sell at 150, buy at 120,
+	// expected profit is 50
+	assert.Equal(50.0, signals.Profit())
+}
+
+func TestPatterns(t *testing.T) {
+	assert := assert.New(t)
+
+	// down trend, hammer
+	open := []float64{110, 105, 99}
+	high := []float64{111, 106, 100.2}
+	low := []float64{104, 99, 95}
+	close := []float64{105, 100, 100}
+	assert.Equal([]indicator.Pattern{{Index: 2, Name: indicator.HAMMER, Direction: indicator.BULLISH}},
+		indicator.Patterns(open, high, low, close, 1))
+
+	// the same shape is not hammer in up trend
+	close[0] = 95
+	assert.Empty(indicator.Patterns(open, high, low, close, 1))
+
+	// down trend, bullish engulfing
+	open = []float64{115, 110, 105, 100}
+	high = []float64{116, 111, 106, 108}
+	low = []float64{109, 104, 100, 99.5}
+	close = []float64{110, 105, 101, 107}
+	assert.Contains(indicator.Patterns(open, high, low, close, 1),
+		indicator.Pattern{Index: 3, Name: indicator.BULLISHENGULFING, Direction: indicator.BULLISH})
+
+	// up trend, bearish engulfing
+	open = []float64{100, 105, 110, 116}
+	high = []float64{106, 111, 116, 116.5}
+	low = []float64{99, 104, 109, 108}
+	close = []float64{105, 110, 115, 109}
+	assert.Contains(indicator.Patterns(open, high, low, close, 1),
+		indicator.Pattern{Index: 3, Name: indicator.BEARISHENGULFING, Direction: indicator.BEARISH})
+
+	// down trend, long bearish, doji, long bullish is morning star
+	open = []float64{120, 115, 110, 100.1, 101}
+	high = []float64{121, 116, 111, 101, 108}
+	low = []float64{114, 109, 100, 99, 100.5}
+	close = []float64{115, 110, 101, 100, 107}
+	patterns := indicator.Patterns(open, high, low, close, 1)
+	assert.Contains(patterns, indicator.Pattern{Index: 3, Name: indicator.DOJI, Direction: indicator.NEUTRAL})
+	assert.Contains(patterns, indicator.Pattern{Index: 4, Name: indicator.MORNINGSTAR, Direction: indicator.BULLISH})
+
+	directions := indicator.PatternDirections(patterns, len(close))
+	assert.Len(directions, 5)
+	assert.True(directions[4] > 0)
+	assert.Equal(0, directions[3])
+}
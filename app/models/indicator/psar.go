@@ -0,0 +1,92 @@
+package indicator
+
+// PsarBacktestParam represents some parameters used for backtest
+type PsarBacktestParam struct {
+	PsarStepLow  float64 `json:"step_low"`
+	PsarStepHigh float64 `json:"step_high"`
+	PsarMaxLow   float64 `json:"max_low"`
+	PsarMaxHigh  float64 `json:"max_high"`
+}
+
+// PsarSignals stores PsarSignal
+type PsarSignals struct {
+	PsarSignals []PsarSignal
+}
+
+// PsarSignal is signal results of backtest
+type PsarSignal struct {
+	ID     int     `gorm:"primary_key" json:"-"`
+	Symbol string  `json:"-"`
+	Time   int64   `json:"time"`
+	Price  float64 `json:"-"`
+	Action string  `json:"action"`
+}
+
+// Buy appends buy-signal to Signals, if can not buy, return false
+func (ps *PsarSignals) Buy(symbol string, time int64, price float64) bool {
+	if !(ps.CanBuy()) {
+		return false
+	}
+	ps.PsarSignals = append(ps.PsarSignals, PsarSignal{Symbol: symbol, Time: time, Price: price, Action: BUY})
+	return true
+}
+
+// CanBuy judges whether buy or not
+func (ps *PsarSignals) CanBuy() bool {
+	lenSignals := len(ps.PsarSignals)
+	// not buy or sell
+	if lenSignals == 0 {
+		return true
+	}
+
+	if ps.PsarSignals[lenSignals-1].Action == SELL {
+		return true
+	}
+
+	return false
+}
+
+// Sell appends sell-signal to Signals, if can not sell, return false
+func (ps *PsarSignals) Sell(symbol string, time int64, price float64) bool {
+	if !(ps.CanSell()) {
+		return false
+	}
+	ps.PsarSignals = append(ps.PsarSignals, PsarSignal{Symbol: symbol, Time: time, Price: price, Action: SELL})
+	return true
+}
+
+// CanSell judges whether sell or not,
+// unlike other signals, sell is possible at first because sell opens short position
+func (ps *PsarSignals) CanSell() bool {
+	lenSignals := len(ps.PsarSignals)
+	// not buy or sell
+	if lenSignals == 0 {
+		return true
+	}
+
+	if ps.PsarSignals[lenSignals-1].Action == BUY {
+		return true
+	}
+
+	return false
+}
+
+// Profit calculates profit for backtest by stop-and-reverse position,
+// each signal closes the current position and opens the reverse one,
+// BUY closes short and opens long, SELL closes long and opens short,
+// the last position which is not closed is not included
+func (ps *PsarSignals) Profit() float64 {
+	profit := 0.0
+
+	for i := 1; i < len(ps.PsarSignals); i++ {
+		before := ps.PsarSignals[i-1]
+		after := ps.PsarSignals[i]
+		if before.Action == BUY {
+			profit += after.Price - before.Price
+		} else if before.Action == SELL {
+			profit += before.Price - after.Price
+		}
+	}
+
+	return profit
+}
@@ -0,0 +1,68 @@
+package indicator_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/jumpei00/gostocktrade/app/models/indicator"
+	"testing"
+)
+
+func TestPsarBuyAndSell(t *testing.T) {
+	assert := assert.New(t)
+
+	signals := indicator.PsarSignals{}
+	// when empty, sell opens short position
+	assert.True(signals.Sell("VOO", 0, 100))
+
+	// when last is SELL
+	assert.False(signals.Sell("VOO", 1, 100))
+	assert.True(signals.Buy("VOO", 1, 100))
+
+	// when last is BUY
+	assert.False(signals.Buy("VOO", 2, 100))
+	assert.True(signals.Sell("VOO", 2, 100))
+
+	// when empty, buy opens long position
+	signals = indicator.PsarSignals{}
+	assert.True(signals.Buy("VOO", 0, 100))
+}
+
+func TestPsarProfit(t *testing.T) {
+	assert := assert.New(t)
+
+	signals := indicator.PsarSignals{
+		PsarSignals: []indicator.PsarSignal{
+			indicator.PsarSignal{
+				Symbol: "VOO",
+				Time: 0,
+				Price: 100,
+				Action: indicator.BUY,
+			},
+			indicator.PsarSignal{
+				Symbol: "VOO",
+				Time: 1,
+				Price: 150,
+				Action: indicator.SELL,
+			},
+		},
+	}
+
+	// when buy at 100, sell at 150,
+	// expected profit is 50
+	assert.Equal(50.0, signals.Profit())
+
+	signals.PsarSignals = append(signals.PsarSignals, indicator.PsarSignal{
+		Symbol: "VOO", Time: 2, Price: 120, Action: indicator.BUY,
+	})
+
+	// when buy at 100, sell at 150, buy at 120,
+	// long 50 + short 30, expected profit is 80
+	assert.Equal(80.0, signals.Profit())
+
+	signals.PsarSignals = append(signals.PsarSignals, indicator.PsarSignal{
+		Symbol: "VOO", Time: 3, Price: 110, Action: indicator.SELL,
+	})
+
+	// when buy at 100, sell at 150, buy at 120, sell at 110,
+	// long 50 + short 30 + long -10, expected profit is 70
+	assert.Equal(70.0, signals.Profit())
+}
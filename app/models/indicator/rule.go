@@ -0,0 +1,89 @@
+package indicator
+
+// RuleSignals stores RuleSignal for a custom rule named Name
+type RuleSignals struct {
+	Name        string
+	RuleSignals []RuleSignal
+}
+
+// RuleSignal is signal results of backtest for custom rule
+type RuleSignal struct {
+	ID     int     `gorm:"primary_key" json:"-"`
+	Symbol string  `json:"-"`
+	Name   string  `json:"name"`
+	Time   int64   `json:"time"`
+	Price  float64 `json:"-"`
+	Action string  `json:"action"`
+}
+
+// Buy appends buy-signal to Signals, if can not buy, return false
+func (ru *RuleSignals) Buy(symbol string, time int64, price float64) bool {
+	if !(ru.CanBuy()) {
+		return false
+	}
+	ru.RuleSignals = append(ru.RuleSignals, RuleSignal{Symbol: symbol, Name: ru.Name, Time: time, Price: price, Action: BUY})
+	return true
+}
+
+// CanBuy judges whether buy or not
+func (ru *RuleSignals) CanBuy() bool {
+	lenSignals := len(ru.RuleSignals)
+	// not buy or sell
+	if lenSignals == 0 {
+		return true
+	}
+
+	if ru.RuleSignals[lenSignals-1].Action == SELL {
+		return true
+	}
+
+	return false
+}
+
+// Sell appends sell-signal to Signals, if can not sell, return false
+func (ru *RuleSignals) Sell(symbol string, time int64, price float64) bool {
+	if !(ru.CanSell()) {
+		return false
+	}
+	ru.RuleSignals = append(ru.RuleSignals, RuleSignal{Symbol: symbol, Name: ru.Name, Time: time, Price: price, Action: SELL})
+	return true
+}
+
+// CanSell judges whether sell or not
+func (ru *RuleSignals) CanSell() bool {
+	lenSignals := len(ru.RuleSignals)
+	// not buy or sell
+	if lenSignals == 0 {
+		return false
+	}
+
+	if ru.RuleSignals[lenSignals-1].Action == BUY {
+		return true
+	}
+
+	return false
+}
+
+// Profit calculates profit for backtest
+func (ru *RuleSignals) Profit() float64 {
+	profit := 0.0
+	afterSell := 0.0
+	isHolding := false
+
+	for _, signal := range ru.RuleSignals {
+		if signal.Action == BUY {
+			profit -= signal.Price
+			isHolding = true
+		} else if signal.Action == SELL {
+			profit += signal.Price
+			afterSell = profit
+			isHolding = false
+		}
+	}
+
+	if isHolding {
+		return afterSell
+	}
+
+	return profit
+}
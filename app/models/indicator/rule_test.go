@@ -0,0 +1,65 @@
+package indicator_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/jumpei00/gostocktrade/app/models/indicator"
+	"testing"
+)
+
+func TestRuleBuyAndSell(t *testing.T) {
+	assert := assert.New(t)
+
+	signals := indicator.RuleSignals{Name: "golden"}
+	// when empty
+	assert.False(signals.Sell("VOO", 0, 100))
+	assert.True(signals.Buy("VOO", 0, 100))
+
+	// when last is BUY
+	assert.False(signals.Buy("VOO", 1, 100))
+	assert.True(signals.Sell("VOO", 1, 100))
+
+	// when last is SELL
+	assert.False(signals.Sell("VOO", 2, 100))
+	assert.True(signals.Buy("VOO", 2, 100))
+
+	// name is stored in each signal
+	for _, signal := range signals.RuleSignals {
+		assert.Equal("golden", signal.Name)
+	}
+}
+
+func TestRuleProfit(t *testing.T) {
+	assert := assert.New(t)
+
+	signals := indicator.RuleSignals{
+		Name: "golden",
+		RuleSignals: []indicator.RuleSignal{
+			indicator.RuleSignal{
+				Symbol: "VOO",
+				Name: "golden",
+				Time: 0,
+				Price: 100,
+				Action: indicator.BUY,
+			},
+			indicator.RuleSignal{
+				Symbol: "VOO",
+				Name: "golden",
+				Time: 1,
+				Price: 150,
+				Action: indicator.SELL,
+			},
+		},
+	}
+
+	// when buy at 100, sell at 150,
+	// expected profit is 50
+	assert.Equal(50.0, signals.Profit())
+
+	signals.RuleSignals = append(signals.RuleSignals, indicator.RuleSignal{
+		Symbol: "VOO", Name: "golden", Time: 2, Price: 100, Action: indicator.BUY,
+	})
+
+	// when buy at 100, sell at 150, buy at 100,
+	// expected profit is 50
+	assert.Equal(50.0, signals.Profit())
+}
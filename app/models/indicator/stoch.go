@@ -0,0 +1,103 @@
+package indicator
+
+// StochBacktestParam represents some parameters used for backtest,
+// k is period of %K, d is period of %D, slowing is period smoothing %K of slow stochastic,
+// both of fast and slow stochastic are tested
+type StochBacktestParam struct {
+	StochKLow           int     `json:"k_low"`
+	StochKHigh          int     `json:"k_high"`
+	StochDLow           int     `json:"d_low"`
+	StochDHigh          int     `json:"d_high"`
+	StochSlowingLow     int     `json:"slowing_low"`
+	StochSlowingHigh    int     `json:"slowing_high"`
+	StochBuyThreadLow   float64 `json:"buy_low"`
+	StochBuyThreadHigh  float64 `json:"buy_high"`
+	StochSellThreadLow  float64 `json:"sell_low"`
+	StochSellThreadHigh float64 `json:"sell_high"`
+}
+
+// StochSignals stores StochSignal
+type StochSignals struct {
+	StochSignals []StochSignal
+}
+
+// StochSignal is signal results of backtest
+type StochSignal struct {
+	ID     int     `gorm:"primary_key" json:"-"`
+	Symbol string  `json:"-"`
+	Time   int64   `json:"time"`
+	Price  float64 `json:"-"`
+	Action string  `json:"action"`
+}
+
+// Buy appends buy-signal to Signals, if can not buy, return false
+func (wi *StochSignals) Buy(symbol string, time int64, price float64) bool {
+	if !(wi.CanBuy()) {
+		return false
+	}
+	wi.StochSignals = append(wi.StochSignals, StochSignal{Symbol: symbol, Time: time, Price: price, Action: BUY})
+	return true
+}
+
+// CanBuy judges whether buy or not
+func (wi *StochSignals) CanBuy() bool {
+	lenSignals := len(wi.StochSignals)
+	// not buy or sell
+	if lenSignals == 0 {
+		return true
+	}
+
+	if wi.StochSignals[lenSignals-1].Action == SELL {
+		return true
+	}
+
+	return false
+}
+
+// Sell appends sell-signal to Signals, if can not sell, return false
+func (wi *StochSignals) Sell(symbol string, time int64, price float64) bool {
+	if !(wi.CanSell()) {
+		return false
+	}
+	wi.StochSignals = append(wi.StochSignals, StochSignal{Symbol: symbol, Time: time, Price: price, Action: SELL})
+	return true
+}
+
+// CanSell judges whether sell or not
+func (wi *StochSignals) CanSell() bool {
+	lenSignals := len(wi.StochSignals)
+	// not buy or sell
+	if lenSignals == 0 {
+		return false
+	}
+
+	if wi.StochSignals[lenSignals-1].Action == BUY {
+		return true
+	}
+
+	return false
+}
+
+// Profit calculates profit for backtest
+func (wi *StochSignals) Profit() float64 {
+	profit := 0.0
+	afterSell := 0.0
+	isHolding := false
+
+	for _, signal := range wi.StochSignals {
+		if signal.Action == BUY {
+			profit -= signal.Price
+			isHolding = true
+		} else if signal.Action == SELL {
+			profit += signal.Price
+			afterSell = profit
+			isHolding = false
+		}
+	}
+
+	if isHolding {
+		return afterSell
+	}
+
+	return profit
+}
@@ -0,0 +1,55 @@
+package models
+
+import (
+	"github.com/jumpei00/gostocktrade/app/models/analysis"
+)
+
+// parameters of support and resistance detection
+const (
+	// levelWidth is width of swing points used for the level strategy
+	levelWidth = 3
+	// levelTolerance is ratio of price within which swing points are clustered
+	levelTolerance = 0.01
+	// levelBins is number of bins of volume-at-price
+	levelBins = 50
+	// levelTop is max number of volume levels
+	levelTop = 5
+)
+
+// LevelFrame is support and resistance levels detected from candles,
+// close is of the last candle and each kind of level is relative to it
+type LevelFrame struct {
+	Symbol string           `json:"symbol"`
+	Close  float64          `json:"close"`
+	Levels []analysis.Level `json:"levels"`
+}
+
+// GetLevels returns levels of pivot clustering, volume-at-price and floor pivots for the next day,
+// width is of swing points, if no candles, returns nil
+func GetLevels(symbol string, limit, width int) *LevelFrame {
+	cframe := GetCandleFrame(symbol, limit)
+	lenCandles := len(cframe.Candles)
+	if lenCandles == 0 {
+		return nil
+	}
+
+	highs, lows, closes := cframe.Highs(), cframe.Lows(), cframe.Closes()
+	levels := analysis.PivotLevels(highs, lows, closes, width, levelTolerance)
+	levels = append(levels, analysis.VolumeLevels(highs, lows, closes, cframe.Volumes(), levelBins, levelTop)...)
+	levels = append(levels, analysis.FloorPivots(highs[lenCandles-1], lows[lenCandles-1], closes[lenCandles-1])...)
+
+	return &LevelFrame{Symbol: symbol, Close: closes[lenCandles-1], Levels: levels}
+}
+
+// nearestLevels returns the highest support and the lowest resistance, 0 means nothing
+func nearestLevels(levels []analysis.Level) (support, resistance float64) {
+	for _, level := range levels {
+		if level.Kind == analysis.SUPPORT && level.Price > support {
+			support = level.Price
+		}
+		if level.Kind == analysis.RESISTANCE && (resistance == 0 || level.Price < resistance) {
+			resistance = level.Price
+		}
+	}
+	return support, resistance
+}
@@ -0,0 +1,205 @@
+package models
+
+import (
+	"errors"
+	"math"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/jumpei00/gostocktrade/app/models/analysis"
+	"github.com/jumpei00/gostocktrade/app/models/indicator"
+)
+
+// PairBacktestParam recieves parameters used for backtest of pair trading at json,
+// y is traded against x by hedge ratio
+type PairBacktestParam struct {
+	SymbolY string                       `json:"y"`
+	SymbolX string                       `json:"x"`
+	Period  int                          `json:"period"`
+	Pair    *indicator.PairBacktestParam `json:"pair"`
+	// Sizing is not supported, a trade is always one share of y against ratio shares of x
+	Sizing *Sizing `json:"sizing,omitempty"`
+}
+
+// PairResult is optimized parameters and trades of pair trading,
+// ZScore is of the last candle by the optimized lookback
+type PairResult struct {
+	ID          int                   `gorm:"primary_key" json:"-"`
+	Timestamp   int64                 `json:"timestamp"`
+	SymbolY     string                `json:"y"`
+	SymbolX     string                `json:"x"`
+	Performance float64               `json:"performance"`
+	Lookback    int                   `json:"lookback"`
+	Entry       float64               `json:"entry"`
+	Exit        float64               `json:"exit"`
+	Stop        float64               `json:"stop"`
+	ZScore      float64               `json:"zscore"`
+	Trades      []indicator.PairTrade `gorm:"-" json:"trades"`
+}
+
+// pairFrame is closes of two symbols at common times
+type pairFrame struct {
+	y, x    string
+	times   []int64
+	closesY []float64
+	closesX []float64
+}
+
+// newPairFrame returns closes of y and x at common times in latest limit candles
+func newPairFrame(y, x string, limit int) *pairFrame {
+	times, closes := alignCloses([]string{y, x}, limit)
+	return &pairFrame{y: y, x: x, times: times, closesY: closes[0], closesX: closes[1]}
+}
+
+// BackTest optimizes lookback and thresholds of pair trading
+func (bt *PairBacktestParam) BackTest() (*PairResult, error) {
+	if bt.SymbolY == "" || bt.SymbolX == "" || bt.SymbolY == bt.SymbolX {
+		return nil, errors.New("two different symbols are needed")
+	}
+	if bt.Pair == nil {
+		return nil, errors.New("pair params are empty")
+	}
+	if bt.Sizing != nil {
+		return nil, errors.New("sizing is not supported for pair trading")
+	}
+
+	pframe := newPairFrame(bt.SymbolY, bt.SymbolX, bt.Period)
+	logrus.Infof("pair backtest start: %v, %v, %v", bt.SymbolY, bt.SymbolX, bt.Period)
+
+	performance, lookback, entry, exit, stop := pframe.optimizePair(
+		bt.Pair.PairLookbackLow, bt.Pair.PairLookbackHigh, bt.Pair.PairEntryLow, bt.Pair.PairEntryHigh,
+		bt.Pair.PairExitLow, bt.Pair.PairExitHigh, bt.Pair.PairStopLow, bt.Pair.PairStopHigh)
+	if lookback == 0 {
+		return nil, errors.New("not enough common candles for lookback")
+	}
+
+	entry, exit, stop = math.Round(entry*10)/10, math.Round(exit*10)/10, math.Round(stop*10)/10
+	ratios, zscores := analysis.RollingSpread(pframe.closesY, pframe.closesX, lookback)
+	result := PairResult{
+		Timestamp:   time.Now().Unix() * 1000,
+		SymbolY:     bt.SymbolY,
+		SymbolX:     bt.SymbolX,
+		Performance: math.Round(performance*100) / 100,
+		Lookback:    lookback,
+		Entry:       entry,
+		Exit:        exit,
+		Stop:        stop,
+		ZScore:      math.Round(zscores[len(zscores)-1]*100) / 100,
+		Trades:      pframe.backtestPair(lookback, entry, exit, stop, ratios, zscores).PairTrades,
+	}
+
+	logrus.Infof("pair backtest end: results -> %v", result.Performance)
+	return &result, nil
+}
+
+func (pframe *pairFrame) optimizePair(lowLookback, highLookback int, lowEntry, highEntry, lowExit, highExit, lowStop, highStop float64) (
+	bestPerformance float64, bestLookback int, bestEntry, bestExit, bestStop float64) {
+	logrus.Infof("Pair backtest start: paramas -> %v, %v, %v, %v, %v, %v, %v, %v",
+		lowLookback, highLookback, lowEntry, highEntry, lowExit, highExit, lowStop, highStop)
+
+	profit := 0.0
+	bestEntry, bestExit, bestStop = 2.0, 0.5, 3.0
+
+	for lookback := lowLookback; lookback <= highLookback; lookback++ {
+		if lookback < 2 || lookback >= len(pframe.times) {
+			continue
+		}
+		if bestLookback == 0 {
+			bestLookback = lookback
+		}
+
+		// hedge ratio and z-score depend only on lookback
+		ratios, zscores := analysis.RollingSpread(pframe.closesY, pframe.closesX, lookback)
+		// 1e-9 is margin for error of adding 0.1
+		for entry := lowEntry; entry <= highEntry+1e-9; entry += 0.1 {
+			for exit := lowExit; exit <= highExit+1e-9; exit += 0.1 {
+				for stop := lowStop; stop <= highStop+1e-9; stop += 0.1 {
+					if !(exit < entry && entry < stop) {
+						continue
+					}
+					profit = pframe.backtestPair(lookback, entry, exit, stop, ratios, zscores).Profit()
+					if bestPerformance < profit {
+						bestPerformance = profit
+						bestLookback = lookback
+						bestEntry = entry
+						bestExit = exit
+						bestStop = stop
+					}
+				}
+			}
+		}
+	}
+
+	logrus.Infof("Pair backtest end: results -> %v, %v, %v, %v, %v", bestPerformance, bestLookback, bestEntry, bestExit, bestStop)
+	return bestPerformance, bestLookback, bestEntry, bestExit, bestStop
+}
+
+// backtestPair trades spread by z-score, ratios and zscores are calculated by lookback,
+// long spread when z-score falls below -entry, and short when it rises above entry,
+// closes when it reverts within exit, or reaches stop, trades are done at close
+func (pframe *pairFrame) backtestPair(lookback int, entry, exit, stop float64, ratios, zscores []float64) *indicator.PairTrades {
+	trades := indicator.PairTrades{}
+
+	for day := lookback - 1; day < len(pframe.times); day++ {
+		z := zscores[day]
+		t, priceY, priceX := pframe.times[day], pframe.closesY[day], pframe.closesX[day]
+
+		switch trades.Side() {
+		case indicator.LONGSPREAD:
+			if z >= -exit {
+				trades.Close(t, priceY, priceX, z, indicator.REVERSION)
+			} else if z <= -stop {
+				trades.Close(t, priceY, priceX, z, indicator.STOP)
+			}
+			continue
+		case indicator.SHORTSPREAD:
+			if z <= exit {
+				trades.Close(t, priceY, priceX, z, indicator.REVERSION)
+			} else if z >= stop {
+				trades.Close(t, priceY, priceX, z, indicator.STOP)
+			}
+			continue
+		}
+
+		// negative hedge ratio is not a pair moving together
+		if ratios[day] <= 0 {
+			continue
+		}
+		if -stop < z && z <= -entry {
+			trades.Open(pframe.y, pframe.x, indicator.LONGSPREAD, ratios[day], t, priceY, priceX, z)
+		} else if entry <= z && z < stop {
+			trades.Open(pframe.y, pframe.x, indicator.SHORTSPREAD, ratios[day], t, priceY, priceX, z)
+		}
+	}
+
+	return &trades
+}
+
+// CreatePairResult stores optimized parameters and trades, after deleting existing result of the pair
+func (pr *PairResult) CreatePairResult() error {
+	DeletePairResult(pr.SymbolY, pr.SymbolX)
+	if err := DB.Create(pr).Error; err != nil {
+		return err
+	}
+	if len(pr.Trades) == 0 {
+		return nil
+	}
+	return DB.Create(&pr.Trades).Error
+}
+
+// DeletePairResult deletes optimized parameters and trades of the pair
+func DeletePairResult(y, x string) {
+	DB.Delete(PairResult{}, "symbol_y = ? AND symbol_x = ?", y, x)
+	DB.Delete(indicator.PairTrade{}, "symbol_y = ? AND symbol_x = ?", y, x)
+}
+
+// GetPairResult returns stored result of pair trading, if not backtested, returns error
+func GetPairResult(y, x string) (*PairResult, error) {
+	var pr PairResult
+	if err := DB.Where("symbol_y = ? AND symbol_x = ?", y, x).First(&pr).Error; err != nil {
+		return nil, err
+	}
+	DB.Where("symbol_y = ? AND symbol_x = ?", y, x).Order("entry_time").Find(&pr.Trades)
+	return &pr, nil
+}
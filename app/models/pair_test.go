@@ -0,0 +1,74 @@
+package models_test
+
+import (
+	"github.com/jumpei00/gostocktrade/app/models"
+	"github.com/jumpei00/gostocktrade/app/models/indicator"
+)
+
+func (suite *ModelsTestSuite) TestPairBackTest() {
+	// candles of other symbol, which moves with VOO but deviates periodically
+	other := models.Candles{}
+	for i, candle := range *suite.Candles {
+		candle.ID = 0
+		candle.Symbol = "QQQ"
+		candle.Close = candle.Close*2 + float64(i%20-10)
+		other = append(other, candle)
+	}
+	other.CreateCandles()
+
+	bt := models.PairBacktestParam{
+		SymbolY: "QQQ",
+		SymbolX: "VOO",
+		Period:  300,
+		Pair: &indicator.PairBacktestParam{
+			PairLookbackLow:  20,
+			PairLookbackHigh: 30,
+			PairEntryLow:     1.5,
+			PairEntryHigh:    2.0,
+			PairExitLow:      0,
+			PairExitHigh:     0.5,
+			PairStopLow:      3,
+			PairStopHigh:     3.5,
+		},
+	}
+	result, err := bt.BackTest()
+	suite.Nil(err)
+	suite.True(result.Lookback >= 20 && result.Lookback <= 30)
+	suite.True(result.Exit < result.Entry && result.Entry < result.Stop)
+	suite.NotEmpty(result.Trades)
+	for _, trade := range result.Trades {
+		suite.Equal("QQQ", trade.SymbolY)
+		suite.Equal("VOO", trade.SymbolX)
+		suite.True(trade.QuantityX*trade.QuantityY < 0)
+	}
+
+	// stored
+	suite.Nil(result.CreatePairResult())
+	stored, err := models.GetPairResult("QQQ", "VOO")
+	suite.Nil(err)
+	suite.Equal(result.Lookback, stored.Lookback)
+	suite.Len(stored.Trades, len(result.Trades))
+
+	models.DeletePairResult("QQQ", "VOO")
+	_, err = models.GetPairResult("QQQ", "VOO")
+	suite.NotNil(err)
+
+	// sizing is not supported
+	bt.Sizing = &models.Sizing{Policy: models.FIXEDSHARES, Shares: 10}
+	_, err = bt.BackTest()
+	suite.NotNil(err)
+	bt.Sizing = nil
+
+	// upper bound is tested regardless of error of adding 0.1, only entry 1.4 is above exit
+	bt.Pair.PairEntryLow, bt.Pair.PairEntryHigh = 1.3, 1.4
+	bt.Pair.PairExitLow, bt.Pair.PairExitHigh = 1.3, 1.3
+	bt.Pair.PairStopLow, bt.Pair.PairStopHigh = 3, 3
+	result, err = bt.BackTest()
+	suite.Nil(err)
+	suite.Equal(1.4, result.Entry)
+
+	// same symbol
+	bt.SymbolX = "QQQ"
+	_, err = bt.BackTest()
+	suite.NotNil(err)
+}
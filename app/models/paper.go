@@ -0,0 +1,286 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jumpei00/gostocktrade/app/models/indicator"
+)
+
+// status of paper order
+const (
+	ORDEROPEN      = "open"
+	ORDERFILLED    = "filled"
+	ORDERREJECTED  = "rejected"
+	ORDERCANCELLED = "cancelled"
+)
+
+// PaperAccount is account of paper trading, strategy is such as "ema" or "rule:golden",
+// its signals of symbol from start(unixtime(ms)) are executed automatically, and empty strategy means manual orders only
+type PaperAccount struct {
+	ID          int     `gorm:"primary_key" json:"-"`
+	Name        string  `gorm:"uniqueIndex" json:"name"`
+	Symbol      string  `json:"symbol"`
+	Strategy    string  `json:"strategy"`
+	InitialCash float64 `json:"initial_cash"`
+	Cash        float64 `json:"cash"`
+	Start       int64   `json:"start"`
+	// Sizing is position sizing of signal orders, empty policy means 1 share
+	Sizing Sizing `gorm:"embedded;embeddedPrefix:sizing_" json:"sizing"`
+}
+
+// PaperOrder is order of paper account, time is of the candle when ordered, price is of limit or stop,
+// and quantity 0 of sell means all of the position
+type PaperOrder struct {
+	ID        int     `gorm:"primary_key" json:"id"`
+	AccountID int     `gorm:"index" json:"-"`
+	Symbol    string  `json:"symbol"`
+	Side      string  `json:"side"`
+	Type      string  `json:"type"`
+	Quantity  float64 `json:"quantity"`
+	Price     float64 `json:"price,omitempty"`
+	Strategy  string  `json:"strategy,omitempty"`
+	Time      int64   `json:"time"`
+	Status    string  `json:"status"`
+	Reason    string  `json:"reason,omitempty"`
+}
+
+// PaperFill is execution of order, realized P&L is of sell
+type PaperFill struct {
+	ID          int     `gorm:"primary_key" json:"-"`
+	AccountID   int     `gorm:"index" json:"-"`
+	OrderID     int     `json:"order_id"`
+	Symbol      string  `json:"symbol"`
+	Side        string  `json:"side"`
+	Quantity    float64 `json:"quantity"`
+	Price       float64 `json:"price"`
+	Time        int64   `json:"time"`
+	RealizedPnL float64 `json:"realized_pnl"`
+}
+
+// PaperPosition is holding of symbol, close and following are of the last candle and not stored
+type PaperPosition struct {
+	ID            int     `gorm:"primary_key" json:"-"`
+	AccountID     int     `gorm:"index" json:"-"`
+	Symbol        string  `json:"symbol"`
+	Quantity      float64 `json:"quantity"`
+	AveragePrice  float64 `json:"average_price"`
+	RealizedPnL   float64 `json:"realized_pnl"`
+	Close         float64 `gorm:"-" json:"close"`
+	MarketValue   float64 `gorm:"-" json:"market_value"`
+	UnrealizedPnL float64 `gorm:"-" json:"unrealized_pnl"`
+}
+
+// PaperReport is state of paper account valued by the last candles
+type PaperReport struct {
+	Account       *PaperAccount   `json:"account"`
+	Positions     []PaperPosition `json:"positions"`
+	MarketValue   float64         `json:"market_value"`
+	Equity        float64         `json:"equity"`
+	RealizedPnL   float64         `json:"realized_pnl"`
+	UnrealizedPnL float64         `json:"unrealized_pnl"`
+}
+
+// EquityPoint is equity of paper account at the close of a candle
+type EquityPoint struct {
+	Time        int64   `json:"time"`
+	Cash        float64 `json:"cash"`
+	MarketValue float64 `json:"market_value"`
+	Equity      float64 `json:"equity"`
+}
+
+// signalAction is time and action of a signal of any strategy
+type signalAction struct {
+	Time   int64
+	Action string
+}
+
+// strategySignals returns stored signals of strategy for symbol ordered by time,
+// strategy is json key of optimized strategy, or "rule:" and name of custom strategy
+func strategySignals(symbol, strategy string) ([]signalAction, error) {
+	actions := []signalAction{}
+
+	if strings.HasPrefix(strategy, "rule:") {
+		signals := []indicator.RuleSignal{}
+		DB.Where("Symbol = ? AND Name = ?", symbol, strings.TrimPrefix(strategy, "rule:")).Order("time").Find(&signals)
+		for _, signal := range signals {
+			actions = append(actions, signalAction{Time: signal.Time, Action: signal.Action})
+		}
+		return actions, nil
+	}
+
+	// fields of SignalEvents are such as "ema_signals"
+	signalEvents := GetSignalFrame(symbol, true, true, true, true, true, true, true, true, true, true, true, true, true, true).Signals
+	rv := reflect.ValueOf(*signalEvents)
+	for i := 0; i < rv.NumField(); i++ {
+		if strings.Split(rv.Type().Field(i).Tag.Get("json"), ",")[0] != strategy+"_signals" {
+			continue
+		}
+		signals := rv.Field(i)
+		for j := 0; j < signals.Len(); j++ {
+			actions = append(actions, signalAction{
+				Time:   signals.Index(j).FieldByName("Time").Int(),
+				Action: signals.Index(j).FieldByName("Action").String(),
+			})
+		}
+		sort.Slice(actions, func(i, j int) bool { return actions[i].Time < actions[j].Time })
+		return actions, nil
+	}
+
+	return nil, fmt.Errorf("unknown strategy: %v", strategy)
+}
+
+// CreatePaperAccount validates and creates account, cash is initial cash,
+// when start is 0, signals from today are executed
+func (pa *PaperAccount) CreatePaperAccount() error {
+	if pa.Name == "" {
+		return errors.New("account name is empty")
+	}
+	if pa.InitialCash <= 0 {
+		return errors.New("initial cash must be positive")
+	}
+	pa.Symbol = strings.ToUpper(strings.TrimSpace(pa.Symbol))
+	if pa.Strategy != "" {
+		if pa.Symbol == "" {
+			return errors.New("account symbol is empty, signals of strategy are of the symbol")
+		}
+		if _, err := strategySignals(pa.Symbol, pa.Strategy); err != nil {
+			return err
+		}
+	}
+
+	if err := pa.Sizing.Validate(); err != nil {
+		return err
+	}
+
+	pa.Cash = pa.InitialCash
+	if pa.Start == 0 {
+		now := time.Now().UTC()
+		pa.Start = time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC).Unix() * 1000
+	}
+	return DB.Create(pa).Error
+}
+
+// sizing returns sizing of signal orders, nil means 1 share
+func (pa *PaperAccount) sizing() *Sizing {
+	if pa.Sizing.Policy == "" {
+		return nil
+	}
+	return &pa.Sizing
+}
+
+// GetPaperAccount returns account for name
+func GetPaperAccount(name string) (*PaperAccount, error) {
+	var pa PaperAccount
+	if err := DB.Where("Name = ?", name).First(&pa).Error; err != nil {
+		return nil, err
+	}
+	return &pa, nil
+}
+
+// GetPaperAccounts returns all accounts
+func GetPaperAccounts() []PaperAccount {
+	accounts := []PaperAccount{}
+	DB.Order("name").Find(&accounts)
+	return accounts
+}
+
+// DeletePaperAccount deletes account with the orders, fills and positions
+func DeletePaperAccount(name string) {
+	pa, err := GetPaperAccount(name)
+	if err != nil {
+		return
+	}
+	DB.Delete(PaperOrder{}, "account_id = ?", pa.ID)
+	DB.Delete(PaperFill{}, "account_id = ?", pa.ID)
+	DB.Delete(PaperPosition{}, "account_id = ?", pa.ID)
+	DB.Delete(pa)
+}
+
+// Report returns positions valued by the last candles, and P&L of account
+func (pa *PaperAccount) Report() *PaperReport {
+	report := PaperReport{Account: pa, Positions: []PaperPosition{}, Equity: pa.Cash}
+	DB.Where("account_id = ?", pa.ID).Order("symbol").Find(&report.Positions)
+
+	for i := range report.Positions {
+		position := &report.Positions[i]
+		if cframe := GetCandleFrame(position.Symbol, 1); len(cframe.Candles) != 0 {
+			position.Close = cframe.Candles[0].Close
+		}
+		position.MarketValue = position.Close * position.Quantity
+		position.UnrealizedPnL = (position.Close - position.AveragePrice) * position.Quantity
+
+		report.MarketValue += position.MarketValue
+		report.RealizedPnL += position.RealizedPnL
+		report.UnrealizedPnL += position.UnrealizedPnL
+	}
+	report.Equity += report.MarketValue
+
+	return &report
+}
+
+// EquityHistory returns equity at the close of every candle of traded symbols from start,
+// a symbol without the candle is valued by the previous close
+func (pa *PaperAccount) EquityHistory() []EquityPoint {
+	history := []EquityPoint{}
+
+	fills := []PaperFill{}
+	DB.Where("account_id = ?", pa.ID).Order("time, id").Find(&fills)
+	if len(fills) == 0 {
+		return history
+	}
+
+	closes := map[string]map[int64]float64{}
+	timeSet := map[int64]bool{}
+	for _, fill := range fills {
+		if _, ok := closes[fill.Symbol]; ok {
+			continue
+		}
+		closes[fill.Symbol] = map[int64]float64{}
+		for _, candle := range GetCandleFrameBetween(fill.Symbol, pa.Start, math.MaxInt64).Candles {
+			closes[fill.Symbol][candle.Time] = candle.Close
+			timeSet[candle.Time] = true
+		}
+	}
+	times := []int64{}
+	for t := range timeSet {
+		times = append(times, t)
+	}
+	sort.Slice(times, func(i, j int) bool { return times[i] < times[j] })
+
+	cash := pa.InitialCash
+	quantities, lastCloses := map[string]float64{}, map[string]float64{}
+	next := 0
+	for _, t := range times {
+		// fills are at the open, so included in the day
+		for ; next < len(fills) && fills[next].Time <= t; next++ {
+			fill := fills[next]
+			if fill.Side == indicator.BUY {
+				cash -= fill.Price * fill.Quantity
+				quantities[fill.Symbol] += fill.Quantity
+			} else {
+				cash += fill.Price * fill.Quantity
+				quantities[fill.Symbol] -= fill.Quantity
+			}
+		}
+
+		point := EquityPoint{Time: t, Cash: round(cash)}
+		marketValue := 0.0
+		for symbol, quantity := range quantities {
+			if close, ok := closes[symbol][t]; ok {
+				lastCloses[symbol] = close
+			}
+			marketValue += quantity * lastCloses[symbol]
+		}
+		point.MarketValue = round(marketValue)
+		point.Equity = round(cash + marketValue)
+		history = append(history, point)
+	}
+
+	return history
+}
@@ -0,0 +1,56 @@
+package models_test
+
+import (
+	"github.com/jumpei00/gostocktrade/app/models"
+	"github.com/jumpei00/gostocktrade/app/models/indicator"
+)
+
+func (suite *ModelsTestSuite) TestPaperTrade() {
+	candles := models.GetCandleFrame("VOO", 10).Candles
+	models.DB.Create(&indicator.EmaSignal{Symbol: "VOO", Time: candles[0].Time, Price: candles[0].Close, Action: indicator.BUY})
+	models.DB.Create(&indicator.EmaSignal{Symbol: "VOO", Time: candles[3].Time, Price: candles[3].Close, Action: indicator.BUY})
+	models.DB.Create(&indicator.EmaSignal{Symbol: "VOO", Time: candles[6].Time, Price: candles[6].Close, Action: indicator.SELL})
+
+	pa := models.PaperAccount{Name: "ema", Symbol: "voo", Strategy: "ema", InitialCash: 10000, Start: candles[1].Time}
+	suite.Nil(pa.CreatePaperAccount())
+	suite.Equal("VOO", pa.Symbol)
+	suite.NotNil((&models.PaperAccount{Name: "unknown", Symbol: "VOO", Strategy: "foo", InitialCash: 10000}).CreatePaperAccount())
+	suite.NotNil((&models.PaperAccount{Name: "nosymbol", Strategy: "ema", InitialCash: 10000}).CreatePaperAccount())
+
+	// signals of other symbols are not ordered
+	other := models.PaperAccount{Name: "other", Symbol: "QQQ", Strategy: "ema", InitialCash: 10000, Start: candles[1].Time}
+	suite.Nil(other.CreatePaperAccount())
+
+	// signal before start is not ordered, and signals are ordered once
+	models.PaperTrade("VOO")
+	models.PaperTrade("VOO")
+	account, _ := models.GetPaperAccount("ema")
+	broker := models.NewSimulatedBroker(account)
+	fills, _ := broker.Fills()
+	suite.Len(fills, 2)
+	suite.Equal(candles[4].Open, fills[1].Price)
+	suite.Equal(candles[7].Time, fills[0].Time)
+	suite.InDelta(candles[7].Open-candles[4].Open, fills[0].RealizedPnL, 1e-9)
+
+	report, _ := broker.Account()
+	suite.InDelta(10000+fills[0].RealizedPnL, report.Equity, 1e-9)
+	suite.Equal(fills[0].RealizedPnL, report.RealizedPnL)
+
+	otherFills, _ := models.NewSimulatedBroker(&other).Fills()
+	suite.Empty(otherFills)
+
+	// no position to sell
+	order := models.PaperOrder{Symbol: "VOO", Side: indicator.SELL}
+	suite.Nil(broker.SubmitOrder(&order))
+	suite.Equal(models.ORDEROPEN, order.Status)
+	suite.NotNil(broker.SubmitOrder(&models.PaperOrder{Symbol: "VOO", Side: "HOLD", Quantity: 1}))
+
+	history := account.EquityHistory()
+	suite.Len(history, 9)
+	suite.Equal(10000.0, history[0].Equity)
+
+	models.DeletePaperAccount("ema")
+	models.DeletePaperAccount("other")
+	suite.Empty(models.GetPaperAccounts())
+	models.DeleteBacktestResult("VOO")
+}
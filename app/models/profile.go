@@ -0,0 +1,60 @@
+package models
+
+import (
+	"errors"
+	"math"
+
+	"github.com/jumpei00/gostocktrade/app/models/analysis"
+)
+
+// VwapPoint is anchored VWAP at the time
+type VwapPoint struct {
+	Time int64   `json:"time"`
+	Vwap float64 `json:"vwap"`
+}
+
+// ProfileFrame is volume profile and anchored VWAP of candles between from and to
+type ProfileFrame struct {
+	Symbol  string                  `json:"symbol"`
+	From    int64                   `json:"from"`
+	To      int64                   `json:"to"`
+	Anchor  int64                   `json:"anchor"`
+	Profile *analysis.VolumeProfile `json:"profile"`
+	Vwap    []VwapPoint             `json:"vwap"`
+}
+
+// GetProfileFrame returns volume profile and VWAP anchored at the first candle from anchor,
+// from, to and anchor are unixtime(ms), if no candles or no candle after anchor, returns error
+func GetProfileFrame(symbol string, from, to, anchor int64, bins int) (*ProfileFrame, error) {
+	cframe := GetCandleFrameBetween(symbol, from, to)
+	lenCandles := len(cframe.Candles)
+	if lenCandles == 0 {
+		return nil, errors.New("no candles")
+	}
+
+	anchorDay := -1
+	for day, candle := range cframe.Candles {
+		if candle.Time >= anchor {
+			anchorDay = day
+			break
+		}
+	}
+	if anchorDay < 0 {
+		return nil, errors.New("no candle after anchor")
+	}
+
+	pframe := ProfileFrame{
+		Symbol:  symbol,
+		From:    cframe.Candles[0].Time,
+		To:      cframe.Candles[lenCandles-1].Time,
+		Anchor:  cframe.Candles[anchorDay].Time,
+		Profile: analysis.NewVolumeProfile(cframe.Highs(), cframe.Lows(), cframe.Volumes(), bins),
+	}
+
+	vwap := analysis.AnchoredVwap(cframe.Highs(), cframe.Lows(), cframe.Closes(), cframe.Volumes(), anchorDay)
+	for day := anchorDay; day < lenCandles; day++ {
+		pframe.Vwap = append(pframe.Vwap, VwapPoint{Time: cframe.Candles[day].Time, Vwap: math.Round(vwap[day]*100) / 100})
+	}
+
+	return &pframe, nil
+}
@@ -0,0 +1,329 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// type of data-quality issue
+const (
+	ZEROVOLUME      = "zero_volume"
+	HIGHBELOWLOW    = "high_below_low"
+	CLOSEOUTOFRANGE = "close_out_of_range"
+	DUPLICATEDATE   = "duplicate_date"
+	MISSINGDAY      = "missing_day"
+)
+
+// mode of data-quality validation
+const (
+	// QUALITYREPORT only reports issues, candles are stored as downloaded
+	QUALITYREPORT = "report"
+	// QUALITYREPAIR fixes High/Low and drops duplicates, issues not repairable are kept
+	QUALITYREPAIR = "repair"
+	// QUALITYDROP drops candles with any issue
+	QUALITYDROP = "drop"
+)
+
+// action taken for data-quality issue
+const (
+	ISSUEKEPT     = "kept"
+	ISSUEREPAIRED = "repaired"
+	ISSUEDROPPED  = "dropped"
+)
+
+// QualityReport is result of validation of candles of symbol at the last import,
+// time is of the validation(unixtime(ms)), and counts are by type of issue
+type QualityReport struct {
+	ID              int            `gorm:"primary_key" json:"-"`
+	Symbol          string         `gorm:"uniqueIndex" json:"symbol"`
+	Time            int64          `json:"time"`
+	Mode            string         `json:"mode"`
+	Candles         int            `json:"candles"`
+	Stored          int            `json:"stored"`
+	ZeroVolume      int            `json:"zero_volume"`
+	HighBelowLow    int            `json:"high_below_low"`
+	CloseOutOfRange int            `json:"close_out_of_range"`
+	Duplicates      int            `json:"duplicate_date"`
+	MissingDays     int            `json:"missing_day"`
+	Repaired        int            `json:"repaired"`
+	Dropped         int            `json:"dropped"`
+	Issues          []QualityIssue `gorm:"foreignKey:ReportID" json:"issues,omitempty"`
+}
+
+// QualityIssue is an issue of the candle at time, or the trading day without candle
+type QualityIssue struct {
+	ID       int    `gorm:"primary_key" json:"-"`
+	ReportID int    `json:"-"`
+	Time     int64  `json:"time"`
+	Type     string `json:"type"`
+	Detail   string `json:"detail"`
+	Action   string `json:"action"`
+}
+
+// add appends issue and counts it
+func (qr *QualityReport) add(t int64, issueType, detail, action string) {
+	qr.Issues = append(qr.Issues, QualityIssue{Time: t, Type: issueType, Detail: detail, Action: action})
+
+	switch issueType {
+	case ZEROVOLUME:
+		qr.ZeroVolume++
+	case HIGHBELOWLOW:
+		qr.HighBelowLow++
+	case CLOSEOUTOFRANGE:
+		qr.CloseOutOfRange++
+	case DUPLICATEDATE:
+		qr.Duplicates++
+	case MISSINGDAY:
+		qr.MissingDays++
+	}
+}
+
+// Validate checks candles by ascending time for zero volume, High below Low, Close outside High/Low,
+// duplicated dates and missing trading days, and returns candles to store by mode with the report,
+// zero volume is not an issue when all volumes are zero such as index
+func (cs Candles) Validate(mode string) (Candles, *QualityReport) {
+	if mode != QUALITYREPORT && mode != QUALITYREPAIR && mode != QUALITYDROP {
+		logrus.Warnf("data quality, unknown mode: %v", mode)
+		mode = QUALITYREPORT
+	}
+
+	report := QualityReport{Time: time.Now().Unix() * 1000, Mode: mode, Candles: len(cs), Issues: []QualityIssue{}}
+	if len(cs) == 0 {
+		return Candles{}, &report
+	}
+	report.Symbol = cs[0].Symbol
+
+	sorted := make(Candles, len(cs))
+	copy(sorted, cs)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Time < sorted[j].Time })
+
+	hasVolume := false
+	for _, candle := range sorted {
+		if candle.Volume > 0 {
+			hasVolume = true
+			break
+		}
+	}
+
+	action := ISSUEKEPT
+	switch mode {
+	case QUALITYREPAIR:
+		action = ISSUEREPAIRED
+	case QUALITYDROP:
+		action = ISSUEDROPPED
+	}
+
+	validated := Candles{}
+	for i, candle := range sorted {
+		if i > 0 && candle.Time == sorted[i-1].Time {
+			if mode == QUALITYREPORT {
+				report.add(candle.Time, DUPLICATEDATE, fmt.Sprintf("close %v", candle.Close), ISSUEKEPT)
+				validated = append(validated, candle)
+			} else {
+				report.add(candle.Time, DUPLICATEDATE, fmt.Sprintf("close %v", candle.Close), ISSUEDROPPED)
+				report.Dropped++
+			}
+			continue
+		}
+
+		issues := 0
+		if hasVolume && candle.Volume <= 0 {
+			issues++
+			// volume is not repairable
+			if mode == QUALITYDROP {
+				report.add(candle.Time, ZEROVOLUME, "volume 0", ISSUEDROPPED)
+			} else {
+				report.add(candle.Time, ZEROVOLUME, "volume 0", ISSUEKEPT)
+			}
+		}
+		if candle.High < candle.Low {
+			issues++
+			report.add(candle.Time, HIGHBELOWLOW, fmt.Sprintf("high %v, low %v", candle.High, candle.Low), action)
+			if mode == QUALITYREPAIR {
+				candle.High, candle.Low = candle.Low, candle.High
+			}
+		}
+		// range of inverted High/Low is checked as swapped
+		if candle.Close > maxFloat(candle.High, candle.Low) || candle.Close < minFloat(candle.High, candle.Low) {
+			issues++
+			report.add(candle.Time, CLOSEOUTOFRANGE, fmt.Sprintf("close %v, high %v, low %v", candle.Close, candle.High, candle.Low), action)
+			if mode == QUALITYREPAIR {
+				candle.High, candle.Low = maxFloat(candle.High, candle.Close), minFloat(candle.Low, candle.Close)
+			}
+		}
+
+		switch {
+		case issues == 0 || mode == QUALITYREPORT:
+		case mode == QUALITYDROP:
+			report.Dropped++
+			continue
+		case candle != sorted[i]:
+			report.Repaired++
+		}
+		validated = append(validated, candle)
+	}
+
+	// missing days are between downloaded candles, not filled
+	for i := 1; i < len(sorted); i++ {
+		for _, day := range missingTradingDays(sorted[i-1].Time, sorted[i].Time) {
+			report.add(day, MISSINGDAY, "no candle", ISSUEKEPT)
+		}
+	}
+	sort.SliceStable(report.Issues, func(i, j int) bool { return report.Issues[i].Time < report.Issues[j].Time })
+
+	report.Stored = len(validated)
+	return validated, &report
+}
+
+// maxFloat returns larger one
+func maxFloat(a, b float64) float64 {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+// minFloat returns smaller one
+func minFloat(a, b float64) float64 {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// missingTradingDays returns trading days(unixtime(ms)) after from and before to,
+// both are dates at 00:00 UTC as candles
+func missingTradingDays(from, to int64) []int64 {
+	days := []int64{}
+	end := time.Unix(to/1000, 0).UTC()
+	for day := time.Unix(from/1000, 0).UTC().AddDate(0, 0, 1); day.Before(end); day = day.AddDate(0, 0, 1) {
+		if IsTradingDay(day) {
+			days = append(days, day.Unix()*1000)
+		}
+	}
+	return days
+}
+
+// specialClosures are days NYSE closed other than holidays
+var specialClosures = map[string]bool{
+	"2001-09-11": true, "2001-09-12": true, "2001-09-13": true, "2001-09-14": true,
+	"2004-06-11": true, "2007-01-02": true, "2012-10-29": true, "2012-10-30": true,
+	"2018-12-05": true, "2025-01-09": true,
+}
+
+// IsTradingDay returns whether NYSE is open on the date, weekends, holidays and special closures are not
+func IsTradingDay(date time.Time) bool {
+	if date.Weekday() == time.Saturday || date.Weekday() == time.Sunday {
+		return false
+	}
+	key := date.Format("2006-01-02")
+	return !(specialClosures[key]) && !(marketHolidays(date.Year())[key])
+}
+
+// marketHolidays returns NYSE holidays of year as observed, such as "2021-07-05"
+func marketHolidays(year int) map[string]bool {
+	date := func(month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	}
+	// nth weekday of month, n < 0 is the last
+	nthWeekday := func(month time.Month, weekday time.Weekday, n int) time.Time {
+		if n < 0 {
+			last := date(month+1, 0)
+			return last.AddDate(0, 0, -((int(last.Weekday()) - int(weekday) + 7) % 7))
+		}
+		first := date(month, 1)
+		return first.AddDate(0, 0, (int(weekday)-int(first.Weekday())+7)%7+7*(n-1))
+	}
+	// saturday is observed on friday, and sunday on monday
+	observed := func(t time.Time) time.Time {
+		switch t.Weekday() {
+		case time.Saturday:
+			return t.AddDate(0, 0, -1)
+		case time.Sunday:
+			return t.AddDate(0, 0, 1)
+		}
+		return t
+	}
+
+	holidays := []time.Time{
+		nthWeekday(time.February, time.Monday, 3),
+		easter(year).AddDate(0, 0, -2),
+		nthWeekday(time.May, time.Monday, -1),
+		observed(date(time.July, 4)),
+		nthWeekday(time.September, time.Monday, 1),
+		nthWeekday(time.November, time.Thursday, 4),
+		observed(date(time.December, 25)),
+	}
+	// new year's day on saturday is not observed
+	if newYear := date(time.January, 1); newYear.Weekday() != time.Saturday {
+		holidays = append(holidays, observed(newYear))
+	}
+	if year >= 1998 {
+		holidays = append(holidays, nthWeekday(time.January, time.Monday, 3))
+	}
+	if year >= 2022 {
+		holidays = append(holidays, observed(date(time.June, 19)))
+	}
+
+	keys := map[string]bool{}
+	for _, holiday := range holidays {
+		keys[holiday.Format("2006-01-02")] = true
+	}
+	return keys
+}
+
+// easter returns easter sunday of year by anonymous gregorian algorithm
+func easter(year int) time.Time {
+	a, b, c := year%19, year/100, year%100
+	d, e := b/4, b%4
+	f := (b + 8) / 25
+	g := (b - f + 1) / 3
+	h := (19*a + b - d - g + 15) % 30
+	i, k := c/4, c%4
+	l := (32 + 2*e + 2*i - h - k) % 7
+	m := (a + 11*h + 22*l) / 451
+	month := (h + l - 7*m + 114) / 31
+	day := (h+l-7*m+114)%31 + 1
+	return time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+}
+
+// SaveQualityReport replaces data-quality report of the symbol with the issues
+func SaveQualityReport(report *QualityReport) error {
+	DeleteQualityReport(report.Symbol)
+	report.ID = 0
+	for i := range report.Issues {
+		report.Issues[i].ID, report.Issues[i].ReportID = 0, 0
+	}
+	return DB.Create(report).Error
+}
+
+// GetQualityReport returns data-quality report of symbol with the issues
+func GetQualityReport(symbol string) (*QualityReport, error) {
+	var report QualityReport
+	err := DB.Preload("Issues", func(db *gorm.DB) *gorm.DB { return db.Order("time, id") }).Where("symbol = ?", symbol).First(&report).Error
+	if err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+// GetQualityReports returns data-quality reports of all symbols without the issues
+func GetQualityReports() []QualityReport {
+	reports := []QualityReport{}
+	DB.Order("symbol").Find(&reports)
+	return reports
+}
+
+// DeleteQualityReport deletes data-quality report of symbol with the issues
+func DeleteQualityReport(symbol string) {
+	var report QualityReport
+	if err := DB.Where("symbol = ?", symbol).First(&report).Error; err != nil {
+		return
+	}
+	DB.Delete(QualityIssue{}, "report_id = ?", report.ID)
+	DB.Delete(&report)
+}
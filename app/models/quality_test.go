@@ -0,0 +1,88 @@
+package models_test
+
+import (
+	"time"
+
+	"github.com/jumpei00/gostocktrade/app/models"
+)
+
+// qualityCandles are from 2021-07-01 to 2021-07-09, 07-05 is holiday and 07-07 is missing
+func qualityCandles() models.Candles {
+	day := func(d int) int64 { return time.Date(2021, 7, d, 0, 0, 0, 0, time.UTC).Unix() * 1000 }
+	return models.Candles{
+		{Symbol: "TEST", Time: day(1), Open: 10, High: 11, Low: 9, Close: 10, Volume: 100},
+		{Symbol: "TEST", Time: day(2), Open: 10, High: 11, Low: 9, Close: 10, Volume: 0},
+		{Symbol: "TEST", Time: day(2), Open: 10, High: 11, Low: 9, Close: 10, Volume: 100},
+		{Symbol: "TEST", Time: day(9), Open: 10, High: 11, Low: 9, Close: 12, Volume: 100},
+		{Symbol: "TEST", Time: day(6), Open: 10, High: 9, Low: 11, Close: 10, Volume: 100},
+		{Symbol: "TEST", Time: day(8), Open: 10, High: 11, Low: 9, Close: 10, Volume: 100},
+	}
+}
+
+func (suite *ModelsTestSuite) TestValidateCandles() {
+	candles, report := qualityCandles().Validate(models.QUALITYREPORT)
+	suite.Len(candles, 6)
+	suite.Equal(1, report.ZeroVolume)
+	suite.Equal(1, report.HighBelowLow)
+	suite.Equal(1, report.CloseOutOfRange)
+	suite.Equal(1, report.Duplicates)
+	suite.Equal(1, report.MissingDays)
+	suite.Equal(0, report.Repaired+report.Dropped)
+
+	// high and low are swapped or extended to close, and zero volume is kept
+	candles, report = qualityCandles().Validate(models.QUALITYREPAIR)
+	suite.Len(candles, 5)
+	suite.Equal(2, report.Repaired)
+	suite.Equal(1, report.Dropped)
+	suite.Equal(11.0, candles[2].High)
+	suite.Equal(9.0, candles[2].Low)
+	suite.Equal(12.0, candles[4].High)
+	suite.Equal(0.0, candles[1].Volume)
+
+	candles, report = qualityCandles().Validate(models.QUALITYDROP)
+	suite.Len(candles, 2)
+	suite.Equal(4, report.Dropped)
+	suite.Equal(2, report.Stored)
+	suite.Equal(6, report.Candles)
+
+	// all zero volume such as index is not an issue
+	index := qualityCandles()
+	for i := range index {
+		index[i].Volume = 0
+	}
+	_, report = index.Validate(models.QUALITYREPORT)
+	suite.Equal(0, report.ZeroVolume)
+}
+
+func (suite *ModelsTestSuite) TestIsTradingDay() {
+	date := func(y int, m time.Month, d int) time.Time { return time.Date(y, m, d, 0, 0, 0, 0, time.UTC) }
+
+	suite.True(models.IsTradingDay(date(2021, 7, 6)))
+	suite.True(models.IsTradingDay(date(2021, 12, 31)))
+	suite.False(models.IsTradingDay(date(2021, 7, 3)))
+	suite.False(models.IsTradingDay(date(2021, 7, 5)))
+	suite.False(models.IsTradingDay(date(2021, 1, 18)))
+	suite.False(models.IsTradingDay(date(2021, 4, 2)))
+	suite.False(models.IsTradingDay(date(2021, 5, 31)))
+	suite.False(models.IsTradingDay(date(2022, 6, 20)))
+	suite.False(models.IsTradingDay(date(2020, 11, 26)))
+	suite.False(models.IsTradingDay(date(2023, 1, 2)))
+	suite.False(models.IsTradingDay(date(2018, 12, 5)))
+}
+
+func (suite *ModelsTestSuite) TestQualityReport() {
+	_, report := qualityCandles().Validate(models.QUALITYREPAIR)
+	suite.Nil(models.SaveQualityReport(report))
+	suite.Nil(models.SaveQualityReport(report))
+
+	saved, err := models.GetQualityReport("TEST")
+	suite.Nil(err)
+	suite.Equal(models.QUALITYREPAIR, saved.Mode)
+	suite.Len(saved.Issues, 5)
+	suite.Equal(models.ZEROVOLUME, saved.Issues[0].Type)
+	suite.Len(models.GetQualityReports(), 1)
+
+	models.DeleteQualityReport("TEST")
+	_, err = models.GetQualityReport("TEST")
+	suite.NotNil(err)
+}
@@ -0,0 +1,257 @@
+package rule
+
+import (
+	"fmt"
+	"math"
+)
+
+// Data is price series used for evaluating rule,
+// all series must be the same length and ordered by ascending time
+type Data struct {
+	Opens   []float64
+	Highs   []float64
+	Lows    []float64
+	Closes  []float64
+	Volumes []float64
+}
+
+func (d *Data) len() int {
+	return len(d.Closes)
+}
+
+// priceSeries is names usable in rule as price series
+var priceSeries = map[string]func(d *Data) []float64{
+	"open":   func(d *Data) []float64 { return d.Opens },
+	"high":   func(d *Data) []float64 { return d.Highs },
+	"low":    func(d *Data) []float64 { return d.Lows },
+	"close":  func(d *Data) []float64 { return d.Closes },
+	"volume": func(d *Data) []float64 { return d.Volumes },
+}
+
+type kind int
+
+const (
+	kindNumber kind = iota
+	kindBool
+)
+
+// value is evaluated result of Node,
+// the elements before warmup are not reliable because of indicator calculating
+type value struct {
+	numbers []float64
+	bools   []bool
+	warmup  int
+}
+
+// Node is a part of parsed rule
+type Node interface {
+	kind() kind
+	eval(d *Data) (*value, error)
+}
+
+// Signals evaluates buy and sell condition for each candle,
+// before indicators are ready, both are false
+func (s *Strategy) Signals(d *Data) (buy, sell []bool, err error) {
+	buy = make([]bool, d.len())
+	sell = make([]bool, d.len())
+
+	if s.Buy != nil {
+		if buy, err = evalBool(s.Buy, d); err != nil {
+			return nil, nil, err
+		}
+	}
+	if s.Sell != nil {
+		if sell, err = evalBool(s.Sell, d); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return buy, sell, nil
+}
+
+func evalBool(node Node, d *Data) ([]bool, error) {
+	v, err := node.eval(d)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]bool, d.len())
+	for i := v.warmup; i < d.len(); i++ {
+		result[i] = v.bools[i]
+	}
+	return result, nil
+}
+
+type numberNode struct {
+	value float64
+}
+
+func (n *numberNode) kind() kind { return kindNumber }
+
+func (n *numberNode) eval(d *Data) (*value, error) {
+	numbers := make([]float64, d.len())
+	for i := range numbers {
+		numbers[i] = n.value
+	}
+	return &value{numbers: numbers}, nil
+}
+
+type seriesNode struct {
+	name string
+}
+
+func (s *seriesNode) kind() kind { return kindNumber }
+
+func (s *seriesNode) eval(d *Data) (*value, error) {
+	series := priceSeries[s.name](d)
+	if len(series) != d.len() {
+		return nil, fmt.Errorf("rule: %s has %d values, but close has %d", s.name, len(series), d.len())
+	}
+	return &value{numbers: series}, nil
+}
+
+type unaryNode struct {
+	op      string
+	operand Node
+}
+
+func (u *unaryNode) kind() kind { return u.operand.kind() }
+
+func (u *unaryNode) eval(d *Data) (*value, error) {
+	v, err := u.operand.eval(d)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &value{warmup: v.warmup}
+	switch u.op {
+	case "-":
+		result.numbers = make([]float64, d.len())
+		for i, n := range v.numbers {
+			result.numbers[i] = -n
+		}
+	case "not":
+		result.bools = make([]bool, d.len())
+		for i, b := range v.bools {
+			result.bools[i] = !b
+		}
+	}
+	return result, nil
+}
+
+type binaryNode struct {
+	op          string
+	left, right Node
+}
+
+func newBinary(op string, left, right Node) (Node, error) {
+	want := kindNumber
+	if op == "and" || op == "or" {
+		want = kindBool
+	}
+	if left.kind() != want || right.kind() != want {
+		if want == kindBool {
+			return nil, fmt.Errorf("rule: %q needs conditions on both sides", op)
+		}
+		return nil, fmt.Errorf("rule: %q needs numbers on both sides", op)
+	}
+	return &binaryNode{op: op, left: left, right: right}, nil
+}
+
+func (b *binaryNode) kind() kind {
+	switch b.op {
+	case "+", "-", "*", "/":
+		return kindNumber
+	}
+	return kindBool
+}
+
+func (b *binaryNode) eval(d *Data) (*value, error) {
+	l, err := b.left.eval(d)
+	if err != nil {
+		return nil, err
+	}
+	r, err := b.right.eval(d)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &value{warmup: maxInt(l.warmup, r.warmup)}
+	if b.kind() == kindNumber {
+		result.numbers = make([]float64, d.len())
+	} else {
+		result.bools = make([]bool, d.len())
+	}
+
+	for i := 0; i < d.len(); i++ {
+		switch b.op {
+		case "+":
+			result.numbers[i] = l.numbers[i] + r.numbers[i]
+		case "-":
+			result.numbers[i] = l.numbers[i] - r.numbers[i]
+		case "*":
+			result.numbers[i] = l.numbers[i] * r.numbers[i]
+		case "/":
+			if r.numbers[i] == 0 {
+				result.numbers[i] = math.NaN()
+				continue
+			}
+			result.numbers[i] = l.numbers[i] / r.numbers[i]
+		case "<":
+			result.bools[i] = l.numbers[i] < r.numbers[i]
+		case "<=":
+			result.bools[i] = l.numbers[i] <= r.numbers[i]
+		case ">":
+			result.bools[i] = l.numbers[i] > r.numbers[i]
+		case ">=":
+			result.bools[i] = l.numbers[i] >= r.numbers[i]
+		case "==":
+			result.bools[i] = l.numbers[i] == r.numbers[i]
+		case "!=":
+			result.bools[i] = l.numbers[i] != r.numbers[i]
+		case "and":
+			result.bools[i] = l.bools[i] && r.bools[i]
+		case "or":
+			result.bools[i] = l.bools[i] || r.bools[i]
+		}
+	}
+
+	return result, nil
+}
+
+type callNode struct {
+	name string
+	fn   *function
+	args []Node
+}
+
+func (c *callNode) kind() kind { return c.fn.result }
+
+func (c *callNode) eval(d *Data) (*value, error) {
+	series := []*value{}
+	consts := []float64{}
+
+	for i, arg := range c.args {
+		if c.fn.params[i] == paramConst {
+			consts = append(consts, arg.(*numberNode).value)
+			continue
+		}
+		v, err := arg.eval(d)
+		if err != nil {
+			return nil, err
+		}
+		series = append(series, v)
+	}
+
+	return c.fn.call(d, series, consts)
+}
+
+func maxInt(values ...int) int {
+	max := 0
+	for _, v := range values {
+		if v > max {
+			max = v
+		}
+	}
+	return max
+}
@@ -0,0 +1,93 @@
+package rule_test
+
+import (
+	"testing"
+
+	"github.com/jumpei00/gostocktrade/app/models/rule"
+	"github.com/stretchr/testify/assert"
+)
+
+func newData(closes []float64) *rule.Data {
+	return &rule.Data{Opens: closes, Highs: closes, Lows: closes, Closes: closes, Volumes: closes}
+}
+
+func TestSignals(t *testing.T) {
+	assert := assert.New(t)
+
+	data := newData([]float64{10, 9, 8, 9, 11, 12, 11, 9, 8})
+
+	// crossover of close and previous close
+	strategy, _ := rule.Parse(`
+		buy when crossover(close, prev(close, 1))
+		sell when crossunder(close, prev(close, 1))`)
+	buy, sell, err := strategy.Signals(data)
+
+	assert.Nil(err)
+	assert.Equal([]bool{false, false, false, true, false, false, false, false, false}, buy)
+	assert.Equal([]bool{false, false, false, false, false, false, true, false, false}, sell)
+
+	// arithmetic and logical operator
+	strategy, _ = rule.Parse("buy when close * 2 - 10 >= 12 and not close == 12")
+	buy, sell, err = strategy.Signals(data)
+
+	assert.Nil(err)
+	assert.Equal([]bool{false, false, false, false, true, false, true, false, false}, buy)
+	assert.Equal(make([]bool, len(data.Closes)), sell)
+}
+
+func TestSignalsWarmup(t *testing.T) {
+	assert := assert.New(t)
+
+	data := newData([]float64{1, 2, 3, 4, 5, 6})
+
+	// highest(close, 3) is not ready during first 2 candles
+	strategy, _ := rule.Parse("buy when highest(close, 3) >= lowest(close, 3)")
+	buy, _, err := strategy.Signals(data)
+
+	assert.Nil(err)
+	assert.Equal([]bool{false, false, true, true, true, true}, buy)
+
+	// period is longer than candles, never true
+	strategy, _ = rule.Parse("buy when sma(close, 10) >= 0")
+	buy, _, err = strategy.Signals(data)
+
+	assert.Nil(err)
+	assert.Equal(make([]bool, len(data.Closes)), buy)
+}
+
+func TestProfileFunctions(t *testing.T) {
+	assert := assert.New(t)
+
+	data := newData([]float64{10, 20, 30, 40})
+
+	// vwap of last 2 candles, volume is the same to close
+	strategy, _ := rule.Parse("buy when vwap(2) > 30")
+	buy, _, err := strategy.Signals(data)
+
+	assert.Nil(err)
+	assert.Equal([]bool{false, false, false, true}, buy)
+
+	// point of control of last 3 candles is always between lowest and highest
+	strategy, _ = rule.Parse("buy when poc(3) >= lowest(close, 3) and poc(3) <= highest(close, 3) and vah(3) >= val(3)")
+	buy, _, err = strategy.Signals(data)
+
+	assert.Nil(err)
+	assert.Equal([]bool{false, false, true, true}, buy)
+}
+
+func TestVolatilityFunctions(t *testing.T) {
+	assert := assert.New(t)
+
+	data := newData([]float64{100, 100, 100, 110, 100})
+
+	// volatility appears after price moves
+	strategy, _ := rule.Parse("buy when hv(2) > 0 and atr(2) > 0 and parkinson(2) >= 0 and gk(2) >= 0")
+	buy, _, err := strategy.Signals(data)
+
+	assert.Nil(err)
+	assert.Equal([]bool{false, false, false, true, true}, buy)
+
+	// argument must be constant
+	_, err = rule.Parse("buy when hv_rank(close, 10) > 50")
+	assert.NotNil(err)
+}
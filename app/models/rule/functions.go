@@ -0,0 +1,318 @@
+package rule
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/jumpei00/gostocktrade/app/models/analysis"
+	"github.com/markcheno/go-talib"
+)
+
+type paramKind int
+
+const (
+	// paramSeries is number series, ex) close, ema(close, 9)
+	paramSeries paramKind = iota
+	// paramConst is constant number, ex) period of ema
+	paramConst
+)
+
+// function is usable in rule, ex) ema(close, 9)
+type function struct {
+	params []paramKind
+	result kind
+	call   func(d *Data, series []*value, consts []float64) (*value, error)
+}
+
+// functions is all functions usable in rule
+var functions = map[string]*function{
+	"ema": {
+		params: []paramKind{paramSeries, paramConst},
+		result: kindNumber,
+		call: func(d *Data, series []*value, consts []float64) (*value, error) {
+			n, err := period("ema", consts[0], d)
+			if err != nil || n == 0 {
+				return notReady(d), err
+			}
+			return &value{numbers: talib.Ema(series[0].numbers, n), warmup: series[0].warmup + n}, nil
+		},
+	},
+	"sma": {
+		params: []paramKind{paramSeries, paramConst},
+		result: kindNumber,
+		call: func(d *Data, series []*value, consts []float64) (*value, error) {
+			n, err := period("sma", consts[0], d)
+			if err != nil || n == 0 {
+				return notReady(d), err
+			}
+			return &value{numbers: talib.Sma(series[0].numbers, n), warmup: series[0].warmup + n}, nil
+		},
+	},
+	"rsi": {
+		params: []paramKind{paramSeries, paramConst},
+		result: kindNumber,
+		call: func(d *Data, series []*value, consts []float64) (*value, error) {
+			n, err := period("rsi", consts[0], d)
+			if err != nil || n == 0 {
+				return notReady(d), err
+			}
+			return &value{numbers: talib.Rsi(series[0].numbers, n), warmup: series[0].warmup + n}, nil
+		},
+	},
+	"macd":        macdFunction(0),
+	"macd_signal": macdFunction(1),
+	"macd_hist":   macdFunction(2),
+	"bb_upper":    bbFunction(0),
+	"bb_middle":   bbFunction(1),
+	"bb_lower":    bbFunction(2),
+	"willr": {
+		params: []paramKind{paramConst},
+		result: kindNumber,
+		call: func(d *Data, series []*value, consts []float64) (*value, error) {
+			n, err := period("willr", consts[0], d)
+			if err != nil || n == 0 {
+				return notReady(d), err
+			}
+			return &value{numbers: talib.WillR(d.Highs, d.Lows, d.Closes, n), warmup: n}, nil
+		},
+	},
+	"crossover":  crossFunction(true),
+	"crossunder": crossFunction(false),
+	"prev": {
+		params: []paramKind{paramSeries, paramConst},
+		result: kindNumber,
+		call: func(d *Data, series []*value, consts []float64) (*value, error) {
+			n, err := period("prev", consts[0], d)
+			if err != nil || n == 0 {
+				return notReady(d), err
+			}
+			numbers := make([]float64, d.len())
+			for i := n; i < d.len(); i++ {
+				numbers[i] = series[0].numbers[i-n]
+			}
+			return &value{numbers: numbers, warmup: series[0].warmup + n}, nil
+		},
+	},
+	"highest": windowFunction("highest", math.Max),
+	"lowest":  windowFunction("lowest", math.Min),
+	"abs": {
+		params: []paramKind{paramSeries},
+		result: kindNumber,
+		call: func(d *Data, series []*value, consts []float64) (*value, error) {
+			numbers := make([]float64, d.len())
+			for i, n := range series[0].numbers {
+				numbers[i] = math.Abs(n)
+			}
+			return &value{numbers: numbers, warmup: series[0].warmup}, nil
+		},
+	},
+	"vwap": {
+		params: []paramKind{paramConst},
+		result: kindNumber,
+		call: func(d *Data, series []*value, consts []float64) (*value, error) {
+			n, err := period("vwap", consts[0], d)
+			if err != nil || n == 0 {
+				return notReady(d), err
+			}
+			numbers := make([]float64, d.len())
+			for i := n - 1; i < d.len(); i++ {
+				from := i - n + 1
+				vwap := analysis.AnchoredVwap(d.Highs[from:i+1], d.Lows[from:i+1], d.Closes[from:i+1], d.Volumes[from:i+1], 0)
+				numbers[i] = vwap[n-1]
+			}
+			return &value{numbers: numbers, warmup: n - 1}, nil
+		},
+	},
+	"atr": {
+		params: []paramKind{paramConst},
+		result: kindNumber,
+		call: func(d *Data, series []*value, consts []float64) (*value, error) {
+			n, err := period("atr", consts[0], d)
+			if err != nil || n == 0 {
+				return notReady(d), err
+			}
+			return &value{numbers: talib.Atr(d.Highs, d.Lows, d.Closes, n), warmup: n}, nil
+		},
+	},
+	"hv": volatilityFunction("hv", func(d *Data, n int) []float64 { return analysis.CloseToClose(d.Closes, n) }),
+	"parkinson": volatilityFunction("parkinson", func(d *Data, n int) []float64 {
+		return analysis.Parkinson(d.Highs, d.Lows, n)
+	}),
+	"gk": volatilityFunction("gk", func(d *Data, n int) []float64 {
+		return analysis.GarmanKlass(d.Opens, d.Highs, d.Lows, d.Closes, n)
+	}),
+	"hv_rank": {
+		params: []paramKind{paramConst, paramConst},
+		result: kindNumber,
+		call: func(d *Data, series []*value, consts []float64) (*value, error) {
+			n, err := period("hv_rank", consts[0], d)
+			if err != nil || n == 0 {
+				return notReady(d), err
+			}
+			lookback, err := period("hv_rank", consts[1], d)
+			if err != nil || lookback == 0 || n+lookback >= d.len() {
+				return notReady(d), err
+			}
+			return &value{numbers: analysis.PercentileRank(analysis.CloseToClose(d.Closes, n), lookback), warmup: n + lookback}, nil
+		},
+	},
+	"poc": profileFunction("poc", func(p *analysis.VolumeProfile) float64 { return p.POC }),
+	"vah": profileFunction("vah", func(p *analysis.VolumeProfile) float64 { return p.VAH }),
+	"val": profileFunction("val", func(p *analysis.VolumeProfile) float64 { return p.VAL }),
+}
+
+// macdFunction returns macd(x, fast, slow, signal), index selects macd line, signal line, or histogram
+func macdFunction(index int) *function {
+	return &function{
+		params: []paramKind{paramSeries, paramConst, paramConst, paramConst},
+		result: kindNumber,
+		call: func(d *Data, series []*value, consts []float64) (*value, error) {
+			periods := []int{}
+			for _, c := range consts {
+				n, err := period("macd", c, d)
+				if err != nil || n == 0 {
+					return notReady(d), err
+				}
+				periods = append(periods, n)
+			}
+			macd, signal, hist := talib.Macd(series[0].numbers, periods[0], periods[1], periods[2])
+			lines := [][]float64{macd, signal, hist}
+			warmup := series[0].warmup + maxInt(periods[0], periods[1]) + periods[2]
+			return &value{numbers: lines[index], warmup: warmup}, nil
+		},
+	}
+}
+
+// bbFunction returns bb(x, n, k), index selects upper, middle, or lower band
+func bbFunction(index int) *function {
+	return &function{
+		params: []paramKind{paramSeries, paramConst, paramConst},
+		result: kindNumber,
+		call: func(d *Data, series []*value, consts []float64) (*value, error) {
+			n, err := period("bb", consts[0], d)
+			if err != nil || n == 0 {
+				return notReady(d), err
+			}
+			upper, middle, lower := talib.BBands(series[0].numbers, n, consts[1], consts[1], 0)
+			bands := [][]float64{upper, middle, lower}
+			return &value{numbers: bands[index], warmup: series[0].warmup + n}, nil
+		},
+	}
+}
+
+// crossFunction returns crossover(a, b) when over is true, otherwise crossunder(a, b)
+func crossFunction(over bool) *function {
+	return &function{
+		params: []paramKind{paramSeries, paramSeries},
+		result: kindBool,
+		call: func(d *Data, series []*value, consts []float64) (*value, error) {
+			a, b := series[0].numbers, series[1].numbers
+			bools := make([]bool, d.len())
+			for i := 1; i < d.len(); i++ {
+				if over {
+					bools[i] = a[i-1] < b[i-1] && a[i] >= b[i]
+				} else {
+					bools[i] = a[i-1] > b[i-1] && a[i] <= b[i]
+				}
+			}
+			return &value{bools: bools, warmup: maxInt(series[0].warmup, series[1].warmup) + 1}, nil
+		},
+	}
+}
+
+// windowFunction returns function picking up a value for last n candles by pick
+func windowFunction(name string, pick func(x, y float64) float64) *function {
+	return &function{
+		params: []paramKind{paramSeries, paramConst},
+		result: kindNumber,
+		call: func(d *Data, series []*value, consts []float64) (*value, error) {
+			n, err := period(name, consts[0], d)
+			if err != nil || n == 0 {
+				return notReady(d), err
+			}
+			numbers := make([]float64, d.len())
+			for i := n - 1; i < d.len(); i++ {
+				picked := series[0].numbers[i]
+				for j := i - n + 1; j < i; j++ {
+					picked = pick(picked, series[0].numbers[j])
+				}
+				numbers[i] = picked
+			}
+			return &value{numbers: numbers, warmup: series[0].warmup + n - 1}, nil
+		},
+	}
+}
+
+// volatilityFunction returns function of annualized volatility for last n candles calculated by estimate
+func volatilityFunction(name string, estimate func(d *Data, n int) []float64) *function {
+	return &function{
+		params: []paramKind{paramConst},
+		result: kindNumber,
+		call: func(d *Data, series []*value, consts []float64) (*value, error) {
+			n, err := period(name, consts[0], d)
+			if err != nil || n == 0 {
+				return notReady(d), err
+			}
+			return &value{numbers: estimate(d, n), warmup: n}, nil
+		},
+	}
+}
+
+// profileFunction returns function picking up a price of volume profile for last n candles by pick
+func profileFunction(name string, pick func(p *analysis.VolumeProfile) float64) *function {
+	return &function{
+		params: []paramKind{paramConst},
+		result: kindNumber,
+		call: func(d *Data, series []*value, consts []float64) (*value, error) {
+			n, err := period(name, consts[0], d)
+			if err != nil || n == 0 {
+				return notReady(d), err
+			}
+			numbers := make([]float64, d.len())
+			for i := n - 1; i < d.len(); i++ {
+				from := i - n + 1
+				numbers[i] = pick(analysis.NewVolumeProfile(d.Highs[from:i+1], d.Lows[from:i+1], d.Volumes[from:i+1], analysis.ProfileBins))
+			}
+			return &value{numbers: numbers, warmup: n - 1}, nil
+		},
+	}
+}
+
+// check validates arguments when rule is parsed
+func (fn *function) check(name string, args []Node) error {
+	if len(args) != len(fn.params) {
+		return fmt.Errorf("rule: %s needs %d arguments, but got %d", name, len(fn.params), len(args))
+	}
+
+	for i, arg := range args {
+		switch fn.params[i] {
+		case paramSeries:
+			if arg.kind() != kindNumber {
+				return fmt.Errorf("rule: argument %d of %s must be number, not condition", i+1, name)
+			}
+		case paramConst:
+			if _, ok := arg.(*numberNode); !ok {
+				return fmt.Errorf("rule: argument %d of %s must be constant number", i+1, name)
+			}
+		}
+	}
+
+	return nil
+}
+
+// period converts constant argument to period,
+// when candles are not enough for the period, returns 0
+func period(name string, c float64, d *Data) (int, error) {
+	if c < 1 || c != math.Trunc(c) {
+		return 0, fmt.Errorf("rule: period of %s must be positive integer, but got %v", name, c)
+	}
+	if int(c) >= d.len() {
+		return 0, nil
+	}
+	return int(c), nil
+}
+
+// notReady is value never used, because candles are not enough
+func notReady(d *Data) *value {
+	return &value{numbers: make([]float64, d.len()), bools: make([]bool, d.len()), warmup: d.len()}
+}
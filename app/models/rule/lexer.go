@@ -0,0 +1,96 @@
+package rule
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokNumber
+	tokIdent
+	tokOperator
+	tokLParen
+	tokRParen
+	tokComma
+	tokSeparator
+)
+
+// token is a piece of rule text
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+func (t token) String() string {
+	if t.kind == tokEOF {
+		return "end of rule"
+	}
+	if t.kind == tokSeparator {
+		return "end of line"
+	}
+	return fmt.Sprintf("%q", t.text)
+}
+
+// lex splits rule text into tokens,
+// new line and ";" are used as separator between "buy when" and "sell when"
+func lex(src string) ([]token, error) {
+	tokens := []token{}
+	runes := []rune(src)
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case r == '\n' || r == ';':
+			tokens = append(tokens, token{kind: tokSeparator, text: string(r), pos: i})
+			i++
+		case unicode.IsSpace(r):
+			i++
+		case unicode.IsDigit(r) || (r == '.' && i+1 < len(runes) && unicode.IsDigit(runes[i+1])):
+			start := i
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.') {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokNumber, text: string(runes[start:i]), pos: start})
+		case unicode.IsLetter(r) || r == '_':
+			start := i
+			for i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) || runes[i] == '_') {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokIdent, text: strings.ToLower(string(runes[start:i])), pos: start})
+		case r == '(':
+			tokens = append(tokens, token{kind: tokLParen, text: "(", pos: i})
+			i++
+		case r == ')':
+			tokens = append(tokens, token{kind: tokRParen, text: ")", pos: i})
+			i++
+		case r == ',':
+			tokens = append(tokens, token{kind: tokComma, text: ",", pos: i})
+			i++
+		case strings.ContainsRune("<>=!", r):
+			// two characters operator, "<=", ">=", "==", "!="
+			if i+1 < len(runes) && runes[i+1] == '=' {
+				tokens = append(tokens, token{kind: tokOperator, text: string(runes[i : i+2]), pos: i})
+				i += 2
+				continue
+			}
+			if r == '=' || r == '!' {
+				return nil, fmt.Errorf("rule: unknown operator %q at %d", string(r), i)
+			}
+			tokens = append(tokens, token{kind: tokOperator, text: string(r), pos: i})
+			i++
+		case strings.ContainsRune("+-*/", r):
+			tokens = append(tokens, token{kind: tokOperator, text: string(r), pos: i})
+			i++
+		default:
+			return nil, fmt.Errorf("rule: unexpected character %q at %d", string(r), i)
+		}
+	}
+
+	tokens = append(tokens, token{kind: tokEOF, pos: len(runes)})
+	return tokens, nil
+}
@@ -0,0 +1,342 @@
+package rule
+
+import (
+	"fmt"
+	"strconv"
+)
+
+const (
+	// BUY is keyword of buy rule
+	BUY = "buy"
+	// SELL is keyword of sell rule
+	SELL = "sell"
+)
+
+// Strategy is parsed rule, has condition of buy and sell,
+// ex) "buy when crossover(ema(close, 9), ema(close, 21)) and rsi(close, 14) < 60"
+type Strategy struct {
+	Buy  Node
+	Sell Node
+}
+
+// Parse parses rule text and returns Strategy,
+// the text is some lines of "buy when <condition>" or "sell when <condition>"
+func Parse(src string) (*Strategy, error) {
+	tokens, err := lex(src)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens}
+	strategy := &Strategy{}
+
+	for {
+		p.skipSeparators()
+		if p.peek().kind == tokEOF {
+			break
+		}
+
+		action := p.next()
+		if action.kind != tokIdent || (action.text != BUY && action.text != SELL) {
+			return nil, fmt.Errorf("rule: expected \"buy\" or \"sell\", but got %v at %d", action, action.pos)
+		}
+		if when := p.next(); when.kind != tokIdent || when.text != "when" {
+			return nil, fmt.Errorf("rule: expected \"when\", but got %v at %d", when, when.pos)
+		}
+
+		condition, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		if condition.kind() != kindBool {
+			return nil, fmt.Errorf("rule: condition of %s must be true or false, not number", action.text)
+		}
+
+		if end := p.next(); end.kind != tokSeparator && end.kind != tokEOF {
+			return nil, fmt.Errorf("rule: unexpected %v at %d", end, end.pos)
+		}
+
+		switch action.text {
+		case BUY:
+			if strategy.Buy != nil {
+				return nil, fmt.Errorf("rule: buy condition is defined twice")
+			}
+			strategy.Buy = condition
+		case SELL:
+			if strategy.Sell != nil {
+				return nil, fmt.Errorf("rule: sell condition is defined twice")
+			}
+			strategy.Sell = condition
+		}
+	}
+
+	if strategy.Buy == nil && strategy.Sell == nil {
+		return nil, fmt.Errorf("rule: no buy or sell condition")
+	}
+
+	// evaluates once with a candle, due to find wrong arguments like negative period
+	if _, _, err := strategy.Signals(dryData()); err != nil {
+		return nil, err
+	}
+
+	return strategy, nil
+}
+
+// ParseExpr parses a single expression, ex) ema(close, 9) > ema(close, 21)
+func ParseExpr(src string) (Node, error) {
+	tokens, err := lex(src)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens}
+	node, err := p.parseExpr()
+	if err != nil {
+		return nil, err
+	}
+	if end := p.next(); end.kind != tokEOF {
+		return nil, fmt.Errorf("rule: unexpected %v at %d", end, end.pos)
+	}
+
+	if _, err := node.eval(dryData()); err != nil {
+		return nil, err
+	}
+
+	return node, nil
+}
+
+// dryData is a candle used for validating rule
+func dryData() *Data {
+	return &Data{Opens: []float64{1}, Highs: []float64{1}, Lows: []float64{1}, Closes: []float64{1}, Volumes: []float64{1}}
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *parser) skipSeparators() {
+	for p.peek().kind == tokSeparator {
+		p.next()
+	}
+}
+
+func (p *parser) isKeyword(word string) bool {
+	t := p.peek()
+	return t.kind == tokIdent && t.text == word
+}
+
+func (p *parser) isOperator(ops ...string) bool {
+	t := p.peek()
+	if t.kind != tokOperator {
+		return false
+	}
+	for _, op := range ops {
+		if t.text == op {
+			return true
+		}
+	}
+	return false
+}
+
+// following, recursive descent by the precedence,
+// or < and < not < comparison < "+", "-" < "*", "/" < unary "-"
+func (p *parser) parseExpr() (Node, error) {
+	return p.parseOr()
+}
+
+func (p *parser) parseOr() (Node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.isKeyword("or") {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		if left, err = newBinary("or", left, right); err != nil {
+			return nil, err
+		}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (Node, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for p.isKeyword("and") {
+		p.next()
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		if left, err = newBinary("and", left, right); err != nil {
+			return nil, err
+		}
+	}
+	return left, nil
+}
+
+func (p *parser) parseNot() (Node, error) {
+	if p.isKeyword("not") {
+		p.next()
+		operand, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		if operand.kind() != kindBool {
+			return nil, fmt.Errorf("rule: \"not\" needs condition, not number")
+		}
+		return &unaryNode{op: "not", operand: operand}, nil
+	}
+	return p.parseComparison()
+}
+
+func (p *parser) parseComparison() (Node, error) {
+	left, err := p.parseSum()
+	if err != nil {
+		return nil, err
+	}
+	if p.isOperator("<", "<=", ">", ">=", "==", "!=") {
+		op := p.next().text
+		right, err := p.parseSum()
+		if err != nil {
+			return nil, err
+		}
+		return newBinary(op, left, right)
+	}
+	return left, nil
+}
+
+func (p *parser) parseSum() (Node, error) {
+	left, err := p.parseTerm()
+	if err != nil {
+		return nil, err
+	}
+	for p.isOperator("+", "-") {
+		op := p.next().text
+		right, err := p.parseTerm()
+		if err != nil {
+			return nil, err
+		}
+		if left, err = newBinary(op, left, right); err != nil {
+			return nil, err
+		}
+	}
+	return left, nil
+}
+
+func (p *parser) parseTerm() (Node, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.isOperator("*", "/") {
+		op := p.next().text
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		if left, err = newBinary(op, left, right); err != nil {
+			return nil, err
+		}
+	}
+	return left, nil
+}
+
+func (p *parser) parseUnary() (Node, error) {
+	if p.isOperator("-") {
+		p.next()
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		if num, ok := operand.(*numberNode); ok {
+			return &numberNode{value: -num.value}, nil
+		}
+		if operand.kind() != kindNumber {
+			return nil, fmt.Errorf("rule: \"-\" needs number, not condition")
+		}
+		return &unaryNode{op: "-", operand: operand}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *parser) parsePrimary() (Node, error) {
+	t := p.next()
+	switch t.kind {
+	case tokNumber:
+		value, err := strconv.ParseFloat(t.text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("rule: bad number %q at %d", t.text, t.pos)
+		}
+		return &numberNode{value: value}, nil
+	case tokLParen:
+		node, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		if end := p.next(); end.kind != tokRParen {
+			return nil, fmt.Errorf("rule: expected \")\", but got %v at %d", end, end.pos)
+		}
+		return node, nil
+	case tokIdent:
+		if p.peek().kind == tokLParen {
+			return p.parseCall(t)
+		}
+		if _, ok := priceSeries[t.text]; ok {
+			return &seriesNode{name: t.text}, nil
+		}
+		return nil, fmt.Errorf("rule: unknown name %q at %d", t.text, t.pos)
+	}
+	return nil, fmt.Errorf("rule: unexpected %v at %d", t, t.pos)
+}
+
+func (p *parser) parseCall(name token) (Node, error) {
+	fn, ok := functions[name.text]
+	if !ok {
+		return nil, fmt.Errorf("rule: unknown function %q at %d", name.text, name.pos)
+	}
+
+	// skip "("
+	p.next()
+	args := []Node{}
+	if p.peek().kind != tokRParen {
+		for {
+			arg, err := p.parseExpr()
+			if err != nil {
+				return nil, err
+			}
+			args = append(args, arg)
+			if p.peek().kind != tokComma {
+				break
+			}
+			p.next()
+		}
+	}
+	if end := p.next(); end.kind != tokRParen {
+		return nil, fmt.Errorf("rule: expected \")\", but got %v at %d", end, end.pos)
+	}
+
+	if err := fn.check(name.text, args); err != nil {
+		return nil, err
+	}
+
+	return &callNode{name: name.text, fn: fn, args: args}, nil
+}
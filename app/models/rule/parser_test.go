@@ -0,0 +1,78 @@
+package rule_test
+
+import (
+	"testing"
+
+	"github.com/jumpei00/gostocktrade/app/models/rule"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	assert := assert.New(t)
+
+	strategy, err := rule.Parse("buy when crossover(ema(close,9), ema(close,21)) and rsi(close,14) < 60")
+	assert.Nil(err)
+	assert.NotNil(strategy.Buy)
+	assert.Nil(strategy.Sell)
+
+	// buy and sell, separated by new line or ";"
+	strategy, err = rule.Parse(`
+		BUY WHEN close > sma(close, 20)
+		sell when close < sma(close, 20)`)
+	assert.Nil(err)
+	assert.NotNil(strategy.Buy)
+	assert.NotNil(strategy.Sell)
+
+	strategy, err = rule.Parse("buy when not (rsi(close, 14) > 70 or willr(10) > -20); sell when rsi(close, 14) >= 70")
+	assert.Nil(err)
+	assert.NotNil(strategy.Buy)
+	assert.NotNil(strategy.Sell)
+}
+
+func TestParseError(t *testing.T) {
+	assert := assert.New(t)
+
+	wrongRules := []string{
+		// empty
+		"",
+		// no "when"
+		"buy close > 10",
+		// condition is number
+		"buy when close + 10",
+		// unknown function and name
+		"buy when foo(close) > 1",
+		"buy when price > 1",
+		// wrong arguments
+		"buy when ema(close) > 1",
+		"buy when ema(close, close) > 1",
+		"buy when ema(close, -5) > 1",
+		"buy when ema(close, 2.5) > 1",
+		"buy when crossover(close > 1, close) ",
+		// defined twice
+		"buy when close > 1; buy when close < 1",
+		// syntax
+		"buy when (close > 1",
+		"buy when close > 1)",
+		"buy when close = 1",
+		"buy when close > 1 and",
+		"sell when close # 1",
+	}
+
+	for _, wrong := range wrongRules {
+		_, err := rule.Parse(wrong)
+		assert.NotNil(err, wrong)
+	}
+}
+
+func TestParseExpr(t *testing.T) {
+	assert := assert.New(t)
+
+	_, err := rule.ParseExpr("bb_lower(close, 20, 2)")
+	assert.Nil(err)
+
+	_, err = rule.ParseExpr("close > open")
+	assert.Nil(err)
+
+	_, err = rule.ParseExpr("buy when close > open")
+	assert.NotNil(err)
+}
@@ -0,0 +1,187 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/jumpei00/gostocktrade/app/models/indicator"
+	"github.com/jumpei00/gostocktrade/app/models/rule"
+)
+
+// RuleStrategy is custom strategy written by rule, saved by name
+// ex) buy when crossover(ema(close,9), ema(close,21)) and rsi(close,14) < 60
+type RuleStrategy struct {
+	ID   int    `gorm:"primary_key" json:"-"`
+	Name string `gorm:"uniqueIndex" json:"name"`
+	Rule string `json:"rule"`
+}
+
+// SaveRuleStrategy creates custom strategy, if the name already exists, updates the rule
+func (rs *RuleStrategy) SaveRuleStrategy() error {
+	if rs.Name == "" {
+		return errors.New("rule name is empty")
+	}
+
+	if _, err := rule.Parse(rs.Rule); err != nil {
+		return err
+	}
+
+	return DB.Where(RuleStrategy{Name: rs.Name}).Assign(RuleStrategy{Rule: rs.Rule}).FirstOrCreate(rs).Error
+}
+
+// GetRuleStrategy returns custom strategy for name
+func GetRuleStrategy(name string) (*RuleStrategy, error) {
+	var rs RuleStrategy
+	if err := DB.Where("Name = ?", name).First(&rs).Error; err != nil {
+		return nil, err
+	}
+	return &rs, nil
+}
+
+// GetRuleStrategies returns all custom strategies
+func GetRuleStrategies() []RuleStrategy {
+	strategies := []RuleStrategy{}
+	DB.Order("name").Find(&strategies)
+	return strategies
+}
+
+// DeleteRuleStrategy deletes custom strategy and the signals for all symbols
+func DeleteRuleStrategy(name string) {
+	DB.Delete(RuleStrategy{}, "Name = ?", name)
+	DB.Delete(indicator.RuleSignal{}, "Name = ?", name)
+}
+
+// RuleBacktestParam recieves parameters used for backtest of custom strategy at json
+type RuleBacktestParam struct {
+	Name   string `json:"name"`
+	Symbol string `json:"symbol"`
+	Period int    `json:"period"`
+	// Sizing is position sizing for performance, nil means one share of each signal
+	Sizing *Sizing `json:"sizing,omitempty"`
+}
+
+// RuleResult is backtest result of custom strategy
+type RuleResult struct {
+	Timestamp   int64                  `json:"timestamp,omitempty"`
+	Name        string                 `json:"name"`
+	Symbol      string                 `json:"symbol"`
+	Performance float64                `json:"performance"`
+	Sizing      *Sizing                `json:"sizing,omitempty"`
+	Signals     []indicator.RuleSignal `json:"signals"`
+}
+
+// BackTest executes backtest of custom strategy,
+// unlike BackTestParam, there are no parameters to optimize
+func (bt *RuleBacktestParam) BackTest() (*RuleResult, error) {
+	if bt.Sizing != nil {
+		if err := bt.Sizing.Validate(); err != nil {
+			return nil, fmt.Errorf("sizing: %v", err)
+		}
+	}
+
+	rs, err := GetRuleStrategy(bt.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	strategy, err := rule.Parse(rs.Rule)
+	if err != nil {
+		return nil, err
+	}
+
+	cframe := GetCandleFrame(bt.Symbol, bt.Period)
+	logrus.Infof("rule backtest start: %v, %v, %v", bt.Name, bt.Symbol, bt.Period)
+	cframe.setSizing(bt.Sizing)
+	// custom strategies are filtered as "rule" by entry filters of the optimized params
+	if op := GetOptimizedParamFrame(bt.Symbol).Param; op != nil {
+		cframe.setEntryFilters(op)
+	}
+
+	signals, err := cframe.backtestRule(1, bt.Name, strategy, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	result := RuleResult{
+		Timestamp:   time.Now().Unix() * 1000,
+		Name:        bt.Name,
+		Symbol:      bt.Symbol,
+		Performance: math.Round(cframe.profit(signals)*100) / 100,
+		Sizing:      bt.Sizing,
+		Signals:     signals.RuleSignals,
+	}
+
+	logrus.Infof("rule backtest end: results -> %v", result.Performance)
+	return &result, nil
+}
+
+// CreateRuleResult stores signals of backtest result, after deleting existing signals
+func (rr *RuleResult) CreateRuleResult() error {
+	DeleteRuleSignals(rr.Symbol, rr.Name)
+	if len(rr.Signals) == 0 {
+		return nil
+	}
+	return DB.Create(&rr.Signals).Error
+}
+
+// DeleteRuleSignals deletes signals of custom strategy for symbol
+func DeleteRuleSignals(symbol, name string) {
+	DB.Delete(indicator.RuleSignal{}, "Symbol = ? AND Name = ?", symbol, name)
+}
+
+// GetRuleResult returns stored signals and performance of custom strategy for symbol
+func GetRuleResult(symbol, name string) *RuleResult {
+	signals := indicator.RuleSignals{Name: name}
+	DB.Where("Symbol = ? AND Name = ?", symbol, name).Order("time").Find(&signals.RuleSignals)
+
+	return &RuleResult{
+		Name:        name,
+		Symbol:      symbol,
+		Performance: math.Round(signals.Profit()*100) / 100,
+		Signals:     signals.RuleSignals,
+	}
+}
+
+// ruleSignalTest updates signals of all custom strategies backtested for the symbol,
+// used at SignalTest
+func (cframe *CandleFrame) ruleSignalTest() {
+	if len(cframe.Candles) == 0 {
+		return
+	}
+
+	firstTime := cframe.Candles[0].ID
+	for _, rs := range GetRuleStrategies() {
+		var lastSignal indicator.RuleSignal
+		err := DB.Where("Symbol = ? AND Name = ?", cframe.Symbol, rs.Name).Order("time desc").First(&lastSignal).Error
+		if err != nil {
+			// not backtested yet
+			continue
+		}
+
+		machID, err := MatchTime(cframe.Symbol, lastSignal.Time)
+		if err != nil {
+			continue
+		}
+
+		strategy, err := rule.Parse(rs.Rule)
+		if err != nil {
+			logrus.Warnf("rule parse error: %v, %v", rs.Name, err)
+			continue
+		}
+
+		signals, err := cframe.backtestRule(machID-firstTime+1, rs.Name, strategy, &lastSignal)
+		if err != nil {
+			logrus.Warnf("rule signal test error: %v, %v", rs.Name, err)
+			continue
+		}
+
+		// first signal is lastSignal, already stored
+		if newSignals := signals.RuleSignals[1:]; len(newSignals) != 0 {
+			DB.Create(&newSignals)
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package models_test
+
+import (
+	"github.com/jumpei00/gostocktrade/app/models"
+	"github.com/jumpei00/gostocktrade/app/models/indicator"
+)
+
+const goldenCross = `
+	buy when crossover(ema(close, 9), ema(close, 21))
+	sell when crossunder(ema(close, 9), ema(close, 21))`
+
+func (suite *ModelsTestSuite) TestSaveRuleStrategy() {
+	rs := models.RuleStrategy{Name: "golden", Rule: goldenCross}
+	suite.Nil(rs.SaveRuleStrategy())
+
+	saved, err := models.GetRuleStrategy("golden")
+	suite.Nil(err)
+	suite.Equal(goldenCross, saved.Rule)
+
+	// same name is updated
+	rs = models.RuleStrategy{Name: "golden", Rule: "buy when rsi(close, 14) < 30"}
+	suite.Nil(rs.SaveRuleStrategy())
+	suite.Len(models.GetRuleStrategies(), 1)
+
+	saved, _ = models.GetRuleStrategy("golden")
+	suite.Equal("buy when rsi(close, 14) < 30", saved.Rule)
+
+	// wrong rule or no name
+	rs = models.RuleStrategy{Name: "wrong", Rule: "buy when ema(close) > 1"}
+	suite.NotNil(rs.SaveRuleStrategy())
+	rs = models.RuleStrategy{Name: "", Rule: goldenCross}
+	suite.NotNil(rs.SaveRuleStrategy())
+
+	models.DeleteRuleStrategy("golden")
+	_, err = models.GetRuleStrategy("golden")
+	suite.NotNil(err)
+}
+
+func (suite *ModelsTestSuite) TestRuleBackTest() {
+	rs := models.RuleStrategy{Name: "golden", Rule: goldenCross}
+	rs.SaveRuleStrategy()
+
+	bt := models.RuleBacktestParam{Name: "golden", Symbol: "VOO", Period: 500}
+	result, err := bt.BackTest()
+	suite.Nil(err)
+	suite.Equal("golden", result.Name)
+	suite.NotEmpty(result.Signals)
+
+	// the same to backtest of EMA 9, 21
+	emaSignals := indicator.EmaSignals{}
+	cframe := models.GetCandleFrame("VOO", 500)
+	for _, signal := range result.Signals {
+		suite.Equal("VOO", signal.Symbol)
+		suite.Equal("golden", signal.Name)
+		if signal.Action == indicator.BUY {
+			emaSignals.Buy(signal.Symbol, signal.Time, signal.Price)
+		} else {
+			emaSignals.Sell(signal.Symbol, signal.Time, signal.Price)
+		}
+	}
+	suite.Equal(emaSignals.Profit(), (&indicator.RuleSignals{RuleSignals: result.Signals}).Profit())
+	suite.NotEmpty(cframe.Candles)
+
+	suite.Nil(result.CreateRuleResult())
+	stored := models.GetRuleResult("VOO", "golden")
+	suite.Len(stored.Signals, len(result.Signals))
+	suite.Equal(result.Performance, stored.Performance)
+
+	// 10 fixed shares scale performance of one share
+	sized := models.RuleBacktestParam{Name: "golden", Symbol: "VOO", Period: 500, Sizing: &models.Sizing{Policy: models.FIXEDSHARES, Shares: 10}}
+	sizedResult, err := sized.BackTest()
+	suite.Nil(err)
+	suite.InDelta(result.Performance*10, sizedResult.Performance, 1)
+
+	sized.Sizing = &models.Sizing{Policy: "martingale"}
+	_, err = sized.BackTest()
+	suite.NotNil(err)
+
+	// not saved name
+	bt = models.RuleBacktestParam{Name: "damy", Symbol: "VOO", Period: 500}
+	_, err = bt.BackTest()
+	suite.NotNil(err)
+
+	models.DeleteRuleStrategy("golden")
+	suite.Empty(models.GetRuleResult("VOO", "golden").Signals)
+}
+
+func (suite *ModelsTestSuite) TestRuleSignalTest() {
+	rs := models.RuleStrategy{Name: "golden", Rule: goldenCross}
+	rs.SaveRuleStrategy()
+
+	bt := models.RuleBacktestParam{Name: "golden", Symbol: "VOO", Period: 500}
+	result, _ := bt.BackTest()
+	result.CreateRuleResult()
+	before := models.GetRuleResult("VOO", "golden")
+
+	// signals are not changed when candles are the same
+	models.SignalTest("VOO", 500)
+	after := models.GetRuleResult("VOO", "golden")
+	suite.Equal(len(before.Signals), len(after.Signals))
+	suite.Equal(before.Performance, after.Performance)
+
+	models.DeleteRuleStrategy("golden")
+}
@@ -0,0 +1,204 @@
+package models
+
+import (
+	"errors"
+	"math"
+)
+
+// screenLimit is number of candles used for screening, enough for SMA of 200 days
+const screenLimit = 400
+
+// RsiCondition is satisfied when RSI is below Below
+type RsiCondition struct {
+	Period int     `json:"period"`
+	Below  float64 `json:"below"`
+}
+
+// SmaCondition is satisfied when close is above SMA
+type SmaCondition struct {
+	Period int `json:"period"`
+}
+
+// EmaCrossCondition is satisfied when short EMA crosses over long EMA today
+type EmaCrossCondition struct {
+	Short int `json:"short"`
+	Long  int `json:"long"`
+}
+
+// VolumeSpikeCondition is satisfied when volume is more than Ratio times average volume of the previous Period days
+type VolumeSpikeCondition struct {
+	Period int     `json:"period"`
+	Ratio  float64 `json:"ratio"`
+}
+
+// ScreenParam recieves watchlist and conditions of screener at json,
+// nil condition is not used, and 0 in condition means the default,
+// when symbols are empty, all stored symbols are screened
+type ScreenParam struct {
+	Symbols     []string              `json:"symbols"`
+	Rsi         *RsiCondition         `json:"rsi,omitempty"`
+	Sma         *SmaCondition         `json:"sma,omitempty"`
+	EmaCross    *EmaCrossCondition    `json:"ema_cross,omitempty"`
+	VolumeSpike *VolumeSpikeCondition `json:"volume_spike,omitempty"`
+}
+
+// ScreenMatch is symbol satisfying all conditions, with indicator values of the last candle
+type ScreenMatch struct {
+	Symbol string             `json:"symbol"`
+	Time   int64              `json:"time"`
+	Close  float64            `json:"close"`
+	Values map[string]float64 `json:"values"`
+}
+
+// ScreenFrame is result of screener, skipped symbols have not enough candles for conditions
+type ScreenFrame struct {
+	Matches []ScreenMatch `json:"matches"`
+	Skipped []string      `json:"skipped"`
+}
+
+// setDefaults fills 0 in conditions with default values, RSI 14 below 30, SMA 200, EMA 9 and 21, volume 20 days 2 times
+func (sp *ScreenParam) setDefaults() {
+	if sp.Rsi != nil {
+		if sp.Rsi.Period == 0 {
+			sp.Rsi.Period = 14
+		}
+		if sp.Rsi.Below == 0 {
+			sp.Rsi.Below = 30
+		}
+	}
+	if sp.Sma != nil && sp.Sma.Period == 0 {
+		sp.Sma.Period = 200
+	}
+	if sp.EmaCross != nil {
+		if sp.EmaCross.Short == 0 {
+			sp.EmaCross.Short = 9
+		}
+		if sp.EmaCross.Long == 0 {
+			sp.EmaCross.Long = 21
+		}
+	}
+	if sp.VolumeSpike != nil {
+		if sp.VolumeSpike.Period == 0 {
+			sp.VolumeSpike.Period = 20
+		}
+		if sp.VolumeSpike.Ratio == 0 {
+			sp.VolumeSpike.Ratio = 2
+		}
+	}
+}
+
+// validate checks conditions after filling defaults
+func (sp *ScreenParam) validate() error {
+	if sp.Rsi == nil && sp.Sma == nil && sp.EmaCross == nil && sp.VolumeSpike == nil {
+		return errors.New("no conditions")
+	}
+	if sp.Rsi != nil && (sp.Rsi.Period < 2 || sp.Rsi.Below <= 0 || sp.Rsi.Below > 100) {
+		return errors.New("bad rsi condition")
+	}
+	if sp.Sma != nil && sp.Sma.Period < 1 {
+		return errors.New("bad sma condition")
+	}
+	if sp.EmaCross != nil && (sp.EmaCross.Short < 1 || sp.EmaCross.Short >= sp.EmaCross.Long) {
+		return errors.New("bad ema_cross condition")
+	}
+	if sp.VolumeSpike != nil && (sp.VolumeSpike.Period < 1 || sp.VolumeSpike.Ratio <= 0) {
+		return errors.New("bad volume_spike condition")
+	}
+	return nil
+}
+
+// lookback returns number of candles needed for all conditions
+func (sp *ScreenParam) lookback() int {
+	lookback := 1
+	if sp.Rsi != nil && sp.Rsi.Period+1 > lookback {
+		lookback = sp.Rsi.Period + 1
+	}
+	if sp.Sma != nil && sp.Sma.Period > lookback {
+		lookback = sp.Sma.Period
+	}
+	if sp.EmaCross != nil && sp.EmaCross.Long+1 > lookback {
+		lookback = sp.EmaCross.Long + 1
+	}
+	if sp.VolumeSpike != nil && sp.VolumeSpike.Period+1 > lookback {
+		lookback = sp.VolumeSpike.Period + 1
+	}
+	return lookback
+}
+
+// Screen evaluates conditions against stored candles of each symbol
+func (sp *ScreenParam) Screen() (*ScreenFrame, error) {
+	sp.setDefaults()
+	if err := sp.validate(); err != nil {
+		return nil, err
+	}
+
+	symbols := sp.Symbols
+	if len(symbols) == 0 {
+		symbols = StoredSymbols()
+	}
+
+	limit := screenLimit
+	if sp.lookback() > limit {
+		limit = sp.lookback()
+	}
+
+	sframe := ScreenFrame{Matches: []ScreenMatch{}, Skipped: []string{}}
+	for _, symbol := range symbols {
+		cframe := GetCandleFrame(symbol, limit)
+		if len(cframe.Candles) < sp.lookback() {
+			sframe.Skipped = append(sframe.Skipped, symbol)
+			continue
+		}
+		if match, ok := cframe.screen(sp); ok {
+			sframe.Matches = append(sframe.Matches, *match)
+		}
+	}
+
+	return &sframe, nil
+}
+
+// screen returns indicator values of the last candle, and whether all conditions are satisfied
+func (cframe *CandleFrame) screen(sp *ScreenParam) (*ScreenMatch, bool) {
+	last := len(cframe.Candles) - 1
+	candle := cframe.Candles[last]
+	match := ScreenMatch{Symbol: cframe.Symbol, Time: candle.Time, Close: candle.Close, Values: map[string]float64{}}
+	ok := true
+
+	if sp.Rsi != nil {
+		rsi := cframe.rsi(sp.Rsi.Period)[last]
+		match.Values["rsi"] = round(rsi)
+		ok = ok && rsi < sp.Rsi.Below
+	}
+
+	if sp.Sma != nil {
+		sma := cframe.sma(sp.Sma.Period)[last]
+		match.Values["sma"] = round(sma)
+		ok = ok && candle.Close > sma
+	}
+
+	if sp.EmaCross != nil {
+		short, long := cframe.ema(sp.EmaCross.Short), cframe.ema(sp.EmaCross.Long)
+		match.Values["ema_short"] = round(short[last])
+		match.Values["ema_long"] = round(long[last])
+		ok = ok && short[last-1] < long[last-1] && short[last] >= long[last]
+	}
+
+	if sp.VolumeSpike != nil {
+		volumes := cframe.Volumes()
+		average := 0.0
+		for _, volume := range volumes[last-sp.VolumeSpike.Period : last] {
+			average += volume
+		}
+		average /= float64(sp.VolumeSpike.Period)
+		match.Values["volume"] = candle.Volume
+		match.Values["volume_average"] = round(average)
+		ok = ok && average > 0 && candle.Volume > average*sp.VolumeSpike.Ratio
+	}
+
+	return &match, ok
+}
+
+// round rounds value to 2 decimal places
+func round(value float64) float64 {
+	return math.Round(value*100) / 100
+}
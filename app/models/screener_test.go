@@ -0,0 +1,49 @@
+package models_test
+
+import (
+	"github.com/jumpei00/gostocktrade/app/models"
+)
+
+func (suite *ModelsTestSuite) TestScreen() {
+	// condition which is always satisfied
+	sp := models.ScreenParam{Symbols: []string{"VOO", "QQQ"}, Rsi: &models.RsiCondition{Below: 100}}
+	sframe, err := sp.Screen()
+	suite.Nil(err)
+	suite.Len(sframe.Matches, 1)
+	suite.Equal("VOO", sframe.Matches[0].Symbol)
+	suite.Equal(14, sp.Rsi.Period)
+	suite.Contains(sframe.Matches[0].Values, "rsi")
+	// not stored
+	suite.Equal([]string{"QQQ"}, sframe.Skipped)
+
+	// all conditions, values are returned only for matches
+	sp = models.ScreenParam{
+		Rsi:         &models.RsiCondition{},
+		Sma:         &models.SmaCondition{},
+		EmaCross:    &models.EmaCrossCondition{},
+		VolumeSpike: &models.VolumeSpikeCondition{},
+	}
+	sframe, err = sp.Screen()
+	suite.Nil(err)
+	for _, match := range sframe.Matches {
+		suite.True(match.Values["rsi"] < 30)
+		suite.True(match.Close > match.Values["sma"])
+		suite.True(match.Values["volume"] > match.Values["volume_average"]*2)
+	}
+
+	// too long period
+	sp = models.ScreenParam{Sma: &models.SmaCondition{Period: 10000}}
+	sframe, err = sp.Screen()
+	suite.Nil(err)
+	suite.Equal([]string{"VOO"}, sframe.Skipped)
+
+	// bad conditions
+	for _, sp := range []models.ScreenParam{
+		{},
+		{EmaCross: &models.EmaCrossCondition{Short: 21, Long: 9}},
+		{Rsi: &models.RsiCondition{Below: 120}},
+	} {
+		_, err = sp.Screen()
+		suite.NotNil(err)
+	}
+}
@@ -0,0 +1,38 @@
+package models
+
+import (
+	"github.com/jumpei00/gostocktrade/app/models/analysis"
+)
+
+// SeasonalityFrame is seasonality of candles from "from" to "to",
+// weak is periods including the last candle where returns have been significantly lower
+type SeasonalityFrame struct {
+	Symbol string `json:"symbol"`
+	From   int64  `json:"from"`
+	To     int64  `json:"to"`
+	*analysis.Seasonality
+	Weak []string `json:"weak"`
+}
+
+// GetSeasonalityFrame returns seasonality of latest candles, if no candles, returns nil
+func GetSeasonalityFrame(symbol string, limit int) *SeasonalityFrame {
+	cframe := GetCandleFrame(symbol, limit)
+	lenCandles := len(cframe.Candles)
+	if lenCandles == 0 {
+		return nil
+	}
+
+	times := []int64{}
+	for _, candle := range cframe.Candles {
+		times = append(times, candle.Time)
+	}
+
+	seasonality := analysis.NewSeasonality(times, cframe.Closes())
+	return &SeasonalityFrame{
+		Symbol:      symbol,
+		From:        times[0],
+		To:          times[lenCandles-1],
+		Seasonality: seasonality,
+		Weak:        seasonality.WeakPeriods(),
+	}
+}
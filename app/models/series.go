@@ -0,0 +1,151 @@
+package models
+
+import (
+	"github.com/jumpei00/gostocktrade/app/models/indicator"
+	"github.com/markcheno/go-talib"
+)
+
+// SeriesFrame is indicator lines used for drawing on the chart,
+// every line is aligned to candles of CandleFrame, and 0 means no value
+type SeriesFrame struct {
+	Ichimoku    *IchimokuSeries     `json:"ichimoku,omitempty"`
+	Psar        []float64           `json:"psar,omitempty"`
+	Obv         *ObvSeries          `json:"obv,omitempty"`
+	Mfi         []float64           `json:"mfi,omitempty"`
+	Patterns    []CandlePattern     `json:"patterns,omitempty"`
+	Divergences []DivergenceSegment `json:"divergences,omitempty"`
+	Adx         *AdxSeries          `json:"adx,omitempty"`
+}
+
+// AdxSeries is lines of ADX, +DI and -DI
+type AdxSeries struct {
+	Adx     []float64 `json:"adx"`
+	PlusDI  []float64 `json:"plus_di"`
+	MinusDI []float64 `json:"minus_di"`
+}
+
+// IchimokuSeries is lines of Ichimoku Kinko Hyo
+type IchimokuSeries struct {
+	Tenkan  []float64 `json:"tenkan"`
+	Kijun   []float64 `json:"kijun"`
+	SenkouA []float64 `json:"senkou_a"`
+	SenkouB []float64 `json:"senkou_b"`
+	Chikou  []float64 `json:"chikou"`
+}
+
+// ObvSeries is On-Balance Volume and its EMA
+type ObvSeries struct {
+	Obv []float64 `json:"obv"`
+	Ema []float64 `json:"ema"`
+}
+
+// CandlePattern is candlestick pattern annotated on the candle of Time,
+// direction is 1 for bullish, -1 for bearish, 0 for neither
+type CandlePattern struct {
+	Time      int64  `json:"time"`
+	Name      string `json:"name"`
+	Direction int    `json:"direction"`
+}
+
+// DivergenceSegment is divergence between price and oscillator drawn from a swing point to the next,
+// time is the day when the divergence is confirmed
+type DivergenceSegment struct {
+	Source    string  `json:"source"`
+	Kind      string  `json:"kind"`
+	Direction int     `json:"direction"`
+	Time      int64   `json:"time"`
+	FromTime  int64   `json:"from_time"`
+	FromPrice float64 `json:"from_price"`
+	ToTime    int64   `json:"to_time"`
+	ToPrice   float64 `json:"to_price"`
+}
+
+// GetSeriesFrame returns SeriesFrame for candles of limit,
+// using optimized params if backtest has been done, otherwise default params
+func GetSeriesFrame(symbol string, limit int) *SeriesFrame {
+	cframe := GetCandleFrame(symbol, limit)
+	if len(cframe.Candles) == 0 {
+		return &SeriesFrame{}
+	}
+
+	tenkan, kijun, senkou := 9, 26, 52
+	psarStep, psarMax := 0.02, 0.2
+	obvPeriod, mfiPeriod := 20, 14
+	patternTrend := 5
+	divergenceWidth := 5
+	adxPeriod := 14
+	if opParam := GetOptimizedParamFrame(symbol).Param; opParam != nil {
+		// params of strategies omitted at backtest are 0, defaults are kept
+		if opParam.IchimokuTenkan != 0 {
+			tenkan, kijun, senkou = opParam.IchimokuTenkan, opParam.IchimokuKijun, opParam.IchimokuSenkou
+		}
+		if opParam.PsarStep != 0 {
+			psarStep, psarMax = opParam.PsarStep, opParam.PsarMax
+		}
+		if opParam.ObvPeriod != 0 {
+			obvPeriod = opParam.ObvPeriod
+		}
+		if opParam.MfiPeriod != 0 {
+			mfiPeriod = opParam.MfiPeriod
+		}
+		if opParam.PatternTrend != 0 {
+			patternTrend = opParam.PatternTrend
+		}
+		if opParam.DivergenceWidth != 0 {
+			divergenceWidth = opParam.DivergenceWidth
+		}
+		if opParam.AdxPeriod != 0 {
+			adxPeriod = opParam.AdxPeriod
+		}
+	}
+
+	sframe := SeriesFrame{}
+
+	ichimoku := IchimokuSeries{}
+	ichimoku.Tenkan, ichimoku.Kijun, ichimoku.SenkouA, ichimoku.SenkouB, ichimoku.Chikou = indicator.Ichimoku(
+		cframe.Highs(), cframe.Lows(), cframe.Closes(), tenkan, kijun, senkou)
+	sframe.Ichimoku = &ichimoku
+
+	if len(cframe.Candles) > 1 {
+		sframe.Psar = talib.Sar(cframe.Highs(), cframe.Lows(), psarStep, psarMax)
+	}
+
+	if len(cframe.Candles) > obvPeriod && obvPeriod > 1 {
+		obv := talib.Obv(cframe.Closes(), cframe.Volumes())
+		sframe.Obv = &ObvSeries{Obv: obv, Ema: talib.Ema(obv, obvPeriod)}
+	}
+
+	if len(cframe.Candles) > mfiPeriod && mfiPeriod > 1 {
+		sframe.Mfi = talib.Mfi(cframe.Highs(), cframe.Lows(), cframe.Closes(), cframe.Volumes(), mfiPeriod)
+	}
+
+	for _, pattern := range cframe.patterns(patternTrend) {
+		sframe.Patterns = append(sframe.Patterns, CandlePattern{
+			Time: cframe.Candles[pattern.Index].Time, Name: pattern.Name, Direction: pattern.Direction})
+	}
+
+	for _, source := range []string{indicator.DIVERGENCERSI, indicator.DIVERGENCEMACD} {
+		for _, divergence := range indicator.Divergences(cframe.Highs(), cframe.Lows(), cframe.oscillator(source), divergenceWidth) {
+			// bullish is drawn between lows, bearish is drawn between highs
+			from, to := cframe.Candles[divergence.From], cframe.Candles[divergence.To]
+			fromPrice, toPrice := from.Low, to.Low
+			if divergence.Direction == indicator.BEARISH {
+				fromPrice, toPrice = from.High, to.High
+			}
+			sframe.Divergences = append(sframe.Divergences, DivergenceSegment{
+				Source: source, Kind: divergence.Kind, Direction: divergence.Direction,
+				Time: cframe.Candles[divergence.Index].Time, FromTime: from.Time, FromPrice: fromPrice, ToTime: to.Time, ToPrice: toPrice,
+			})
+		}
+	}
+
+	if len(cframe.Candles) > adxPeriod*2 {
+		sframe.Adx = &AdxSeries{
+			Adx:     talib.Adx(cframe.Highs(), cframe.Lows(), cframe.Closes(), adxPeriod),
+			PlusDI:  talib.PlusDI(cframe.Highs(), cframe.Lows(), cframe.Closes(), adxPeriod),
+			MinusDI: talib.MinusDI(cframe.Highs(), cframe.Lows(), cframe.Closes(), adxPeriod),
+		}
+	}
+
+	return &sframe
+}
@@ -0,0 +1,228 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+
+	"github.com/jumpei00/gostocktrade/app/models/indicator"
+)
+
+// policy of position sizing
+const (
+	FIXEDSHARES   = "fixed_shares"
+	FIXEDNOTIONAL = "fixed_notional"
+	PERCENTEQUITY = "percent_equity"
+	VOLATILITY    = "volatility"
+	KELLY         = "kelly"
+)
+
+// default values of position sizing
+const (
+	defaultSizingCapital = 10000.0
+	defaultSizingAtr     = 14
+	defaultKellyFraction = 0.5
+	// kellyMinTrades is number of closed trades needed for kelly, fixed shares are used until then
+	kellyMinTrades = 5
+)
+
+// Sizing is policy of position sizing, equity is capital and realized profit in backtest,
+// and quantity except fixed shares is whole shares up to equity
+type Sizing struct {
+	Policy string `gorm:"column:policy" json:"policy"`
+	// Shares is quantity of fixed_shares
+	Shares float64 `gorm:"column:shares" json:"shares,omitempty"`
+	// Notional is amount of fixed_notional
+	Notional float64 `gorm:"column:notional" json:"notional,omitempty"`
+	// Percent is percent of equity of percent_equity
+	Percent float64 `gorm:"column:percent" json:"percent,omitempty"`
+	// Risk is percent of equity lost by move of one ATR, of volatility
+	Risk      float64 `gorm:"column:risk" json:"risk,omitempty"`
+	AtrPeriod int     `gorm:"column:atr_period" json:"atr_period,omitempty"`
+	// KellyFraction is multiplied to kelly fraction W - (1 - W) / R, W is win rate and R is payoff of past trades
+	KellyFraction float64 `gorm:"column:kelly_fraction" json:"kelly_fraction,omitempty"`
+	// Capital is initial equity of backtest
+	Capital float64 `gorm:"column:capital" json:"capital,omitempty"`
+}
+
+// setDefaults sets default values to unset params
+func (s *Sizing) setDefaults() {
+	if s.Shares == 0 {
+		s.Shares = 1
+	}
+	if s.AtrPeriod == 0 {
+		s.AtrPeriod = defaultSizingAtr
+	}
+	if s.KellyFraction == 0 {
+		s.KellyFraction = defaultKellyFraction
+	}
+	if s.Capital == 0 {
+		s.Capital = defaultSizingCapital
+	}
+}
+
+// Validate sets defaults and checks params of the policy, empty policy is buying one share
+func (s *Sizing) Validate() error {
+	s.setDefaults()
+
+	switch s.Policy {
+	case "", FIXEDSHARES:
+		if s.Shares < 0 {
+			return errors.New("shares must be positive")
+		}
+	case FIXEDNOTIONAL:
+		if s.Notional <= 0 {
+			return errors.New("notional must be positive")
+		}
+	case PERCENTEQUITY:
+		if s.Percent <= 0 || s.Percent > 100 {
+			return errors.New("percent must be in (0, 100]")
+		}
+	case VOLATILITY:
+		if s.Risk <= 0 || s.Risk > 100 {
+			return errors.New("risk must be in (0, 100]")
+		}
+	case KELLY:
+		if s.KellyFraction <= 0 || s.KellyFraction > 1 {
+			return errors.New("kelly fraction must be in (0, 1]")
+		}
+	default:
+		return fmt.Errorf("unknown sizing policy: %v", s.Policy)
+	}
+
+	if s.Capital < 0 || s.AtrPeriod < 1 {
+		return errors.New("bad capital or atr period")
+	}
+	return nil
+}
+
+// kelly returns kelly fraction of equity from returns of closed trades, not less than 0
+func kelly(returns []float64) float64 {
+	wins, gain, loss := 0.0, 0.0, 0.0
+	for _, r := range returns {
+		if r > 0 {
+			wins++
+			gain += r
+		} else {
+			loss -= r
+		}
+	}
+	if wins == 0 {
+		return 0
+	}
+
+	winRate := wins / float64(len(returns))
+	if loss == 0 {
+		return winRate
+	}
+	payoff := (gain / wins) / (loss / (float64(len(returns)) - wins))
+	return math.Max(winRate-(1-winRate)/payoff, 0)
+}
+
+// quantity returns shares to buy at price, atr is at the day, and returns are of closed trades
+func (s *Sizing) quantity(equity, price, atr float64, returns []float64) float64 {
+	if price <= 0 {
+		return 0
+	}
+
+	var quantity float64
+	switch s.Policy {
+	case "", FIXEDSHARES:
+		return s.Shares
+	case FIXEDNOTIONAL:
+		quantity = s.Notional / price
+	case PERCENTEQUITY:
+		quantity = equity * s.Percent / 100 / price
+	case VOLATILITY:
+		if atr <= 0 {
+			return 0
+		}
+		quantity = equity * s.Risk / 100 / atr
+	case KELLY:
+		if len(returns) < kellyMinTrades {
+			return s.Shares
+		}
+		quantity = equity * s.KellyFraction * kelly(returns) / price
+	}
+
+	// no leverage
+	return math.Floor(math.Max(math.Min(quantity, equity/price), 0))
+}
+
+// setSizing sets sizing policy used for performance of backtest, nil means one share of each signal
+func (cframe *CandleFrame) setSizing(sizing *Sizing) {
+	cframe.sizing, cframe.sizingAtr, cframe.sizingDays = sizing, nil, nil
+	if sizing == nil {
+		return
+	}
+
+	cframe.sizingAtr = cframe.atr(sizing.AtrPeriod)
+	cframe.sizingDays = make(map[int64]int, len(cframe.Candles))
+	for day, candle := range cframe.Candles {
+		cframe.sizingDays[candle.Time] = day
+	}
+}
+
+// profit returns performance of signals, which is profit of sized trades when sizing is set,
+// including dividends when total return is set, the trade not closed is ignored as Profit of signals,
+// stop-and-reverse signals such as psar also hold short from SELL to BUY, which pays dividends
+func (cframe *CandleFrame) profit(signals interface{ Profit() float64 }) float64 {
+	if cframe.sizing == nil && cframe.dividendMode == "" {
+		return signals.Profit()
+	}
+
+	// signals has slice of signal, such as EmaSignals
+	var rv reflect.Value
+	for sv, i := reflect.Indirect(reflect.ValueOf(signals)), 0; i < sv.NumField(); i++ {
+		if sv.Field(i).Kind() == reflect.Slice {
+			rv = sv.Field(i)
+			break
+		}
+	}
+	if !rv.IsValid() {
+		return signals.Profit()
+	}
+	_, reverse := signals.(*indicator.PsarSignals)
+
+	profit := 0.0
+	returns := []float64{}
+	// side is BUY of long or SELL of short, empty means no position
+	side, quantity, entryPrice, entryTime := "", 0.0, 0.0, int64(0)
+	for i := 0; i < rv.Len(); i++ {
+		signal := rv.Index(i)
+		price := signal.FieldByName("Price").Float()
+		time := signal.FieldByName("Time").Int()
+		action := signal.FieldByName("Action").String()
+
+		if side != "" && side != action {
+			value := cframe.dividendValue(entryTime, time, price)
+			gain := value - entryPrice
+			if side == indicator.SELL {
+				gain = entryPrice - value
+			}
+			profit += quantity * gain
+			returns = append(returns, gain/entryPrice)
+			side = ""
+		}
+		if action != indicator.BUY && !(reverse && action == indicator.SELL) {
+			continue
+		}
+
+		// size of signal such as donchian is used without sizing
+		quantity = 1
+		if size := signal.FieldByName("Size"); size.IsValid() {
+			quantity = size.Float()
+		}
+		if cframe.sizing != nil {
+			atr := 0.0
+			if day, ok := cframe.sizingDays[time]; ok {
+				atr = cframe.sizingAtr[day]
+			}
+			quantity = cframe.sizing.quantity(cframe.sizing.Capital+profit, price, atr, returns)
+		}
+		side, entryPrice, entryTime = action, price, time
+	}
+
+	return profit
+}
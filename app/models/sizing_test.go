@@ -0,0 +1,73 @@
+package models_test
+
+import (
+	"github.com/jumpei00/gostocktrade/app/models"
+	"github.com/jumpei00/gostocktrade/app/models/indicator"
+)
+
+func (suite *ModelsTestSuite) TestSizingBacktest() {
+	// 10 fixed shares scale performance of one share
+	sized := backTestParam
+	sized.Sizing = &models.Sizing{Policy: models.FIXEDSHARES, Shares: 10}
+	op, err := sized.BackTest()
+	suite.Nil(err)
+	suite.Equal(models.FIXEDSHARES, op.Sizing.Policy)
+	suite.Equal(10.0, op.Sizing.Shares)
+	suite.Equal(10000.0, op.Sizing.Capital)
+	suite.InDelta(suite.Op.EmaPerformance*10, op.EmaPerformance, 1)
+	suite.InDelta(suite.Op.RsiPerformance*10, op.RsiPerformance, 1)
+	// stop-and-reverse profit includes short
+	suite.InDelta(suite.Op.PsarPerformance*10, op.PsarPerformance, 1)
+	suite.Equal(suite.Op.PsarStep, op.PsarStep)
+
+	// invalid sizing is error
+	sized.Sizing = &models.Sizing{Policy: "martingale"}
+	_, err = sized.BackTest()
+	suite.NotNil(err)
+
+	models.DeleteBacktestResult("VOO")
+}
+
+func (suite *ModelsTestSuite) TestSizingValidate() {
+	for _, sizing := range []models.Sizing{
+		{Policy: models.FIXEDNOTIONAL},
+		{Policy: models.PERCENTEQUITY, Percent: 150},
+		{Policy: models.VOLATILITY},
+		{Policy: models.KELLY, KellyFraction: 2},
+		{Policy: "martingale"},
+	} {
+		suite.NotNil(sizing.Validate())
+	}
+
+	sizing := models.Sizing{Policy: models.KELLY}
+	suite.Nil(sizing.Validate())
+	suite.Equal(0.5, sizing.KellyFraction)
+}
+
+func (suite *ModelsTestSuite) TestSizingPaperTrade() {
+	candles := models.GetCandleFrame("VOO", 10).Candles
+	models.DB.Create(&indicator.EmaSignal{Symbol: "VOO", Time: candles[3].Time, Price: candles[3].Close, Action: indicator.BUY})
+
+	pa := models.PaperAccount{Name: "notional", Symbol: "VOO", Strategy: "ema", InitialCash: 10000, Start: candles[0].Time,
+		Sizing: models.Sizing{Policy: models.FIXEDNOTIONAL, Notional: 5000}}
+	suite.Nil(pa.CreatePaperAccount())
+
+	models.PaperTrade("VOO")
+	fills, _ := models.NewSimulatedBroker(&pa).Fills()
+	suite.Len(fills, 1)
+	suite.Equal(float64(int(5000/candles[3].Close)), fills[0].Quantity)
+
+	// notional above equity is capped, no leverage
+	over := models.PaperAccount{Name: "over", Symbol: "VOO", Strategy: "ema", InitialCash: 10000, Start: candles[0].Time,
+		Sizing: models.Sizing{Policy: models.FIXEDNOTIONAL, Notional: 50000}}
+	suite.Nil(over.CreatePaperAccount())
+
+	models.PaperTrade("VOO")
+	fills, _ = models.NewSimulatedBroker(&over).Fills()
+	suite.Len(fills, 1)
+	suite.Equal(float64(int(10000/candles[3].Close)), fills[0].Quantity)
+
+	models.DeletePaperAccount("notional")
+	models.DeletePaperAccount("over")
+	models.DeleteBacktestResult("VOO")
+}
@@ -0,0 +1,143 @@
+package models
+
+import (
+	"time"
+
+	"github.com/markcheno/go-talib"
+	"github.com/sirupsen/logrus"
+)
+
+// higher timeframes
+const (
+	WEEKLY  = "weekly"
+	MONTHLY = "monthly"
+)
+
+// indicators of higher timeframe used for confirmation
+const (
+	// CONFIRMMACD is bullish when MACD(12, 26, 9) is over its signal
+	CONFIRMMACD = "macd"
+	// CONFIRMEMA is bullish when close is over EMA(10)
+	CONFIRMEMA = "ema"
+	// CONFIRMRSI is bullish when RSI(14) is over 50
+	CONFIRMRSI = "rsi"
+)
+
+// Confirmation is setting that buy of strategy is allowed only when indicator of higher timeframe is bullish,
+// strategy is json key of strategy such as "ema"
+type Confirmation struct {
+	ID        int    `gorm:"primary_key" json:"-"`
+	Symbol    string `json:"-"`
+	Strategy  string `json:"strategy"`
+	Timeframe string `json:"timeframe"`
+	Indicator string `json:"indicator"`
+}
+
+// periodKey returns key of week or month including the time
+func periodKey(unixMilli int64, timeframe string) int {
+	t := time.Unix(unixMilli/1000, 0).UTC()
+	if timeframe == MONTHLY {
+		return t.Year()*100 + int(t.Month())
+	}
+	year, week := t.ISOWeek()
+	return year*100 + week
+}
+
+// Resample converts daily candles to weekly or monthly candles,
+// and returns index of the last completed higher candle for each day, -1 means nothing,
+// the candle including the day is not completed at the day, so using completed one has no look-ahead
+func (cframe *CandleFrame) Resample(timeframe string) (higher *CandleFrame, completed []int) {
+	higher = &CandleFrame{Symbol: cframe.Symbol}
+	completed = make([]int, len(cframe.Candles))
+
+	key := 0
+	for day, candle := range cframe.Candles {
+		if k := periodKey(candle.Time, timeframe); day == 0 || k != key {
+			key = k
+			higher.Candles = append(higher.Candles, Candle{
+				Time: candle.Time, Open: candle.Open, High: candle.High, Low: candle.Low,
+			})
+		}
+
+		current := &higher.Candles[len(higher.Candles)-1]
+		if candle.High > current.High {
+			current.High = candle.High
+		}
+		if candle.Low < current.Low {
+			current.Low = candle.Low
+		}
+		current.Close = candle.Close
+		current.Volume += candle.Volume
+
+		completed[day] = len(higher.Candles) - 2
+	}
+
+	return higher, completed
+}
+
+// bullish returns whether indicator is bullish at each candle
+func (cframe *CandleFrame) bullish(indicator string) []bool {
+	bullish := make([]bool, len(cframe.Candles))
+	closes := cframe.Closes()
+
+	switch indicator {
+	case CONFIRMMACD:
+		// macd is not calculated
+		if len(closes) <= 33 {
+			return bullish
+		}
+		macd, signal, _ := talib.Macd(closes, 12, 26, 9)
+		for i := 33; i < len(closes); i++ {
+			bullish[i] = macd[i] > signal[i]
+		}
+	case CONFIRMEMA:
+		if len(closes) <= 10 {
+			return bullish
+		}
+		ema := talib.Ema(closes, 10)
+		for i := 9; i < len(closes); i++ {
+			bullish[i] = closes[i] > ema[i]
+		}
+	case CONFIRMRSI:
+		if len(closes) <= 14 {
+			return bullish
+		}
+		rsi := talib.Rsi(closes, 14)
+		for i := 14; i < len(closes); i++ {
+			bullish[i] = rsi[i] > 50
+		}
+	}
+
+	return bullish
+}
+
+// confirmed returns whether indicator of the last completed higher candle is bullish at each day
+func (cframe *CandleFrame) confirmed(timeframe, indicator string) []bool {
+	higher, completed := cframe.Resample(timeframe)
+	bullish := higher.bullish(indicator)
+
+	allowed := make([]bool, len(cframe.Candles))
+	for day, index := range completed {
+		allowed[day] = index >= 0 && bullish[index]
+	}
+	return allowed
+}
+
+// setConfirmationFilter allows buy of strategies only when confirmed by higher timeframe
+func (cframe *CandleFrame) setConfirmationFilter(confirmations []Confirmation) {
+	for _, confirmation := range confirmations {
+		if _, ok := filteredStrategies[confirmation.Strategy]; !ok {
+			logrus.Warnf("confirmation, unknown strategy: %v", confirmation.Strategy)
+			continue
+		}
+		if confirmation.Timeframe != WEEKLY && confirmation.Timeframe != MONTHLY {
+			logrus.Warnf("confirmation, unknown timeframe: %v", confirmation.Timeframe)
+			continue
+		}
+		if confirmation.Indicator != CONFIRMMACD && confirmation.Indicator != CONFIRMEMA && confirmation.Indicator != CONFIRMRSI {
+			logrus.Warnf("confirmation, unknown indicator: %v", confirmation.Indicator)
+			continue
+		}
+		cframe.addEntryFilter(confirmation.Strategy, cframe.confirmed(confirmation.Timeframe, confirmation.Indicator))
+	}
+}
@@ -0,0 +1,82 @@
+package models_test
+
+import (
+	"time"
+
+	"github.com/jumpei00/gostocktrade/app/models"
+	"github.com/jumpei00/gostocktrade/app/models/indicator"
+	"github.com/markcheno/go-talib"
+)
+
+func (suite *ModelsTestSuite) TestResample() {
+	cframe := models.GetCandleFrame("VOO", 500)
+	weekly, completed := cframe.Resample(models.WEEKLY)
+	suite.NotEmpty(weekly.Candles)
+	suite.Len(completed, len(cframe.Candles))
+
+	// first week is not completed
+	suite.Equal(-1, completed[0])
+
+	for day, candle := range cframe.Candles {
+		if completed[day] < 0 {
+			continue
+		}
+		// completed week is before the day
+		_, week := time.Unix(candle.Time/1000, 0).UTC().ISOWeek()
+		_, completedWeek := time.Unix(weekly.Candles[completed[day]].Time/1000, 0).UTC().ISOWeek()
+		suite.NotEqual(week, completedWeek)
+		suite.True(weekly.Candles[completed[day]].Time < candle.Time)
+		if day > 0 {
+			suite.True(completed[day-1] <= completed[day])
+		}
+	}
+
+	monthly, _ := cframe.Resample(models.MONTHLY)
+	suite.True(len(monthly.Candles) < len(weekly.Candles))
+	high := 0.0
+	for _, candle := range cframe.Candles {
+		if candle.High > high {
+			high = candle.High
+		}
+	}
+	monthlyHigh := 0.0
+	for _, candle := range monthly.Candles {
+		if candle.High > monthlyHigh {
+			monthlyHigh = candle.High
+		}
+	}
+	suite.Equal(high, monthlyHigh)
+}
+
+func (suite *ModelsTestSuite) TestConfirmation() {
+	confirmed := backTestParam
+	confirmed.Confirmations = []models.Confirmation{
+		{Strategy: "ema", Timeframe: models.WEEKLY, Indicator: models.CONFIRMMACD},
+	}
+	op, _ := confirmed.BackTest()
+	suite.Len(op.Confirmations, 1)
+
+	// buy of ema is only when weekly macd is bullish
+	cframe := models.GetCandleFrame("VOO", 500)
+	weekly, completed := cframe.Resample(models.WEEKLY)
+	macd, signal, _ := talib.Macd(weekly.Closes(), 12, 26, 9)
+	for _, s := range op.EmaSignals {
+		if s.Action != indicator.BUY {
+			continue
+		}
+		for day, candle := range cframe.Candles {
+			if candle.Time == s.Time {
+				suite.True(completed[day] >= 33)
+				suite.True(macd[completed[day]] > signal[completed[day]])
+			}
+		}
+	}
+
+	// confirmations are stored with optimized params
+	op.CreateBacktestResult()
+	stored := models.GetOptimizedParamFrame("VOO").Param
+	suite.Equal("ema", stored.Confirmations[0].Strategy)
+	suite.True(models.SignalTest("VOO", 500))
+
+	models.DeleteBacktestResult("VOO")
+}
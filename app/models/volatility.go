@@ -0,0 +1,70 @@
+package models
+
+import (
+	"github.com/jumpei00/gostocktrade/app/models/analysis"
+	"github.com/markcheno/go-talib"
+)
+
+// VolatilitySeries is historical volatilities for the window, the values are aligned to candles and 0 means no value
+type VolatilitySeries struct {
+	Window       int       `json:"window"`
+	CloseToClose []float64 `json:"close_to_close"`
+	Parkinson    []float64 `json:"parkinson"`
+	GarmanKlass  []float64 `json:"garman_klass"`
+	// Rank is percentile rank of close-to-close volatility
+	Rank []float64 `json:"rank"`
+	// Scale is scale of position size for target volatility, only when target is given
+	Scale []float64 `json:"scale,omitempty"`
+}
+
+// VolatilityFrame is ATR and historical volatilities of candles
+type VolatilityFrame struct {
+	Symbol       string             `json:"symbol"`
+	Times        []int64            `json:"times"`
+	AtrPeriod    int                `json:"atr_period"`
+	Atr          []float64          `json:"atr"`
+	Volatilities []VolatilitySeries `json:"volatilities"`
+}
+
+// GetVolatilityFrame returns volatilities for each window, the rank is compared with last rankLookback days,
+// when target is over 0, scale of position size for the target volatility is included, if no candles, returns nil
+func GetVolatilityFrame(symbol string, limit int, windows []int, atrPeriod, rankLookback int, target float64) *VolatilityFrame {
+	cframe := GetCandleFrame(symbol, limit)
+	lenCandles := len(cframe.Candles)
+	if lenCandles == 0 {
+		return nil
+	}
+
+	vframe := VolatilityFrame{Symbol: symbol, AtrPeriod: atrPeriod, Atr: cframe.atr(atrPeriod)}
+	for _, candle := range cframe.Candles {
+		vframe.Times = append(vframe.Times, candle.Time)
+	}
+
+	opens, highs, lows, closes := cframe.Opens(), cframe.Highs(), cframe.Lows(), cframe.Closes()
+	for _, window := range windows {
+		series := VolatilitySeries{
+			Window:       window,
+			CloseToClose: analysis.CloseToClose(closes, window),
+			Parkinson:    analysis.Parkinson(highs, lows, window),
+			GarmanKlass:  analysis.GarmanKlass(opens, highs, lows, closes, window),
+		}
+		series.Rank = analysis.PercentileRank(series.CloseToClose, rankLookback)
+		if target > 0 {
+			series.Scale = make([]float64, lenCandles)
+			for day, volatility := range series.CloseToClose {
+				series.Scale[day] = analysis.VolatilityScale(volatility, target)
+			}
+		}
+		vframe.Volatilities = append(vframe.Volatilities, series)
+	}
+
+	return &vframe
+}
+
+// atr returns ATR aligned to candles, 0 means no value
+func (cframe *CandleFrame) atr(period int) []float64 {
+	if period < 1 || len(cframe.Candles) <= period {
+		return make([]float64, len(cframe.Candles))
+	}
+	return talib.Atr(cframe.Highs(), cframe.Lows(), cframe.Closes(), period)
+}
@@ -0,0 +1,222 @@
+package models
+
+import (
+	"errors"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/jumpei00/gostocktrade/app/models/indicator"
+)
+
+// Watchlist is named list of symbols scanned every day,
+// symbols are stored as comma separated SymbolList
+type Watchlist struct {
+	ID         int      `gorm:"primary_key" json:"-"`
+	Name       string   `gorm:"uniqueIndex" json:"name"`
+	SymbolList string   `json:"-"`
+	Symbols    []string `gorm:"-" json:"symbols"`
+}
+
+// SaveWatchlist creates watchlist, if the name already exists, updates the symbols
+func (wl *Watchlist) SaveWatchlist() error {
+	if wl.Name == "" {
+		return errors.New("watchlist name is empty")
+	}
+
+	symbols := []string{}
+	for _, symbol := range wl.Symbols {
+		symbol = strings.ToUpper(strings.TrimSpace(symbol))
+		if symbol == "" {
+			continue
+		}
+		if strings.Contains(symbol, ",") {
+			return errors.New("symbol must not include comma")
+		}
+		symbols = append(symbols, symbol)
+	}
+	if len(symbols) == 0 {
+		return errors.New("watchlist symbols are empty")
+	}
+	wl.Symbols = symbols
+	wl.SymbolList = strings.Join(symbols, ",")
+
+	return DB.Where(Watchlist{Name: wl.Name}).Assign(Watchlist{SymbolList: wl.SymbolList}).FirstOrCreate(wl).Error
+}
+
+// GetWatchlist returns watchlist for name
+func GetWatchlist(name string) (*Watchlist, error) {
+	var wl Watchlist
+	if err := DB.Where("Name = ?", name).First(&wl).Error; err != nil {
+		return nil, err
+	}
+	wl.Symbols = strings.Split(wl.SymbolList, ",")
+	return &wl, nil
+}
+
+// GetWatchlists returns all watchlists
+func GetWatchlists() []Watchlist {
+	watchlists := []Watchlist{}
+	DB.Order("name").Find(&watchlists)
+	for i := range watchlists {
+		watchlists[i].Symbols = strings.Split(watchlists[i].SymbolList, ",")
+	}
+	return watchlists
+}
+
+// DeleteWatchlist deletes watchlist, candles and digests are kept
+func DeleteWatchlist(name string) {
+	DB.Delete(Watchlist{}, "Name = ?", name)
+}
+
+// WatchlistSymbols returns symbols of all watchlists without duplicates, ordered by name
+func WatchlistSymbols() []string {
+	unique := map[string]bool{}
+	for _, wl := range GetWatchlists() {
+		for _, symbol := range wl.Symbols {
+			unique[symbol] = true
+		}
+	}
+
+	symbols := []string{}
+	for symbol := range unique {
+		symbols = append(symbols, symbol)
+	}
+	sort.Strings(symbols)
+	return symbols
+}
+
+// Digest is signals of every watchlist symbol on the day of the scan, date is such as 2021-01-04
+type Digest struct {
+	ID        int           `gorm:"primary_key" json:"-"`
+	Date      string        `gorm:"uniqueIndex" json:"date"`
+	Timestamp int64         `json:"timestamp"`
+	Entries   []DigestEntry `gorm:"foreignKey:DigestID" json:"entries"`
+}
+
+// DigestEntry is result of the scan for a symbol, time and close are of the last candle,
+// signals are strategies which signaled at the last candle, and error is why the symbol is not scanned
+type DigestEntry struct {
+	ID       int            `gorm:"primary_key" json:"-"`
+	DigestID int            `json:"-"`
+	Symbol   string         `json:"symbol"`
+	Time     int64          `json:"time,omitempty"`
+	Close    float64        `json:"close,omitempty"`
+	Error    string         `json:"error,omitempty"`
+	Signals  []DigestSignal `gorm:"foreignKey:EntryID" json:"signals"`
+}
+
+// DigestSignal is BUY or SELL of a strategy, custom strategy is such as "rule:golden"
+type DigestSignal struct {
+	ID       int    `gorm:"primary_key" json:"-"`
+	EntryID  int    `json:"-"`
+	Strategy string `json:"strategy"`
+	Action   string `json:"action"`
+}
+
+// ScanWatchlists syncs candles of every watchlist symbol by sync, updates signals by SignalTest,
+// and stores digest of signals at the last candle, date is of the scan,
+// and digest of the same date is replaced
+func ScanWatchlists(date string, period int, sync func(symbol string, period int) error) (*Digest, error) {
+	digest := Digest{Date: date, Timestamp: time.Now().Unix() * 1000, Entries: []DigestEntry{}}
+	logrus.Infof("watchlist scan start: %v", date)
+
+	for _, symbol := range WatchlistSymbols() {
+		entry := DigestEntry{Symbol: symbol, Signals: []DigestSignal{}}
+
+		if err := sync(symbol, period); err != nil {
+			logrus.Warnf("watchlist sync error: %v, %v", symbol, err)
+			entry.Error = err.Error()
+			digest.Entries = append(digest.Entries, entry)
+			continue
+		}
+
+		cframe := GetCandleFrame(symbol, 1)
+		if len(cframe.Candles) == 0 {
+			entry.Error = "no candles"
+			digest.Entries = append(digest.Entries, entry)
+			continue
+		}
+		entry.Time, entry.Close = cframe.Candles[0].Time, cframe.Candles[0].Close
+
+		if !(SignalTest(symbol, period)) {
+			entry.Error = "not backtested"
+		}
+		entry.Signals = append(entry.Signals, todaySignals(symbol)...)
+		digest.Entries = append(digest.Entries, entry)
+		NotifyAlerts(symbol)
+		PaperTrade(symbol)
+	}
+
+	DeleteDigest(date)
+	if err := DB.Create(&digest).Error; err != nil {
+		return nil, err
+	}
+
+	logrus.Infof("watchlist scan end: %v symbols", len(digest.Entries))
+	return &digest, nil
+}
+
+// todaySignals returns signals of optimized strategies and custom strategies at the last candle
+func todaySignals(symbol string) []DigestSignal {
+	signals := []DigestSignal{}
+
+	if tframe := GetTradeState(symbol); tframe.Trade != nil {
+		// fields of Trade are pairs of "last_xxx" and "today_xxx"
+		rv := reflect.ValueOf(*tframe.Trade)
+		rt := rv.Type()
+		for i := 0; i < rt.NumField(); i++ {
+			key := rt.Field(i).Tag.Get("json")
+			if !(strings.HasPrefix(key, "last_")) || !(rv.Field(i + 1).Bool()) {
+				continue
+			}
+			signals = append(signals, DigestSignal{Strategy: strings.TrimPrefix(key, "last_"), Action: rv.Field(i).String()})
+		}
+	}
+
+	lastCandleTime, err := LastCandleTime(symbol)
+	if err != nil {
+		return signals
+	}
+	for _, rs := range GetRuleStrategies() {
+		var lastSignal indicator.RuleSignal
+		err := DB.Where("Symbol = ? AND Name = ?", symbol, rs.Name).Order("time desc").First(&lastSignal).Error
+		if err == nil && lastSignal.Time == lastCandleTime {
+			signals = append(signals, DigestSignal{Strategy: "rule:" + rs.Name, Action: lastSignal.Action})
+		}
+	}
+
+	return signals
+}
+
+// GetDigest returns digest of date, when date is empty, the latest digest
+func GetDigest(date string) (*Digest, error) {
+	var digest Digest
+	query := DB.Preload("Entries.Signals")
+	if date != "" {
+		query = query.Where("Date = ?", date)
+	}
+	if err := query.Order("date desc").First(&digest).Error; err != nil {
+		return nil, err
+	}
+	return &digest, nil
+}
+
+// DeleteDigest deletes digest of date with the entries and signals
+func DeleteDigest(date string) {
+	var digest Digest
+	if err := DB.Where("Date = ?", date).First(&digest).Error; err != nil {
+		return
+	}
+
+	var entryIDs []int
+	DB.Model(&DigestEntry{}).Where("digest_id = ?", digest.ID).Pluck("id", &entryIDs)
+	if len(entryIDs) != 0 {
+		DB.Delete(DigestSignal{}, "entry_id IN ?", entryIDs)
+	}
+	DB.Delete(DigestEntry{}, "digest_id = ?", digest.ID)
+	DB.Delete(&digest)
+}
@@ -0,0 +1,68 @@
+package models_test
+
+import (
+	"errors"
+
+	"github.com/jumpei00/gostocktrade/app/models"
+)
+
+func (suite *ModelsTestSuite) TestSaveWatchlist() {
+	wl := models.Watchlist{Name: "etf", Symbols: []string{" voo", "QQQ", ""}}
+	suite.Nil(wl.SaveWatchlist())
+	other := models.Watchlist{Name: "tech", Symbols: []string{"QQQ", "AAPL"}}
+	suite.Nil(other.SaveWatchlist())
+
+	saved, err := models.GetWatchlist("etf")
+	suite.Nil(err)
+	suite.Equal([]string{"VOO", "QQQ"}, saved.Symbols)
+	suite.Equal([]string{"AAPL", "QQQ", "VOO"}, models.WatchlistSymbols())
+
+	// same name is updated
+	wl = models.Watchlist{Name: "etf", Symbols: []string{"SPY"}}
+	suite.Nil(wl.SaveWatchlist())
+	suite.Len(models.GetWatchlists(), 2)
+	saved, _ = models.GetWatchlist("etf")
+	suite.Equal([]string{"SPY"}, saved.Symbols)
+
+	// no name or no symbols
+	suite.NotNil((&models.Watchlist{Symbols: []string{"VOO"}}).SaveWatchlist())
+	suite.NotNil((&models.Watchlist{Name: "empty", Symbols: []string{" "}}).SaveWatchlist())
+
+	models.DeleteWatchlist("etf")
+	models.DeleteWatchlist("tech")
+	suite.Empty(models.GetWatchlists())
+}
+
+func (suite *ModelsTestSuite) TestScanWatchlists() {
+	wl := models.Watchlist{Name: "etf", Symbols: []string{"VOO", "QQQ"}}
+	wl.SaveWatchlist()
+
+	synced := []string{}
+	sync := func(symbol string, period int) error {
+		synced = append(synced, symbol)
+		if symbol == "QQQ" {
+			return errors.New("stock get error")
+		}
+		return nil
+	}
+
+	digest, err := models.ScanWatchlists("2021-01-04", 500, sync)
+	suite.Nil(err)
+	suite.Equal([]string{"QQQ", "VOO"}, synced)
+	suite.Len(digest.Entries, 2)
+	suite.Equal("stock get error", digest.Entries[0].Error)
+	// VOO is not backtested, but scanned
+	suite.Equal("not backtested", digest.Entries[1].Error)
+	suite.NotZero(digest.Entries[1].Time)
+
+	// the same date is replaced
+	models.ScanWatchlists("2021-01-04", 500, sync)
+	stored, err := models.GetDigest("2021-01-04")
+	suite.Nil(err)
+	suite.Len(stored.Entries, 2)
+
+	models.DeleteDigest("2021-01-04")
+	_, err = models.GetDigest("")
+	suite.NotNil(err)
+	models.DeleteWatchlist("etf")
+}
@@ -0,0 +1,75 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/jumpei00/gostocktrade/app/models"
+	"github.com/sirupsen/logrus"
+)
+
+// defaultDeliveryLimit is number of deliveries when limit is not specified
+const defaultDeliveryLimit = 100
+
+// alertParams is subscription at json, secret is accepted only on create
+type alertParams struct {
+	models.AlertSubscription
+	Secret string `json:"secret"`
+}
+
+// AlertAPIHandler lists, creates, deletes alert subscriptions,
+// when path is "/alerts"
+func AlertAPIHandler(w http.ResponseWriter, req *http.Request) {
+	logrus.Infof("alert request: method -> %s, url -> %s", req.Method, req.URL)
+
+	switch req.Method {
+	case http.MethodGet:
+		writeJSON(w, models.GetAlertSubscriptions(req.URL.Query().Get("symbol")))
+
+	case http.MethodPost:
+		var params alertParams
+		if err := json.NewDecoder(req.Body).Decode(&params); err != nil {
+			logrus.Warnf("alert params error: %v", err)
+			errorAPI(w, fmt.Sprintf("alert params error: %v", err), http.StatusInternalServerError)
+			return
+		}
+		as := params.AlertSubscription
+		as.Secret = params.Secret
+
+		if err := as.CreateAlertSubscription(); err != nil {
+			logrus.Warnf("alert save error: %v", err)
+			errorAPI(w, fmt.Sprintf("alert save error: %v", err), http.StatusBadRequest)
+			return
+		}
+		writeJSON(w, as)
+
+	case http.MethodDelete:
+		id, err := strconv.Atoi(req.URL.Query().Get("id"))
+		if err != nil {
+			errorAPI(w, "bad parameter(id)", http.StatusBadRequest)
+			return
+		}
+
+		models.DeleteAlertSubscription(id)
+		writeJSON(w, models.GetAlertSubscriptions(""))
+
+	default:
+		errorAPI(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// AlertDeliveryAPIHandler returns latest log of alert deliveries, symbol is optional,
+// when path is "/alerts/deliveries"
+func AlertDeliveryAPIHandler(w http.ResponseWriter, req *http.Request) {
+	logrus.Infof("alert delivery request: url -> %s", req.URL)
+
+	limit, err := parseInt(req.URL.Query().Get("limit"), defaultDeliveryLimit)
+	if err != nil || limit <= 0 {
+		errorAPI(w, "bad parameter(limit)", http.StatusBadRequest)
+		return
+	}
+
+	writeJSON(w, models.GetAlertDeliveries(req.URL.Query().Get("symbol"), limit))
+}
@@ -0,0 +1,84 @@
+package server_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http/httptest"
+	"strconv"
+
+	"github.com/jumpei00/gostocktrade/app/models"
+	"github.com/jumpei00/gostocktrade/app/server"
+)
+
+func (suite *ModelsTestSuite) TestAlertAPIHandler() {
+	// create
+	recorder := httptest.NewRecorder()
+	jsonData, _ := json.Marshal(models.AlertSubscription{Symbol: "VOO", Strategy: "ema", Channel: "email", Target: "user@example.com"})
+	req := httptest.NewRequest("POST", "/alerts", bytes.NewReader(jsonData))
+	server.AlertAPIHandler(recorder, req)
+	resp := recorder.Result()
+
+	as := models.AlertSubscription{}
+	json.NewDecoder(resp.Body).Decode(&as)
+	suite.Equal(200, resp.StatusCode)
+	suite.NotZero(as.ID)
+
+	// secret is stored but not returned
+	recorder = httptest.NewRecorder()
+	req = httptest.NewRequest("POST", "/alerts", bytes.NewReader([]byte(
+		`{"symbol": "QQQ", "channel": "webhook", "target": "https://example.com/hook", "secret": "xxx"}`)))
+	server.AlertAPIHandler(recorder, req)
+	body, _ := io.ReadAll(recorder.Result().Body)
+	suite.Equal(200, recorder.Result().StatusCode)
+	suite.NotContains(string(body), "xxx")
+	suite.Equal("xxx", models.GetAlertSubscriptions("QQQ")[0].Secret)
+
+	recorder = httptest.NewRecorder()
+	req = httptest.NewRequest("GET", "/alerts", nil)
+	server.AlertAPIHandler(recorder, req)
+	body, _ = io.ReadAll(recorder.Result().Body)
+	suite.NotContains(string(body), "xxx")
+	models.DeleteAlertSubscription(models.GetAlertSubscriptions("QQQ")[0].ID)
+
+	// wrong channel
+	recorder = httptest.NewRecorder()
+	jsonData, _ = json.Marshal(models.AlertSubscription{Symbol: "VOO", Channel: "sms", Target: "000"})
+	req = httptest.NewRequest("POST", "/alerts", bytes.NewReader(jsonData))
+	server.AlertAPIHandler(recorder, req)
+	suite.Equal(400, recorder.Result().StatusCode)
+
+	// header injection
+	recorder = httptest.NewRecorder()
+	jsonData, _ = json.Marshal(models.AlertSubscription{Symbol: "VOO\r\nBcc: user@example.com", Channel: "email", Target: "user@example.com"})
+	req = httptest.NewRequest("POST", "/alerts", bytes.NewReader(jsonData))
+	server.AlertAPIHandler(recorder, req)
+	suite.Equal(400, recorder.Result().StatusCode)
+
+	// list
+	recorder = httptest.NewRecorder()
+	req = httptest.NewRequest("GET", "/alerts?symbol=VOO", nil)
+	server.AlertAPIHandler(recorder, req)
+	subscriptions := []models.AlertSubscription{}
+	json.NewDecoder(recorder.Result().Body).Decode(&subscriptions)
+	suite.Len(subscriptions, 1)
+
+	// deliveries
+	recorder = httptest.NewRecorder()
+	req = httptest.NewRequest("GET", "/alerts/deliveries?symbol=VOO&limit=10", nil)
+	server.AlertDeliveryAPIHandler(recorder, req)
+	suite.Equal(200, recorder.Result().StatusCode)
+
+	recorder = httptest.NewRecorder()
+	req = httptest.NewRequest("GET", "/alerts/deliveries?limit=0", nil)
+	server.AlertDeliveryAPIHandler(recorder, req)
+	suite.Equal(400, recorder.Result().StatusCode)
+
+	// delete
+	recorder = httptest.NewRecorder()
+	req = httptest.NewRequest("DELETE", "/alerts?id="+strconv.Itoa(as.ID), nil)
+	server.AlertAPIHandler(recorder, req)
+	subscriptions = []models.AlertSubscription{}
+	json.NewDecoder(recorder.Result().Body).Decode(&subscriptions)
+	suite.Empty(subscriptions)
+}
@@ -0,0 +1,282 @@
+package server
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jumpei00/gostocktrade/app/models"
+	"github.com/jumpei00/gostocktrade/app/models/analysis"
+	"github.com/sirupsen/logrus"
+)
+
+// defaultWidth is width of swing points when width is not specified
+const defaultWidth = 5
+
+// default parameters of volatility
+const (
+	defaultAtrPeriod    = 14
+	defaultRankLookback = 252
+)
+
+// defaultWindows is windows of historical volatility when windows are not specified
+var defaultWindows = []int{20}
+
+// dateLayout is layout of date in query, ex) 2021-01-04
+const dateLayout = "2006-01-02"
+
+// parseInt converts integer in query, when it's empty, returns defaultValue
+func parseInt(value string, defaultValue int) (int, error) {
+	if value == "" {
+		return defaultValue, nil
+	}
+	return strconv.Atoi(value)
+}
+
+// parseDate converts date in query to unixtime(ms), when date is empty, returns defaultTime
+func parseDate(date string, defaultTime int64) (int64, error) {
+	if date == "" {
+		return defaultTime, nil
+	}
+	t, err := time.Parse(dateLayout, date)
+	if err != nil {
+		return 0, err
+	}
+	return t.Unix() * 1000, nil
+}
+
+// LevelAPIHandler returns support and resistance levels with strength for drawing horizontal lines,
+// when path is "/levels"
+func LevelAPIHandler(w http.ResponseWriter, req *http.Request) {
+	logrus.Infof("level request: url -> %s", req.URL)
+
+	symbol := req.URL.Query().Get("symbol")
+	if symbol == "" {
+		errorAPI(w, "bad parameter(symbol)", http.StatusBadRequest)
+		return
+	}
+
+	period, err := strconv.Atoi(req.URL.Query().Get("period"))
+	if err != nil || period <= 0 {
+		errorAPI(w, "bad parameter(period)", http.StatusBadRequest)
+		return
+	}
+
+	width, err := parseInt(req.URL.Query().Get("width"), defaultWidth)
+	if err != nil || width < 1 {
+		errorAPI(w, "bad parameter(width)", http.StatusBadRequest)
+		return
+	}
+
+	lframe := models.GetLevels(symbol, period, width)
+	if lframe == nil {
+		errorAPI(w, fmt.Sprintf("no candles, symbol: %v", symbol), http.StatusNotFound)
+		return
+	}
+	writeJSON(w, lframe)
+}
+
+// ProfileAPIHandler returns volume profile of candles between from and to, and VWAP anchored at anchor,
+// dates are such as 2021-01-04, and when from or anchor is empty, it's the first candle, when to is empty, the last,
+// when path is "/profile"
+func ProfileAPIHandler(w http.ResponseWriter, req *http.Request) {
+	logrus.Infof("profile request: url -> %s", req.URL)
+
+	symbol := req.URL.Query().Get("symbol")
+	if symbol == "" {
+		errorAPI(w, "bad parameter(symbol)", http.StatusBadRequest)
+		return
+	}
+
+	from, err := parseDate(req.URL.Query().Get("from"), 0)
+	if err != nil {
+		errorAPI(w, "bad parameter(from)", http.StatusBadRequest)
+		return
+	}
+	to, err := parseDate(req.URL.Query().Get("to"), math.MaxInt64)
+	if err != nil {
+		errorAPI(w, "bad parameter(to)", http.StatusBadRequest)
+		return
+	}
+	anchor, err := parseDate(req.URL.Query().Get("anchor"), from)
+	if err != nil {
+		errorAPI(w, "bad parameter(anchor)", http.StatusBadRequest)
+		return
+	}
+
+	bins, err := parseInt(req.URL.Query().Get("bins"), analysis.ProfileBins)
+	if err != nil || bins < 1 {
+		errorAPI(w, "bad parameter(bins)", http.StatusBadRequest)
+		return
+	}
+
+	pframe, err := models.GetProfileFrame(symbol, from, to, anchor, bins)
+	if err != nil {
+		errorAPI(w, fmt.Sprintf("profile error: %v, symbol: %v", err, symbol), http.StatusNotFound)
+		return
+	}
+	writeJSON(w, pframe)
+}
+
+// VolatilityAPIHandler returns ATR, close-to-close, Parkinson, Garman-Klass volatility and its percentile rank,
+// windows are comma separated such as 10,20,60, when target is given, scale of position size is included,
+// when path is "/volatility"
+func VolatilityAPIHandler(w http.ResponseWriter, req *http.Request) {
+	logrus.Infof("volatility request: url -> %s", req.URL)
+
+	symbol := req.URL.Query().Get("symbol")
+	if symbol == "" {
+		errorAPI(w, "bad parameter(symbol)", http.StatusBadRequest)
+		return
+	}
+
+	period, err := strconv.Atoi(req.URL.Query().Get("period"))
+	if err != nil || period <= 0 {
+		errorAPI(w, "bad parameter(period)", http.StatusBadRequest)
+		return
+	}
+
+	windows := defaultWindows
+	if req.URL.Query().Get("windows") != "" {
+		windows = []int{}
+		for _, value := range strings.Split(req.URL.Query().Get("windows"), ",") {
+			window, err := strconv.Atoi(strings.TrimSpace(value))
+			if err != nil || window < 2 {
+				errorAPI(w, "bad parameter(windows)", http.StatusBadRequest)
+				return
+			}
+			windows = append(windows, window)
+		}
+	}
+
+	atrPeriod, err := parseInt(req.URL.Query().Get("atr"), defaultAtrPeriod)
+	if err != nil || atrPeriod < 1 {
+		errorAPI(w, "bad parameter(atr)", http.StatusBadRequest)
+		return
+	}
+	lookback, err := parseInt(req.URL.Query().Get("lookback"), defaultRankLookback)
+	if err != nil || lookback < 1 {
+		errorAPI(w, "bad parameter(lookback)", http.StatusBadRequest)
+		return
+	}
+
+	target := 0.0
+	if req.URL.Query().Get("target") != "" {
+		target, err = strconv.ParseFloat(req.URL.Query().Get("target"), 64)
+		if err != nil || target <= 0 {
+			errorAPI(w, "bad parameter(target)", http.StatusBadRequest)
+			return
+		}
+	}
+
+	vframe := models.GetVolatilityFrame(symbol, period, windows, atrPeriod, lookback, target)
+	if vframe == nil {
+		errorAPI(w, fmt.Sprintf("no candles, symbol: %v", symbol), http.StatusNotFound)
+		return
+	}
+	writeJSON(w, vframe)
+}
+
+// SeasonalityAPIHandler returns average return and hit rate by month, day of week and trading day of month,
+// when path is "/seasonality"
+func SeasonalityAPIHandler(w http.ResponseWriter, req *http.Request) {
+	logrus.Infof("seasonality request: url -> %s", req.URL)
+
+	symbol := req.URL.Query().Get("symbol")
+	if symbol == "" {
+		errorAPI(w, "bad parameter(symbol)", http.StatusBadRequest)
+		return
+	}
+
+	period, err := strconv.Atoi(req.URL.Query().Get("period"))
+	if err != nil || period <= 0 {
+		errorAPI(w, "bad parameter(period)", http.StatusBadRequest)
+		return
+	}
+
+	sframe := models.GetSeasonalityFrame(symbol, period)
+	if sframe == nil {
+		errorAPI(w, fmt.Sprintf("no candles, symbol: %v", symbol), http.StatusNotFound)
+		return
+	}
+	writeJSON(w, sframe)
+}
+
+// defaultCorrelationWindow is window of rolling correlation and z-score when window is not specified
+const defaultCorrelationWindow = 60
+
+// parseSymbols converts comma separated symbols in query such as VOO,QQQ
+func parseSymbols(value string) []string {
+	symbols := []string{}
+	for _, symbol := range strings.Split(value, ",") {
+		if symbol = strings.TrimSpace(symbol); symbol != "" {
+			symbols = append(symbols, symbol)
+		}
+	}
+	return symbols
+}
+
+// CorrelationAPIHandler returns correlation matrix of daily returns between stored symbols and rolling correlation of each pair,
+// symbols are comma separated such as VOO,QQQ,SPY, when path is "/correlation"
+func CorrelationAPIHandler(w http.ResponseWriter, req *http.Request) {
+	logrus.Infof("correlation request: url -> %s", req.URL)
+
+	symbols := parseSymbols(req.URL.Query().Get("symbols"))
+	if len(symbols) < 2 {
+		errorAPI(w, "bad parameter(symbols)", http.StatusBadRequest)
+		return
+	}
+
+	period, err := strconv.Atoi(req.URL.Query().Get("period"))
+	if err != nil || period <= 0 {
+		errorAPI(w, "bad parameter(period)", http.StatusBadRequest)
+		return
+	}
+
+	window, err := parseInt(req.URL.Query().Get("window"), defaultCorrelationWindow)
+	if err != nil || window < 2 {
+		errorAPI(w, "bad parameter(window)", http.StatusBadRequest)
+		return
+	}
+
+	cframe, err := models.GetCorrelationFrame(symbols, period, window)
+	if err != nil {
+		errorAPI(w, fmt.Sprintf("correlation error: %v, symbols: %v", err, symbols), http.StatusNotFound)
+		return
+	}
+	writeJSON(w, cframe)
+}
+
+// PairAPIHandler returns hedge ratio of the first symbol against the second, and z-score of the spread,
+// symbols are such as KO,PEP, when path is "/pair"
+func PairAPIHandler(w http.ResponseWriter, req *http.Request) {
+	logrus.Infof("pair request: url -> %s", req.URL)
+
+	symbols := parseSymbols(req.URL.Query().Get("symbols"))
+	if len(symbols) != 2 {
+		errorAPI(w, "bad parameter(symbols)", http.StatusBadRequest)
+		return
+	}
+
+	period, err := strconv.Atoi(req.URL.Query().Get("period"))
+	if err != nil || period <= 0 {
+		errorAPI(w, "bad parameter(period)", http.StatusBadRequest)
+		return
+	}
+
+	window, err := parseInt(req.URL.Query().Get("window"), defaultCorrelationWindow)
+	if err != nil || window < 2 {
+		errorAPI(w, "bad parameter(window)", http.StatusBadRequest)
+		return
+	}
+
+	pframe, err := models.GetPairFrame(symbols[0], symbols[1], period, window)
+	if err != nil {
+		errorAPI(w, fmt.Sprintf("pair error: %v, symbols: %v", err, symbols), http.StatusNotFound)
+		return
+	}
+	writeJSON(w, pframe)
+}
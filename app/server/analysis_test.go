@@ -0,0 +1,193 @@
+package server_test
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"time"
+
+	"github.com/jumpei00/gostocktrade/app/models"
+	"github.com/jumpei00/gostocktrade/app/server"
+)
+
+func (suite *ModelsTestSuite) TestLevelAPIHandler() {
+	// normal access
+	recorder := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "/levels?symbol=VOO&period=250&width=5", nil)
+	server.LevelAPIHandler(recorder, req)
+	resp := recorder.Result()
+
+	lframe := models.LevelFrame{}
+	json.NewDecoder(resp.Body).Decode(&lframe)
+
+	suite.Equal(200, resp.StatusCode)
+	suite.Equal("application/json", resp.Header.Get("Content-Type"))
+	suite.Equal("VOO", lframe.Symbol)
+	suite.NotEmpty(lframe.Levels)
+	for _, level := range lframe.Levels {
+		suite.True(level.Strength > 0 && level.Strength <= 1)
+		suite.Equal(level.Price < lframe.Close, level.Kind == "support")
+	}
+
+	// bad parameters
+	for _, url := range []string{"/levels?period=250", "/levels?symbol=VOO", "/levels?symbol=VOO&period=250&width=0"} {
+		recorder = httptest.NewRecorder()
+		req = httptest.NewRequest("GET", url, nil)
+		server.LevelAPIHandler(recorder, req)
+		suite.Equal(400, recorder.Result().StatusCode)
+	}
+}
+
+func (suite *ModelsTestSuite) TestProfileAPIHandler() {
+	candles := *suite.Candles
+	anchor := time.Unix(candles[len(candles)-20].Time/1000, 0).UTC().Format("2006-01-02")
+
+	// normal access
+	recorder := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "/profile?symbol=VOO&bins=20&anchor="+anchor, nil)
+	server.ProfileAPIHandler(recorder, req)
+	resp := recorder.Result()
+
+	pframe := models.ProfileFrame{}
+	json.NewDecoder(resp.Body).Decode(&pframe)
+
+	suite.Equal(200, resp.StatusCode)
+	suite.Equal("application/json", resp.Header.Get("Content-Type"))
+	suite.Equal(candles[0].Time, pframe.From)
+	suite.Len(pframe.Profile.Bins, 20)
+	suite.True(pframe.Profile.VAL <= pframe.Profile.POC && pframe.Profile.POC <= pframe.Profile.VAH)
+	suite.Len(pframe.Vwap, 20)
+
+	// anchor is after the last candle
+	recorder = httptest.NewRecorder()
+	req = httptest.NewRequest("GET", "/profile?symbol=VOO&anchor=2100-01-01", nil)
+	server.ProfileAPIHandler(recorder, req)
+	suite.Equal(404, recorder.Result().StatusCode)
+
+	// bad parameters
+	for _, url := range []string{"/profile?from=2021-01-04", "/profile?symbol=VOO&from=20210104", "/profile?symbol=VOO&bins=0"} {
+		recorder = httptest.NewRecorder()
+		req = httptest.NewRequest("GET", url, nil)
+		server.ProfileAPIHandler(recorder, req)
+		suite.Equal(400, recorder.Result().StatusCode)
+	}
+}
+
+func (suite *ModelsTestSuite) TestVolatilityAPIHandler() {
+	// normal access
+	recorder := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "/volatility?symbol=VOO&period=300&windows=10,20&atr=14&lookback=100&target=0.15", nil)
+	server.VolatilityAPIHandler(recorder, req)
+	resp := recorder.Result()
+
+	vframe := models.VolatilityFrame{}
+	json.NewDecoder(resp.Body).Decode(&vframe)
+
+	suite.Equal(200, resp.StatusCode)
+	suite.Equal("application/json", resp.Header.Get("Content-Type"))
+	suite.Len(vframe.Times, 300)
+	suite.Len(vframe.Atr, 300)
+	suite.Len(vframe.Volatilities, 2)
+	suite.Equal(20, vframe.Volatilities[1].Window)
+	last := len(vframe.Times) - 1
+	suite.True(vframe.Volatilities[0].CloseToClose[last] > 0)
+	suite.True(vframe.Volatilities[0].Rank[last] >= 0 && vframe.Volatilities[0].Rank[last] <= 100)
+	suite.InDelta(0.15/vframe.Volatilities[0].CloseToClose[last], vframe.Volatilities[0].Scale[last], 1e-9)
+
+	// bad parameters
+	for _, url := range []string{"/volatility?period=300", "/volatility?symbol=VOO", "/volatility?symbol=VOO&period=300&windows=1", "/volatility?symbol=VOO&period=300&target=-1"} {
+		recorder = httptest.NewRecorder()
+		req = httptest.NewRequest("GET", url, nil)
+		server.VolatilityAPIHandler(recorder, req)
+		suite.Equal(400, recorder.Result().StatusCode)
+	}
+}
+
+func (suite *ModelsTestSuite) TestSeasonalityAPIHandler() {
+	// normal access
+	recorder := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "/seasonality?symbol=VOO&period=500", nil)
+	server.SeasonalityAPIHandler(recorder, req)
+	resp := recorder.Result()
+
+	sframe := models.SeasonalityFrame{}
+	json.NewDecoder(resp.Body).Decode(&sframe)
+
+	suite.Equal(200, resp.StatusCode)
+	suite.Equal("application/json", resp.Header.Get("Content-Type"))
+	suite.Equal("VOO", sframe.Symbol)
+	suite.NotEmpty(sframe.Months)
+	suite.Len(sframe.Weekdays, 5)
+	suite.NotEmpty(sframe.TradingDays)
+	for _, stat := range sframe.Weekdays {
+		suite.True(stat.HitRate >= 0 && stat.HitRate <= 1)
+		suite.Equal(stat.PValue < 0.05, stat.Significant)
+	}
+	suite.NotNil(sframe.Weak)
+
+	// bad parameters
+	for _, url := range []string{"/seasonality?period=500", "/seasonality?symbol=VOO&period=0"} {
+		recorder = httptest.NewRecorder()
+		req = httptest.NewRequest("GET", url, nil)
+		server.SeasonalityAPIHandler(recorder, req)
+		suite.Equal(400, recorder.Result().StatusCode)
+	}
+}
+
+func (suite *ModelsTestSuite) TestCorrelationAPIHandler() {
+	// candles of other symbol, the price is doubled
+	other := models.Candles{}
+	for _, candle := range *suite.Candles {
+		candle.ID = 0
+		candle.Symbol = "QQQ"
+		candle.Close *= 2
+		other = append(other, candle)
+	}
+	other.CreateCandles()
+
+	// normal access
+	recorder := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "/correlation?symbols=VOO,QQQ&period=300&window=20", nil)
+	server.CorrelationAPIHandler(recorder, req)
+	resp := recorder.Result()
+
+	cframe := models.CorrelationFrame{}
+	json.NewDecoder(resp.Body).Decode(&cframe)
+
+	suite.Equal(200, resp.StatusCode)
+	suite.Equal("application/json", resp.Header.Get("Content-Type"))
+	suite.Equal([]string{"VOO", "QQQ"}, cframe.Symbols)
+	suite.Len(cframe.Times, 299)
+	suite.InDelta(1, cframe.Matrix[0][1], 1e-9)
+	suite.Len(cframe.Rolling, 1)
+	suite.InDelta(1, cframe.Rolling[0].Values[298], 1e-9)
+
+	recorder = httptest.NewRecorder()
+	req = httptest.NewRequest("GET", "/pair?symbols=QQQ,VOO&period=300&window=20", nil)
+	server.PairAPIHandler(recorder, req)
+	resp = recorder.Result()
+
+	pframe := models.PairFrame{}
+	json.NewDecoder(resp.Body).Decode(&pframe)
+
+	suite.Equal(200, resp.StatusCode)
+	suite.InDelta(2, pframe.HedgeRatio, 1e-9)
+	suite.InDelta(0, pframe.Spread[299], 1e-6)
+
+	// symbol is not stored
+	recorder = httptest.NewRecorder()
+	req = httptest.NewRequest("GET", "/pair?symbols=QQQ,SPY&period=300", nil)
+	server.PairAPIHandler(recorder, req)
+	suite.Equal(404, recorder.Result().StatusCode)
+
+	// bad parameters
+	for _, url := range []string{"/correlation?symbols=VOO&period=300", "/correlation?symbols=VOO,QQQ", "/correlation?symbols=VOO,QQQ&period=300&window=1"} {
+		recorder = httptest.NewRecorder()
+		req = httptest.NewRequest("GET", url, nil)
+		server.CorrelationAPIHandler(recorder, req)
+		suite.Equal(400, recorder.Result().StatusCode)
+	}
+	recorder = httptest.NewRecorder()
+	req = httptest.NewRequest("GET", "/pair?symbols=VOO,QQQ,SPY&period=300", nil)
+	server.PairAPIHandler(recorder, req)
+	suite.Equal(400, recorder.Result().StatusCode)
+}
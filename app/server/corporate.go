@@ -0,0 +1,57 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/jumpei00/gostocktrade/app/models"
+	"github.com/sirupsen/logrus"
+)
+
+// CorporateActionAPIHandler returns splits and dividends of symbol, when path is "/actions"
+func CorporateActionAPIHandler(w http.ResponseWriter, req *http.Request) {
+	logrus.Infof("corporate action request: url -> %s", req.URL)
+
+	symbol := req.URL.Query().Get("symbol")
+	if symbol == "" {
+		errorAPI(w, "bad parameter(symbol)", http.StatusBadRequest)
+		return
+	}
+
+	writeJSON(w, models.GetCorporateActions(symbol))
+}
+
+// PriceAPIHandler returns candles of symbol on basis of raw, price(default) or total,
+// when path is "/prices"
+func PriceAPIHandler(w http.ResponseWriter, req *http.Request) {
+	logrus.Infof("price request: url -> %s", req.URL)
+
+	symbol := req.URL.Query().Get("symbol")
+	if symbol == "" {
+		errorAPI(w, "bad parameter(symbol)", http.StatusBadRequest)
+		return
+	}
+
+	period, err := strconv.Atoi(req.URL.Query().Get("period"))
+	if err != nil || period <= 0 {
+		errorAPI(w, "bad parameter(period)", http.StatusBadRequest)
+		return
+	}
+
+	basis := req.URL.Query().Get("basis")
+	if basis == "" {
+		basis = models.PRICERETURN
+	}
+	if basis != models.RAWPRICE && basis != models.PRICERETURN && basis != models.TOTALRETURN {
+		errorAPI(w, "bad parameter(basis)", http.StatusBadRequest)
+		return
+	}
+
+	cframe := models.GetPriceFrame(symbol, period, basis)
+	if len(cframe.Candles) == 0 {
+		errorAPI(w, fmt.Sprintf("no candles, symbol: %v", symbol), http.StatusNotFound)
+		return
+	}
+	writeJSON(w, cframe)
+}
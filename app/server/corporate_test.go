@@ -0,0 +1,40 @@
+package server_test
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+
+	"github.com/jumpei00/gostocktrade/app/models"
+	"github.com/jumpei00/gostocktrade/app/server"
+)
+
+func (suite *ModelsTestSuite) TestPriceAPIHandler() {
+	candles := models.GetCandleFrame("VOO", 10).Candles
+	models.SaveCorporateActions("VOO", []models.CorporateAction{{Time: candles[5].Time, Type: models.SPLIT, Ratio: 4}})
+
+	recorder := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "/actions?symbol=VOO", nil)
+	server.CorporateActionAPIHandler(recorder, req)
+	actions := []models.CorporateAction{}
+	json.NewDecoder(recorder.Result().Body).Decode(&actions)
+	suite.Len(actions, 1)
+
+	recorder = httptest.NewRecorder()
+	req = httptest.NewRequest("GET", "/prices?symbol=VOO&period=10&basis=raw", nil)
+	server.PriceAPIHandler(recorder, req)
+	cframe := models.CandleFrame{}
+	json.NewDecoder(recorder.Result().Body).Decode(&cframe)
+	suite.Equal(candles[0].Close*4, cframe.Candles[0].Close)
+
+	recorder = httptest.NewRecorder()
+	req = httptest.NewRequest("GET", "/prices?symbol=VOO&period=10&basis=dividend", nil)
+	server.PriceAPIHandler(recorder, req)
+	suite.Equal(400, recorder.Result().StatusCode)
+
+	recorder = httptest.NewRecorder()
+	req = httptest.NewRequest("GET", "/prices?symbol=TEST&period=10", nil)
+	server.PriceAPIHandler(recorder, req)
+	suite.Equal(404, recorder.Result().StatusCode)
+
+	models.DeleteCorporateActions("VOO")
+}
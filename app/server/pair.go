@@ -0,0 +1,57 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/jumpei00/gostocktrade/app/models"
+	"github.com/sirupsen/logrus"
+)
+
+// PairBacktestAPIHandler optimizes pair trading of two stored symbols, returns parameters and trades,
+// when path is "/pairs/backtest"
+func PairBacktestAPIHandler(w http.ResponseWriter, req *http.Request) {
+	logrus.Info("pair backtest request")
+
+	var bt models.PairBacktestParam
+	if err := json.NewDecoder(req.Body).Decode(&bt); err != nil {
+		logrus.Warnf("pair backtest params error: %v", err)
+		errorAPI(w, fmt.Sprintf("pair backtest params error: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	result, err := bt.BackTest()
+	if err != nil {
+		logrus.Warnf("pair backtest error: %v", err)
+		errorAPI(w, fmt.Sprintf("pair backtest error: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	if err := result.CreatePairResult(); err != nil {
+		logrus.Warnf("pair backtest error: %v", err)
+		errorAPI(w, fmt.Sprintf("pair backtest error: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	writeJSON(w, result)
+}
+
+// PairTradeAPIHandler returns stored parameters and trades of pair trading, symbols are such as KO,PEP,
+// when path is "/pairs/trades"
+func PairTradeAPIHandler(w http.ResponseWriter, req *http.Request) {
+	logrus.Infof("pair trade request: url -> %s", req.URL)
+
+	symbols := parseSymbols(req.URL.Query().Get("symbols"))
+	if len(symbols) != 2 {
+		errorAPI(w, "bad parameter(symbols)", http.StatusBadRequest)
+		return
+	}
+
+	result, err := models.GetPairResult(symbols[0], symbols[1])
+	if err != nil {
+		errorAPI(w, fmt.Sprintf("pair not backtested, symbols: %v", symbols), http.StatusNotFound)
+		return
+	}
+	writeJSON(w, result)
+}
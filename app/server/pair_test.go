@@ -0,0 +1,70 @@
+package server_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http/httptest"
+
+	"github.com/jumpei00/gostocktrade/app/models"
+	"github.com/jumpei00/gostocktrade/app/models/indicator"
+	"github.com/jumpei00/gostocktrade/app/server"
+)
+
+func (suite *ModelsTestSuite) TestPairBacktestAPIHandler() {
+	other := models.Candles{}
+	for i, candle := range *suite.Candles {
+		candle.ID = 0
+		candle.Symbol = "QQQ"
+		candle.Close = candle.Close*2 + float64(i%20-10)
+		other = append(other, candle)
+	}
+	other.CreateCandles()
+
+	// backtest
+	recorder := httptest.NewRecorder()
+	jsonData, _ := json.Marshal(models.PairBacktestParam{
+		SymbolY: "QQQ",
+		SymbolX: "VOO",
+		Period:  300,
+		Pair: &indicator.PairBacktestParam{
+			PairLookbackLow: 20, PairLookbackHigh: 25, PairEntryLow: 1.5, PairEntryHigh: 2,
+			PairExitLow: 0, PairExitHigh: 0.5, PairStopLow: 3, PairStopHigh: 3.5,
+		},
+	})
+	req := httptest.NewRequest("POST", "/pairs/backtest", bytes.NewReader(jsonData))
+	server.PairBacktestAPIHandler(recorder, req)
+	resp := recorder.Result()
+
+	result := models.PairResult{}
+	json.NewDecoder(resp.Body).Decode(&result)
+	suite.Equal(200, resp.StatusCode)
+	suite.Equal("application/json", resp.Header.Get("Content-Type"))
+	suite.NotEmpty(result.Trades)
+
+	// stored trades
+	recorder = httptest.NewRecorder()
+	req = httptest.NewRequest("GET", "/pairs/trades?symbols=QQQ,VOO", nil)
+	server.PairTradeAPIHandler(recorder, req)
+	resp = recorder.Result()
+
+	stored := models.PairResult{}
+	json.NewDecoder(resp.Body).Decode(&stored)
+	suite.Equal(200, resp.StatusCode)
+	suite.Equal(result.Lookback, stored.Lookback)
+	suite.Len(stored.Trades, len(result.Trades))
+
+	// not backtested
+	recorder = httptest.NewRecorder()
+	req = httptest.NewRequest("GET", "/pairs/trades?symbols=VOO,QQQ", nil)
+	server.PairTradeAPIHandler(recorder, req)
+	suite.Equal(404, recorder.Result().StatusCode)
+
+	// no pair params
+	recorder = httptest.NewRecorder()
+	jsonData, _ = json.Marshal(models.PairBacktestParam{SymbolY: "QQQ", SymbolX: "VOO", Period: 300})
+	req = httptest.NewRequest("POST", "/pairs/backtest", bytes.NewReader(jsonData))
+	server.PairBacktestAPIHandler(recorder, req)
+	suite.Equal(400, recorder.Result().StatusCode)
+
+	models.DeletePairResult("QQQ", "VOO")
+}
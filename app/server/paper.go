@@ -0,0 +1,171 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/jumpei00/gostocktrade/app/models"
+	"github.com/sirupsen/logrus"
+)
+
+// paperOrderParam is body of manual paper order, type is market, limit or stop
+type paperOrderParam struct {
+	Account  string  `json:"account"`
+	Symbol   string  `json:"symbol"`
+	Side     string  `json:"side"`
+	Type     string  `json:"type"`
+	Quantity float64 `json:"quantity"`
+	Price    float64 `json:"price"`
+}
+
+// paperAccount returns account of "account" query, or writes error
+func paperAccount(w http.ResponseWriter, req *http.Request) (*models.PaperAccount, bool) {
+	name := req.URL.Query().Get("account")
+	if name == "" {
+		errorAPI(w, "bad parameter(account)", http.StatusBadRequest)
+		return nil, false
+	}
+
+	pa, err := models.GetPaperAccount(name)
+	if err != nil {
+		errorAPI(w, fmt.Sprintf("no account: %v", name), http.StatusNotFound)
+		return nil, false
+	}
+	return pa, true
+}
+
+// PaperAccountAPIHandler lists, creates, deletes paper accounts, and returns report of account
+// when name is specified, when path is "/paper/accounts"
+func PaperAccountAPIHandler(w http.ResponseWriter, req *http.Request) {
+	logrus.Infof("paper account request: method -> %s, url -> %s", req.Method, req.URL)
+
+	switch req.Method {
+	case http.MethodGet:
+		name := req.URL.Query().Get("name")
+		if name == "" {
+			writeJSON(w, models.GetPaperAccounts())
+			return
+		}
+
+		pa, err := models.GetPaperAccount(name)
+		if err != nil {
+			errorAPI(w, fmt.Sprintf("no account: %v", name), http.StatusNotFound)
+			return
+		}
+		report, _ := models.NewSimulatedBroker(pa).Account()
+		writeJSON(w, report)
+
+	case http.MethodPost:
+		var pa models.PaperAccount
+		if err := json.NewDecoder(req.Body).Decode(&pa); err != nil {
+			logrus.Warnf("paper account params error: %v", err)
+			errorAPI(w, fmt.Sprintf("paper account params error: %v", err), http.StatusInternalServerError)
+			return
+		}
+
+		if err := pa.CreatePaperAccount(); err != nil {
+			logrus.Warnf("paper account save error: %v", err)
+			errorAPI(w, fmt.Sprintf("paper account save error: %v", err), http.StatusBadRequest)
+			return
+		}
+		writeJSON(w, pa)
+
+	case http.MethodDelete:
+		name := req.URL.Query().Get("name")
+		if name == "" {
+			errorAPI(w, "bad parameter(name)", http.StatusBadRequest)
+			return
+		}
+
+		models.DeletePaperAccount(name)
+		writeJSON(w, models.GetPaperAccounts())
+
+	default:
+		errorAPI(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// PaperOrderAPIHandler lists orders of account, submits manual order, and cancels open order,
+// when path is "/paper/orders"
+func PaperOrderAPIHandler(w http.ResponseWriter, req *http.Request) {
+	logrus.Infof("paper order request: method -> %s, url -> %s", req.Method, req.URL)
+
+	switch req.Method {
+	case http.MethodGet:
+		pa, ok := paperAccount(w, req)
+		if !ok {
+			return
+		}
+		orders, _ := models.NewSimulatedBroker(pa).Orders()
+		writeJSON(w, orders)
+
+	case http.MethodPost:
+		var param paperOrderParam
+		if err := json.NewDecoder(req.Body).Decode(&param); err != nil {
+			logrus.Warnf("paper order params error: %v", err)
+			errorAPI(w, fmt.Sprintf("paper order params error: %v", err), http.StatusInternalServerError)
+			return
+		}
+
+		pa, err := models.GetPaperAccount(param.Account)
+		if err != nil {
+			errorAPI(w, fmt.Sprintf("no account: %v", param.Account), http.StatusNotFound)
+			return
+		}
+
+		order := models.PaperOrder{Symbol: param.Symbol, Side: param.Side, Type: param.Type, Quantity: param.Quantity, Price: param.Price}
+		if err := models.NewSimulatedBroker(pa).SubmitOrder(&order); err != nil {
+			logrus.Warnf("paper order error: %v", err)
+			errorAPI(w, fmt.Sprintf("paper order error: %v", err), http.StatusBadRequest)
+			return
+		}
+		writeJSON(w, order)
+
+	case http.MethodDelete:
+		pa, ok := paperAccount(w, req)
+		if !ok {
+			return
+		}
+		id, err := strconv.Atoi(req.URL.Query().Get("id"))
+		if err != nil {
+			errorAPI(w, "bad parameter(id)", http.StatusBadRequest)
+			return
+		}
+
+		broker := models.NewSimulatedBroker(pa)
+		if err := broker.CancelOrder(id); err != nil {
+			errorAPI(w, fmt.Sprintf("paper cancel error: %v", err), http.StatusBadRequest)
+			return
+		}
+		orders, _ := broker.Orders()
+		writeJSON(w, orders)
+
+	default:
+		errorAPI(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// PaperFillAPIHandler returns fills of account, when path is "/paper/fills"
+func PaperFillAPIHandler(w http.ResponseWriter, req *http.Request) {
+	logrus.Infof("paper fill request: url -> %s", req.URL)
+
+	pa, ok := paperAccount(w, req)
+	if !ok {
+		return
+	}
+	fills, _ := models.NewSimulatedBroker(pa).Fills()
+	writeJSON(w, fills)
+}
+
+// PaperEquityAPIHandler returns equity history of account, when path is "/paper/equity"
+func PaperEquityAPIHandler(w http.ResponseWriter, req *http.Request) {
+	logrus.Infof("paper equity request: url -> %s", req.URL)
+
+	pa, ok := paperAccount(w, req)
+	if !ok {
+		return
+	}
+	writeJSON(w, pa.EquityHistory())
+}
@@ -0,0 +1,77 @@
+package server_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http/httptest"
+	"strconv"
+
+	"github.com/jumpei00/gostocktrade/app/models"
+	"github.com/jumpei00/gostocktrade/app/server"
+)
+
+func (suite *ModelsTestSuite) TestPaperAPIHandler() {
+	// create
+	recorder := httptest.NewRecorder()
+	jsonData, _ := json.Marshal(models.PaperAccount{Name: "manual", InitialCash: 10000})
+	req := httptest.NewRequest("POST", "/paper/accounts", bytes.NewReader(jsonData))
+	server.PaperAccountAPIHandler(recorder, req)
+	suite.Equal(200, recorder.Result().StatusCode)
+
+	// no cash
+	recorder = httptest.NewRecorder()
+	jsonData, _ = json.Marshal(models.PaperAccount{Name: "empty"})
+	req = httptest.NewRequest("POST", "/paper/accounts", bytes.NewReader(jsonData))
+	server.PaperAccountAPIHandler(recorder, req)
+	suite.Equal(400, recorder.Result().StatusCode)
+
+	// order
+	recorder = httptest.NewRecorder()
+	jsonData, _ = json.Marshal(map[string]interface{}{"account": "manual", "symbol": "VOO", "side": "BUY", "quantity": 1})
+	req = httptest.NewRequest("POST", "/paper/orders", bytes.NewReader(jsonData))
+	server.PaperOrderAPIHandler(recorder, req)
+	order := models.PaperOrder{}
+	json.NewDecoder(recorder.Result().Body).Decode(&order)
+	suite.Equal(models.ORDEROPEN, order.Status)
+
+	recorder = httptest.NewRecorder()
+	req = httptest.NewRequest("GET", "/paper/orders?account=manual", nil)
+	server.PaperOrderAPIHandler(recorder, req)
+	orders := []models.PaperOrder{}
+	json.NewDecoder(recorder.Result().Body).Decode(&orders)
+	suite.Len(orders, 1)
+
+	// cancel
+	recorder = httptest.NewRecorder()
+	req = httptest.NewRequest("DELETE", "/paper/orders?account=manual&id="+strconv.Itoa(order.ID), nil)
+	server.PaperOrderAPIHandler(recorder, req)
+	orders = []models.PaperOrder{}
+	json.NewDecoder(recorder.Result().Body).Decode(&orders)
+	suite.Equal(models.ORDERCANCELLED, orders[0].Status)
+
+	// report, fills, equity
+	recorder = httptest.NewRecorder()
+	req = httptest.NewRequest("GET", "/paper/accounts?name=manual", nil)
+	server.PaperAccountAPIHandler(recorder, req)
+	report := models.PaperReport{}
+	json.NewDecoder(recorder.Result().Body).Decode(&report)
+	suite.Equal(10000.0, report.Equity)
+
+	recorder = httptest.NewRecorder()
+	req = httptest.NewRequest("GET", "/paper/fills?account=manual", nil)
+	server.PaperFillAPIHandler(recorder, req)
+	suite.Equal(200, recorder.Result().StatusCode)
+
+	recorder = httptest.NewRecorder()
+	req = httptest.NewRequest("GET", "/paper/equity?account=unknown", nil)
+	server.PaperEquityAPIHandler(recorder, req)
+	suite.Equal(404, recorder.Result().StatusCode)
+
+	// delete
+	recorder = httptest.NewRecorder()
+	req = httptest.NewRequest("DELETE", "/paper/accounts?name=manual", nil)
+	server.PaperAccountAPIHandler(recorder, req)
+	accounts := []models.PaperAccount{}
+	json.NewDecoder(recorder.Result().Body).Decode(&accounts)
+	suite.Empty(accounts)
+}
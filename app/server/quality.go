@@ -0,0 +1,28 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/jumpei00/gostocktrade/app/models"
+	"github.com/sirupsen/logrus"
+)
+
+// QualityAPIHandler returns data-quality report of symbol with the issues at the last import,
+// when symbol is empty, reports of all symbols without the issues, when path is "/quality"
+func QualityAPIHandler(w http.ResponseWriter, req *http.Request) {
+	logrus.Infof("quality request: url -> %s", req.URL)
+
+	symbol := req.URL.Query().Get("symbol")
+	if symbol == "" {
+		writeJSON(w, models.GetQualityReports())
+		return
+	}
+
+	report, err := models.GetQualityReport(symbol)
+	if err != nil {
+		errorAPI(w, fmt.Sprintf("no quality report, symbol: %v", symbol), http.StatusNotFound)
+		return
+	}
+	writeJSON(w, report)
+}
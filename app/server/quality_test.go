@@ -0,0 +1,38 @@
+package server_test
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+
+	"github.com/jumpei00/gostocktrade/app/models"
+	"github.com/jumpei00/gostocktrade/app/server"
+)
+
+func (suite *ModelsTestSuite) TestQualityAPIHandler() {
+	candles := models.GetCandleFrame("VOO", 10).Candles
+	candles[5].Volume = 0
+	_, report := models.Candles(candles).Validate(models.QUALITYREPORT)
+	models.SaveQualityReport(report)
+
+	recorder := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "/quality?symbol=VOO", nil)
+	server.QualityAPIHandler(recorder, req)
+	saved := models.QualityReport{}
+	json.NewDecoder(recorder.Result().Body).Decode(&saved)
+	suite.Equal(1, saved.ZeroVolume)
+	suite.Equal(models.ZEROVOLUME, saved.Issues[0].Type)
+
+	recorder = httptest.NewRecorder()
+	req = httptest.NewRequest("GET", "/quality", nil)
+	server.QualityAPIHandler(recorder, req)
+	reports := []models.QualityReport{}
+	json.NewDecoder(recorder.Result().Body).Decode(&reports)
+	suite.NotEmpty(reports)
+
+	recorder = httptest.NewRecorder()
+	req = httptest.NewRequest("GET", "/quality?symbol=TEST", nil)
+	server.QualityAPIHandler(recorder, req)
+	suite.Equal(404, recorder.Result().StatusCode)
+
+	models.DeleteQualityReport("VOO")
+}
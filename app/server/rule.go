@@ -0,0 +1,115 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/jumpei00/gostocktrade/app/models"
+	"github.com/sirupsen/logrus"
+)
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	js, err := json.Marshal(v)
+	if err != nil {
+		logrus.Warnf("json error: %v", err)
+		errorAPI(w, "json error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(js)
+}
+
+// RuleAPIHandler lists, saves, deletes custom strategies written by rule,
+// when path is "/rules"
+func RuleAPIHandler(w http.ResponseWriter, req *http.Request) {
+	logrus.Infof("rule request: method -> %s, url -> %s", req.Method, req.URL)
+
+	switch req.Method {
+	case http.MethodGet:
+		name := req.URL.Query().Get("name")
+		if name == "" {
+			writeJSON(w, models.GetRuleStrategies())
+			return
+		}
+
+		rs, err := models.GetRuleStrategy(name)
+		if err != nil {
+			errorAPI(w, fmt.Sprintf("rule not found, name: %v", name), http.StatusNotFound)
+			return
+		}
+		writeJSON(w, rs)
+
+	case http.MethodPost:
+		var rs models.RuleStrategy
+		if err := json.NewDecoder(req.Body).Decode(&rs); err != nil {
+			logrus.Warnf("rule params error: %v", err)
+			errorAPI(w, fmt.Sprintf("rule params error: %v", err), http.StatusInternalServerError)
+			return
+		}
+
+		if err := rs.SaveRuleStrategy(); err != nil {
+			logrus.Warnf("rule save error: %v", err)
+			errorAPI(w, fmt.Sprintf("rule save error: %v", err), http.StatusBadRequest)
+			return
+		}
+		writeJSON(w, rs)
+
+	case http.MethodDelete:
+		name := req.URL.Query().Get("name")
+		if name == "" {
+			errorAPI(w, "bad parameter(name)", http.StatusBadRequest)
+			return
+		}
+
+		models.DeleteRuleStrategy(name)
+		writeJSON(w, models.GetRuleStrategies())
+
+	default:
+		errorAPI(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// RuleBacktestAPIHandler executes backtest of custom strategy, returns performance and signals,
+// when path is "/rules/backtest"
+func RuleBacktestAPIHandler(w http.ResponseWriter, req *http.Request) {
+	logrus.Info("rule backtest request")
+
+	var bt models.RuleBacktestParam
+	if err := json.NewDecoder(req.Body).Decode(&bt); err != nil {
+		logrus.Warnf("rule backtest params error: %v", err)
+		errorAPI(w, fmt.Sprintf("rule backtest params error: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	result, err := bt.BackTest()
+	if err != nil {
+		logrus.Warnf("rule backtest error: %v", err)
+		errorAPI(w, fmt.Sprintf("rule backtest error: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	if err := result.CreateRuleResult(); err != nil {
+		logrus.Warnf("rule backtest error: %v", err)
+		errorAPI(w, fmt.Sprintf("rule backtest error: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	writeJSON(w, result)
+}
+
+// RuleSignalAPIHandler returns stored signals of custom strategy,
+// when path is "/rules/signals"
+func RuleSignalAPIHandler(w http.ResponseWriter, req *http.Request) {
+	logrus.Infof("rule signal request: url -> %s", req.URL)
+
+	symbol := req.URL.Query().Get("symbol")
+	name := req.URL.Query().Get("name")
+	if symbol == "" || name == "" {
+		errorAPI(w, "bad parameter(symbol, name)", http.StatusBadRequest)
+		return
+	}
+
+	writeJSON(w, models.GetRuleResult(symbol, name))
+}
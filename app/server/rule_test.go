@@ -0,0 +1,75 @@
+package server_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http/httptest"
+
+	"github.com/jumpei00/gostocktrade/app/models"
+	"github.com/jumpei00/gostocktrade/app/server"
+)
+
+func (suite *ModelsTestSuite) TestRuleAPIHandler() {
+	// save
+	recorder := httptest.NewRecorder()
+	jsonData, _ := json.Marshal(models.RuleStrategy{
+		Name: "golden",
+		Rule: "buy when crossover(ema(close,9), ema(close,21)); sell when crossunder(ema(close,9), ema(close,21))",
+	})
+	req := httptest.NewRequest("POST", "/rules", bytes.NewReader(jsonData))
+	server.RuleAPIHandler(recorder, req)
+	resp := recorder.Result()
+
+	suite.Equal(200, resp.StatusCode)
+	suite.Equal("application/json", resp.Header.Get("Content-Type"))
+
+	// list
+	recorder = httptest.NewRecorder()
+	req = httptest.NewRequest("GET", "/rules", nil)
+	server.RuleAPIHandler(recorder, req)
+	resp = recorder.Result()
+
+	strategies := []models.RuleStrategy{}
+	json.NewDecoder(resp.Body).Decode(&strategies)
+	suite.Equal(200, resp.StatusCode)
+	suite.Len(strategies, 1)
+	suite.Equal("golden", strategies[0].Name)
+
+	// wrong rule
+	recorder = httptest.NewRecorder()
+	jsonData, _ = json.Marshal(models.RuleStrategy{Name: "wrong", Rule: "buy when"})
+	req = httptest.NewRequest("POST", "/rules", bytes.NewReader(jsonData))
+	server.RuleAPIHandler(recorder, req)
+	suite.Equal(400, recorder.Result().StatusCode)
+
+	// backtest
+	recorder = httptest.NewRecorder()
+	jsonData, _ = json.Marshal(models.RuleBacktestParam{Name: "golden", Symbol: "VOO", Period: 500})
+	req = httptest.NewRequest("POST", "/rules/backtest", bytes.NewReader(jsonData))
+	server.RuleBacktestAPIHandler(recorder, req)
+	resp = recorder.Result()
+
+	result := models.RuleResult{}
+	json.NewDecoder(resp.Body).Decode(&result)
+	suite.Equal(200, resp.StatusCode)
+	suite.Equal("golden", result.Name)
+	suite.NotEmpty(result.Signals)
+
+	// stored signals
+	recorder = httptest.NewRecorder()
+	req = httptest.NewRequest("GET", "/rules/signals?symbol=VOO&name=golden", nil)
+	server.RuleSignalAPIHandler(recorder, req)
+	resp = recorder.Result()
+
+	stored := models.RuleResult{}
+	json.NewDecoder(resp.Body).Decode(&stored)
+	suite.Equal(200, resp.StatusCode)
+	suite.Len(stored.Signals, len(result.Signals))
+
+	// delete
+	recorder = httptest.NewRecorder()
+	req = httptest.NewRequest("DELETE", "/rules?name=golden", nil)
+	server.RuleAPIHandler(recorder, req)
+	suite.Equal(200, recorder.Result().StatusCode)
+	suite.Empty(models.GetRuleStrategies())
+}
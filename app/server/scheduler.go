@@ -0,0 +1,88 @@
+package server
+
+import (
+	"fmt"
+	"time"
+	// timezone database is embedded, since docker image may not have it
+	_ "time/tzdata"
+
+	"github.com/jumpei00/gostocktrade/app/models"
+	"github.com/sirupsen/logrus"
+)
+
+// Scheduler scans watchlists at the time every weekday, the time should be after market close
+type Scheduler struct {
+	Hour     int
+	Minute   int
+	Location *time.Location
+	// Period is days of candles synced
+	Period int
+	// Sync replaces stored candles of the symbol
+	Sync func(symbol string, period int) error
+	stop chan struct{}
+}
+
+// NewScheduler is constructor of Scheduler, clock is such as 17:00, location is such as America/New_York
+func NewScheduler(clock, location string, period int) (*Scheduler, error) {
+	t, err := time.Parse("15:04", clock)
+	if err != nil {
+		return nil, fmt.Errorf("scan time error: %v", err)
+	}
+	loc, err := time.LoadLocation(location)
+	if err != nil {
+		return nil, fmt.Errorf("scan location error: %v", err)
+	}
+	if period <= 0 {
+		return nil, fmt.Errorf("scan period error: %v", period)
+	}
+
+	return &Scheduler{Hour: t.Hour(), Minute: t.Minute(), Location: loc, Period: period, Sync: syncCandles}, nil
+}
+
+// Next returns the next scan time after now, Saturday and Sunday are skipped
+func (s *Scheduler) Next(now time.Time) time.Time {
+	now = now.In(s.Location)
+	next := time.Date(now.Year(), now.Month(), now.Day(), s.Hour, s.Minute, 0, 0, s.Location)
+	if !(next.After(now)) {
+		next = next.AddDate(0, 0, 1)
+	}
+	for next.Weekday() == time.Saturday || next.Weekday() == time.Sunday {
+		next = next.AddDate(0, 0, 1)
+	}
+	return next
+}
+
+// Scan scans watchlists and stores digest of the day of now
+func (s *Scheduler) Scan(now time.Time) (*models.Digest, error) {
+	return models.ScanWatchlists(now.In(s.Location).Format(dateLayout), s.Period, s.Sync)
+}
+
+// Start runs scan every weekday in background until Stop is called
+func (s *Scheduler) Start() {
+	s.stop = make(chan struct{})
+	go func() {
+		for {
+			next := s.Next(time.Now())
+			logrus.Infof("next watchlist scan: %v", next)
+
+			timer := time.NewTimer(time.Until(next))
+			select {
+			case <-s.stop:
+				timer.Stop()
+				return
+			case now := <-timer.C:
+				if _, err := s.Scan(now); err != nil {
+					logrus.Warnf("watchlist scan error: %v", err)
+				}
+			}
+		}
+	}()
+}
+
+// Stop stops scan started by Start
+func (s *Scheduler) Stop() {
+	if s.stop != nil {
+		close(s.stop)
+		s.stop = nil
+	}
+}
@@ -0,0 +1,41 @@
+package server_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jumpei00/gostocktrade/app/server"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSchedulerNext(t *testing.T) {
+	assert := assert.New(t)
+
+	scheduler, err := server.NewScheduler("17:00", "America/New_York", 365)
+	assert.Nil(err)
+	loc := scheduler.Location
+
+	// before the time on Monday
+	now := time.Date(2021, 1, 4, 10, 0, 0, 0, loc)
+	assert.Equal(time.Date(2021, 1, 4, 17, 0, 0, 0, loc), scheduler.Next(now))
+
+	// after the time on Monday
+	now = time.Date(2021, 1, 4, 17, 0, 0, 0, loc)
+	assert.Equal(time.Date(2021, 1, 5, 17, 0, 0, 0, loc), scheduler.Next(now))
+
+	// after the time on Friday, weekend is skipped
+	now = time.Date(2021, 1, 8, 18, 0, 0, 0, loc)
+	assert.Equal(time.Date(2021, 1, 11, 17, 0, 0, 0, loc), scheduler.Next(now))
+
+	// now in other location
+	now = time.Date(2021, 1, 4, 23, 0, 0, 0, time.UTC)
+	assert.Equal(time.Date(2021, 1, 5, 17, 0, 0, 0, loc), scheduler.Next(now))
+
+	// bad settings
+	_, err = server.NewScheduler("5pm", "America/New_York", 365)
+	assert.NotNil(err)
+	_, err = server.NewScheduler("17:00", "Nowhere/City", 365)
+	assert.NotNil(err)
+	_, err = server.NewScheduler("17:00", "America/New_York", 0)
+	assert.NotNil(err)
+}
@@ -0,0 +1,37 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/jumpei00/gostocktrade/app/models"
+	"github.com/sirupsen/logrus"
+)
+
+// ScreenerAPIHandler evaluates conditions against stored candles of watchlist, returns matching symbols,
+// when path is "/screener"
+func ScreenerAPIHandler(w http.ResponseWriter, req *http.Request) {
+	logrus.Info("screener request")
+
+	if req.Method != http.MethodPost {
+		errorAPI(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var sp models.ScreenParam
+	if err := json.NewDecoder(req.Body).Decode(&sp); err != nil {
+		logrus.Warnf("screener params error: %v", err)
+		errorAPI(w, fmt.Sprintf("screener params error: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	sframe, err := sp.Screen()
+	if err != nil {
+		logrus.Warnf("screener error: %v", err)
+		errorAPI(w, fmt.Sprintf("screener error: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	writeJSON(w, sframe)
+}
@@ -0,0 +1,39 @@
+package server_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http/httptest"
+
+	"github.com/jumpei00/gostocktrade/app/models"
+	"github.com/jumpei00/gostocktrade/app/server"
+)
+
+func (suite *ModelsTestSuite) TestScreenerAPIHandler() {
+	// normal access
+	recorder := httptest.NewRecorder()
+	jsonData, _ := json.Marshal(models.ScreenParam{Symbols: []string{"VOO"}, Sma: &models.SmaCondition{Period: 1}})
+	req := httptest.NewRequest("POST", "/screener", bytes.NewReader(jsonData))
+	server.ScreenerAPIHandler(recorder, req)
+	resp := recorder.Result()
+
+	sframe := models.ScreenFrame{}
+	json.NewDecoder(resp.Body).Decode(&sframe)
+	suite.Equal(200, resp.StatusCode)
+	suite.Equal("application/json", resp.Header.Get("Content-Type"))
+	suite.Empty(sframe.Skipped)
+	// close is never above SMA of 1 day
+	suite.Empty(sframe.Matches)
+
+	// no conditions
+	recorder = httptest.NewRecorder()
+	req = httptest.NewRequest("POST", "/screener", bytes.NewReader([]byte(`{"symbols": ["VOO"]}`)))
+	server.ScreenerAPIHandler(recorder, req)
+	suite.Equal(400, recorder.Result().StatusCode)
+
+	// wrong method
+	recorder = httptest.NewRecorder()
+	req = httptest.NewRequest("GET", "/screener", nil)
+	server.ScreenerAPIHandler(recorder, req)
+	suite.Equal(405, recorder.Result().StatusCode)
+}
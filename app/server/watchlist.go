@@ -0,0 +1,79 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/jumpei00/gostocktrade/app/models"
+	"github.com/sirupsen/logrus"
+)
+
+// WatchlistAPIHandler lists, saves, deletes watchlists,
+// when path is "/watchlists"
+func WatchlistAPIHandler(w http.ResponseWriter, req *http.Request) {
+	logrus.Infof("watchlist request: method -> %s, url -> %s", req.Method, req.URL)
+
+	switch req.Method {
+	case http.MethodGet:
+		name := req.URL.Query().Get("name")
+		if name == "" {
+			writeJSON(w, models.GetWatchlists())
+			return
+		}
+
+		wl, err := models.GetWatchlist(name)
+		if err != nil {
+			errorAPI(w, fmt.Sprintf("watchlist not found, name: %v", name), http.StatusNotFound)
+			return
+		}
+		writeJSON(w, wl)
+
+	case http.MethodPost:
+		var wl models.Watchlist
+		if err := json.NewDecoder(req.Body).Decode(&wl); err != nil {
+			logrus.Warnf("watchlist params error: %v", err)
+			errorAPI(w, fmt.Sprintf("watchlist params error: %v", err), http.StatusInternalServerError)
+			return
+		}
+
+		if err := wl.SaveWatchlist(); err != nil {
+			logrus.Warnf("watchlist save error: %v", err)
+			errorAPI(w, fmt.Sprintf("watchlist save error: %v", err), http.StatusBadRequest)
+			return
+		}
+		writeJSON(w, wl)
+
+	case http.MethodDelete:
+		name := req.URL.Query().Get("name")
+		if name == "" {
+			errorAPI(w, "bad parameter(name)", http.StatusBadRequest)
+			return
+		}
+
+		models.DeleteWatchlist(name)
+		writeJSON(w, models.GetWatchlists())
+
+	default:
+		errorAPI(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// DigestAPIHandler returns digest of signals of date such as 2021-01-04, when date is empty, the latest,
+// when path is "/digest"
+func DigestAPIHandler(w http.ResponseWriter, req *http.Request) {
+	logrus.Infof("digest request: url -> %s", req.URL)
+
+	date := req.URL.Query().Get("date")
+	if _, err := parseDate(date, 0); err != nil {
+		errorAPI(w, "bad parameter(date)", http.StatusBadRequest)
+		return
+	}
+
+	digest, err := models.GetDigest(date)
+	if err != nil {
+		errorAPI(w, fmt.Sprintf("digest not found, date: %v", date), http.StatusNotFound)
+		return
+	}
+	writeJSON(w, digest)
+}
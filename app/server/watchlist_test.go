@@ -0,0 +1,80 @@
+package server_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"time"
+
+	"github.com/jumpei00/gostocktrade/app/models"
+	"github.com/jumpei00/gostocktrade/app/server"
+)
+
+func (suite *ModelsTestSuite) TestWatchlistAPIHandler() {
+	// save
+	recorder := httptest.NewRecorder()
+	jsonData, _ := json.Marshal(models.Watchlist{Name: "etf", Symbols: []string{"voo", "QQQ"}})
+	req := httptest.NewRequest("POST", "/watchlists", bytes.NewReader(jsonData))
+	server.WatchlistAPIHandler(recorder, req)
+	suite.Equal(200, recorder.Result().StatusCode)
+
+	// get
+	recorder = httptest.NewRecorder()
+	req = httptest.NewRequest("GET", "/watchlists?name=etf", nil)
+	server.WatchlistAPIHandler(recorder, req)
+	resp := recorder.Result()
+
+	wl := models.Watchlist{}
+	json.NewDecoder(resp.Body).Decode(&wl)
+	suite.Equal(200, resp.StatusCode)
+	suite.Equal([]string{"VOO", "QQQ"}, wl.Symbols)
+
+	// empty symbols
+	recorder = httptest.NewRecorder()
+	jsonData, _ = json.Marshal(models.Watchlist{Name: "empty"})
+	req = httptest.NewRequest("POST", "/watchlists", bytes.NewReader(jsonData))
+	server.WatchlistAPIHandler(recorder, req)
+	suite.Equal(400, recorder.Result().StatusCode)
+
+	// scan, QQQ can not be synced
+	scheduler, _ := server.NewScheduler("17:00", "America/New_York", 500)
+	scheduler.Sync = func(symbol string, period int) error {
+		if symbol == "QQQ" {
+			return errors.New("stock get error")
+		}
+		return nil
+	}
+	_, err := scheduler.Scan(time.Date(2021, 1, 4, 17, 0, 0, 0, scheduler.Location))
+	suite.Nil(err)
+
+	recorder = httptest.NewRecorder()
+	req = httptest.NewRequest("GET", "/digest", nil)
+	server.DigestAPIHandler(recorder, req)
+	resp = recorder.Result()
+
+	digest := models.Digest{}
+	json.NewDecoder(resp.Body).Decode(&digest)
+	suite.Equal(200, resp.StatusCode)
+	suite.Equal("2021-01-04", digest.Date)
+	suite.Len(digest.Entries, 2)
+	suite.Equal("QQQ", digest.Entries[0].Symbol)
+	suite.Equal("stock get error", digest.Entries[0].Error)
+	suite.Equal("VOO", digest.Entries[1].Symbol)
+
+	// not scanned date
+	recorder = httptest.NewRecorder()
+	req = httptest.NewRequest("GET", "/digest?date=2020-01-01", nil)
+	server.DigestAPIHandler(recorder, req)
+	suite.Equal(404, recorder.Result().StatusCode)
+
+	// delete
+	recorder = httptest.NewRecorder()
+	req = httptest.NewRequest("DELETE", "/watchlists?name=etf", nil)
+	server.WatchlistAPIHandler(recorder, req)
+	watchlists := []models.Watchlist{}
+	json.NewDecoder(recorder.Result().Body).Decode(&watchlists)
+	suite.Empty(watchlists)
+
+	models.DeleteDigest("2021-01-04")
+}
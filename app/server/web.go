@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"strconv"
 
+	"github.com/jumpei00/gostocktrade/app/alert"
 	"github.com/jumpei00/gostocktrade/app/models"
 	"github.com/jumpei00/gostocktrade/config"
 	"github.com/jumpei00/gostocktrade/stock"
@@ -36,7 +37,7 @@ func IndexAPIHandler(w http.ResponseWriter, req *http.Request) {
 	temp.ExecuteTemplate(w, "index.html", nil)
 }
 
-// CandleGetAPIHandler gets stock data, optimized paramerters, signal data, and trade data,
+// CandleGetAPIHandler gets stock data, indicator lines, optimized paramerters, signal data, and trade data,
 // when path is "/candles"
 func CandleGetAPIHandler(w http.ResponseWriter, req *http.Request) {
 	logrus.Infof("candle get request: url -> %s", req.URL)
@@ -59,21 +60,20 @@ func CandleGetAPIHandler(w http.ResponseWriter, req *http.Request) {
 
 	// Downloads stock data
 	if get {
-		adjStock, _ := stock.GetStockData(symbol, period, true)
-		Stock, _ := stock.GetStockData(symbol, period, false)
-		if len(adjStock.Date) == 0 || len(Stock.Date) == 0 {
-			logrus.Warnf("stock get error, symbol: %v", symbol)
-			errorAPI(w, fmt.Sprintf("stock get error, symbol: %v", symbol), http.StatusBadRequest)
+		if err := syncCandles(symbol, period); err != nil {
+			logrus.Warnf("%v", err)
+			errorAPI(w, err.Error(), http.StatusBadRequest)
 			return
 		}
-		// After delete existing data, store stock data in DB
-		models.AllDeleteCandles()
-		models.NewCandlesFromQuote(adjStock, Stock).CreateCandles()
 		dframe.AddCandleFrame(symbol, period)
+		dframe.AddSeriesFrame(symbol, period)
 		dframe.AddOptimizedParamFrame(symbol)
 		if models.SignalTest(symbol, period) {
 			dframe.AddTradeFrame(symbol)
 		}
+		models.PaperTrade(symbol)
+		// retries of webhook should not delay response
+		go models.NotifyAlerts(symbol)
 	}
 
 	ema, _ := strconv.ParseBool(req.URL.Query().Get("ema"))
@@ -81,8 +81,17 @@ func CandleGetAPIHandler(w http.ResponseWriter, req *http.Request) {
 	macd, _ := strconv.ParseBool(req.URL.Query().Get("macd"))
 	rsi, _ := strconv.ParseBool(req.URL.Query().Get("rsi"))
 	willr, _ := strconv.ParseBool(req.URL.Query().Get("willr"))
-
-	dframe.AddSignalFrame(symbol, ema, bb, macd, rsi, willr)
+	ichimoku, _ := strconv.ParseBool(req.URL.Query().Get("ichimoku"))
+	psar, _ := strconv.ParseBool(req.URL.Query().Get("psar"))
+	obv, _ := strconv.ParseBool(req.URL.Query().Get("obv"))
+	mfi, _ := strconv.ParseBool(req.URL.Query().Get("mfi"))
+	donchian, _ := strconv.ParseBool(req.URL.Query().Get("donchian"))
+	pattern, _ := strconv.ParseBool(req.URL.Query().Get("pattern"))
+	divergence, _ := strconv.ParseBool(req.URL.Query().Get("divergence"))
+	stoch, _ := strconv.ParseBool(req.URL.Query().Get("stoch"))
+	level, _ := strconv.ParseBool(req.URL.Query().Get("level"))
+
+	dframe.AddSignalFrame(symbol, ema, bb, macd, rsi, willr, ichimoku, psar, obv, mfi, donchian, pattern, divergence, stoch, level)
 
 	js, err := json.Marshal(dframe)
 	if err != nil {
@@ -95,6 +104,34 @@ func CandleGetAPIHandler(w http.ResponseWriter, req *http.Request) {
 	w.Write(js)
 }
 
+// syncCandles downloads stock data of period days, and replaces stored candles of the symbol
+// validated by data-quality mode of config
+func syncCandles(symbol string, period int) error {
+	adjStock, _ := stock.GetStockData(symbol, period, true)
+	Stock, _ := stock.GetStockData(symbol, period, false)
+	if len(adjStock.Date) == 0 || len(Stock.Date) == 0 {
+		return fmt.Errorf("stock get error, symbol: %v", symbol)
+	}
+	candles, report := models.NewCandlesFromQuote(adjStock, Stock).Validate(config.Config.QualityMode)
+	// After delete existing data of the symbol, store stock data in DB
+	models.DeleteCandles(symbol)
+	candles.CreateCandles()
+	if err := models.SaveQualityReport(report); err != nil {
+		logrus.Warnf("quality report save error, symbol: %v, %v", symbol, err)
+	}
+
+	// candles are usable without corporate actions
+	events, err := stock.GetEvents(symbol, period)
+	if err != nil {
+		logrus.Warnf("events get error, symbol: %v, %v", symbol, err)
+		return nil
+	}
+	if err := models.SaveCorporateActions(symbol, models.NewCorporateActions(events)); err != nil {
+		logrus.Warnf("corporate actions save error, symbol: %v, %v", symbol, err)
+	}
+	return nil
+}
+
 // BacktestAPIHandler executes backtest, returns optimized parameters, trade data,
 // when path is "/backtest"
 func BacktestAPIHandler(w http.ResponseWriter, req *http.Request) {
@@ -108,7 +145,16 @@ func BacktestAPIHandler(w http.ResponseWriter, req *http.Request) {
 		return
 	}
 
-	if err := bt.BackTest().CreateBacktestResult(); err != nil {
+	if err := bt.Validate(); err != nil {
+		errorAPI(w, fmt.Sprintf("bad parameter(%v)", err), http.StatusBadRequest)
+		return
+	}
+
+	op, err := bt.BackTest()
+	if err == nil {
+		err = op.CreateBacktestResult()
+	}
+	if err != nil {
 		logrus.Warnf("backtest error: %v", err)
 		errorAPI(w, fmt.Sprintf("backtest error: %v", err), http.StatusInternalServerError)
 		return
@@ -137,5 +183,47 @@ func Run() {
 	http.HandleFunc("/", IndexAPIHandler)
 	http.HandleFunc("/candles", CandleGetAPIHandler)
 	http.HandleFunc("/backtest", BacktestAPIHandler)
+	http.HandleFunc("/rules", RuleAPIHandler)
+	http.HandleFunc("/rules/backtest", RuleBacktestAPIHandler)
+	http.HandleFunc("/rules/signals", RuleSignalAPIHandler)
+	http.HandleFunc("/levels", LevelAPIHandler)
+	http.HandleFunc("/profile", ProfileAPIHandler)
+	http.HandleFunc("/volatility", VolatilityAPIHandler)
+	http.HandleFunc("/seasonality", SeasonalityAPIHandler)
+	http.HandleFunc("/correlation", CorrelationAPIHandler)
+	http.HandleFunc("/pair", PairAPIHandler)
+	http.HandleFunc("/pairs/backtest", PairBacktestAPIHandler)
+	http.HandleFunc("/pairs/trades", PairTradeAPIHandler)
+	http.HandleFunc("/screener", ScreenerAPIHandler)
+	http.HandleFunc("/watchlists", WatchlistAPIHandler)
+	http.HandleFunc("/digest", DigestAPIHandler)
+	http.HandleFunc("/alerts", AlertAPIHandler)
+	http.HandleFunc("/alerts/deliveries", AlertDeliveryAPIHandler)
+	http.HandleFunc("/paper/accounts", PaperAccountAPIHandler)
+	http.HandleFunc("/paper/orders", PaperOrderAPIHandler)
+	http.HandleFunc("/paper/fills", PaperFillAPIHandler)
+	http.HandleFunc("/paper/equity", PaperEquityAPIHandler)
+	http.HandleFunc("/actions", CorporateActionAPIHandler)
+	http.HandleFunc("/prices", PriceAPIHandler)
+	http.HandleFunc("/quality", QualityAPIHandler)
+
+	if config.Config.SMTPHost != "" {
+		models.AlertMailer = &alert.Mailer{
+			Host:     config.Config.SMTPHost,
+			Port:     config.Config.SMTPPort,
+			Username: config.Config.SMTPUsername,
+			Password: config.Config.SMTPPassword,
+			From:     config.Config.SMTPFrom,
+		}
+	}
+
+	if config.Config.ScanTime != "" {
+		scheduler, err := NewScheduler(config.Config.ScanTime, config.Config.ScanLocation, config.Config.ScanPeriod)
+		if err != nil {
+			logrus.Warnf("scheduler error: %v", err)
+		} else {
+			scheduler.Start()
+		}
+	}
 	logrus.Fatalln(http.ListenAndServe(fmt.Sprintf(":%d", config.Config.Port), nil))
 }
@@ -59,6 +59,64 @@ var backTestParam = models.BackTestParam{
 		WillrSellThreadLow:  -25,
 		WillrSellThreadHigh: -10,
 	},
+	Ichimoku: &indicator.IchimokuBacktestParam{
+		IchimokuTenkanLow:  7,
+		IchimokuTenkanHigh: 11,
+		IchimokuKijunLow:   22,
+		IchimokuKijunHigh:  30,
+		IchimokuSenkouLow:  44,
+		IchimokuSenkouHigh: 56,
+	},
+	Psar: &indicator.PsarBacktestParam{
+		PsarStepLow:  0.01,
+		PsarStepHigh: 0.05,
+		PsarMaxLow:   0.1,
+		PsarMaxHigh:  0.3,
+	},
+	Obv: &indicator.ObvBacktestParam{
+		ObvPeriodLow:  15,
+		ObvPeriodHigh: 25,
+	},
+	Mfi: &indicator.MfiBacktestParam{
+		MfiPeriodLow:      13,
+		MfiPeriodHigh:     15,
+		MfiBuyThreadLow:   19,
+		MfiBuyThreadHigh:  21,
+		MfiSellThreadLow:  79,
+		MfiSellThreadHigh: 81,
+	},
+	Donchian: &indicator.DonchianBacktestParam{
+		DonchianEntryLow:  19,
+		DonchianEntryHigh: 21,
+		DonchianExitLow:   9,
+		DonchianExitHigh:  11,
+		DonchianAtrLow:    14,
+		DonchianAtrHigh:   15,
+	},
+	Pattern: &indicator.PatternBacktestParam{
+		PatternTrendLow:  3,
+		PatternTrendHigh: 7,
+	},
+	Divergence: &indicator.DivergenceBacktestParam{
+		DivergenceWidthLow:  3,
+		DivergenceWidthHigh: 7,
+	},
+	Stoch: &indicator.StochBacktestParam{
+		StochKLow:           12,
+		StochKHigh:          14,
+		StochDLow:           3,
+		StochDHigh:          3,
+		StochSlowingLow:     3,
+		StochSlowingHigh:    3,
+		StochBuyThreadLow:   20,
+		StochBuyThreadHigh:  25,
+		StochSellThreadLow:  75,
+		StochSellThreadHigh: 80,
+	},
+	Level: &indicator.LevelBacktestParam{
+		LevelLookbackLow:  40,
+		LevelLookbackHigh: 60,
+	},
 }
 
 type ModelsTestSuite struct {
@@ -81,6 +139,34 @@ func (suite *ModelsTestSuite) SetupSuite() {
 		&indicator.MacdSignal{},
 		&indicator.RsiSignal{},
 		&indicator.WillrSignal{},
+		&indicator.IchimokuSignal{},
+		&indicator.PsarSignal{},
+		&indicator.ObvSignal{},
+		&indicator.MfiSignal{},
+		&indicator.DonchianSignal{},
+		&indicator.PatternSignal{},
+		&indicator.DivergenceSignal{},
+		&indicator.StochSignal{},
+		&indicator.LevelSignal{},
+		&models.Confirmation{},
+		&models.RuleStrategy{},
+		&indicator.RuleSignal{},
+		&models.PairResult{},
+		&indicator.PairTrade{},
+		&models.Watchlist{},
+		&models.Digest{},
+		&models.DigestEntry{},
+		&models.DigestSignal{},
+		&models.AlertSubscription{},
+		&models.AlertDelivery{},
+		&models.PaperAccount{},
+		&models.PaperOrder{},
+		&models.PaperFill{},
+		&models.PaperPosition{},
+		&models.EntryOrder{},
+		&models.CorporateAction{},
+		&models.QualityReport{},
+		&models.QualityIssue{},
 	)
 
 	adjStock, _ := stock.GetStockData("VOO", 500, true)
@@ -90,7 +176,8 @@ func (suite *ModelsTestSuite) SetupSuite() {
 
 func (suite *ModelsTestSuite) SetupTest() {
 	suite.Candles.CreateCandles()
-	backTestParam.BackTest().CreateBacktestResult()
+	op, _ := backTestParam.BackTest()
+	op.CreateBacktestResult()
 }
 
 func (suite *ModelsTestSuite) TearDownTest() {
@@ -117,13 +204,14 @@ func (suite *ModelsTestSuite) TestCandleGetAPIHadler() {
 	suite.Equal("application/json", resp.Header.Get("Content-Type"))
 	suite.Equal("VOO", dframe.CandleFrame.Symbol)
 	suite.NotEmpty(dframe.CandleFrame.Candles)
+	suite.Len(dframe.SeriesFrame.Ichimoku.Tenkan, len(dframe.CandleFrame.Candles))
 	suite.NotEmpty(dframe.OptimizedParamFrame.Param)
 	suite.Nil(dframe.SignalFrame)
 	suite.NotEmpty(dframe.TradeFrame.Trade)
 
 	// signal access
 	recorder = httptest.NewRecorder()
-	req = httptest.NewRequest("GET", "/candles?symbol=VOO&ema=true&bb=true&macd=true&rsi=true&willr=true", nil)
+	req = httptest.NewRequest("GET", "/candles?symbol=VOO&ema=true&bb=true&macd=true&rsi=true&willr=true&ichimoku=true&psar=true&obv=true&mfi=true&donchian=true&pattern=true&divergence=true&stoch=true&level=true", nil)
 	server.CandleGetAPIHandler(recorder, req)
 	resp = recorder.Result()
 
@@ -140,7 +228,16 @@ func (suite *ModelsTestSuite) TestCandleGetAPIHadler() {
 	suite.NotEmpty(dframe.SignalFrame.Signals.MacdSignals)
 	suite.NotEmpty(dframe.SignalFrame.Signals.RsiSignals)
 	suite.NotEmpty(dframe.SignalFrame.Signals.WillrSignals)
+	suite.NotEmpty(dframe.SignalFrame.Signals.IchimokuSignals)
+	suite.NotEmpty(dframe.SignalFrame.Signals.PsarSignals)
+	suite.NotEmpty(dframe.SignalFrame.Signals.ObvSignals)
+	suite.NotEmpty(dframe.SignalFrame.Signals.MfiSignals)
+	suite.NotEmpty(dframe.SignalFrame.Signals.DonchianSignals)
+	suite.NotEmpty(dframe.SignalFrame.Signals.PatternSignals)
+	suite.NotEmpty(dframe.SignalFrame.Signals.DivergenceSignals)
+	suite.NotEmpty(dframe.SignalFrame.Signals.StochSignals)
 	suite.Nil(dframe.TradeFrame)
+	suite.Nil(dframe.SeriesFrame)
 
 	// when no backtest data, example GOOGL
 	recorder = httptest.NewRecorder()
@@ -211,6 +308,28 @@ func (suite *ModelsTestSuite) TestBacktestAPIHandler() {
 	suite.NotEmpty(dframe.TradeFrame.Trade)
 }
 
+func (suite *ModelsTestSuite) TestBacktestAPIHandlerBaseline() {
+	// payload of ema, bb, macd, rsi and willr only, other strategies are not optimized
+	baseline := models.BackTestParam{
+		Symbol: "VOO", Period: 500,
+		Ema: backTestParam.Ema, BB: backTestParam.BB, Macd: backTestParam.Macd, Rsi: backTestParam.Rsi, Willr: backTestParam.Willr,
+	}
+	recorder := httptest.NewRecorder()
+	jsonData, _ := json.Marshal(baseline)
+	req := httptest.NewRequest("POST", "/backtest", bytes.NewReader(jsonData))
+	server.BacktestAPIHandler(recorder, req)
+	resp := recorder.Result()
+
+	dframe := models.DataFrame{}
+	json.NewDecoder(resp.Body).Decode(&dframe)
+
+	suite.Equal(200, resp.StatusCode)
+	suite.NotEmpty(dframe.OptimizedParamFrame.Param)
+	suite.NotZero(dframe.OptimizedParamFrame.Param.EmaShort)
+	suite.Zero(dframe.OptimizedParamFrame.Param.IchimokuPerformance)
+	suite.Zero(dframe.OptimizedParamFrame.Param.LevelLookback)
+}
+
 func TestModels(t *testing.T) {
 	suite.Run(t, new(ModelsTestSuite))
 }
@@ -14,6 +14,18 @@ type ConfList struct {
 	DBname   string
 	Port     int
 	IP       string
+	// ScanTime is time of daily watchlist scan such as 17:00, empty means no scan
+	ScanTime     string
+	ScanLocation string
+	ScanPeriod   int
+	// SMTP is used for alerts by email, empty host means no email
+	SMTPHost     string
+	SMTPPort     int
+	SMTPUsername string
+	SMTPPassword string
+	SMTPFrom     string
+	// QualityMode is report, repair or drop of bad candles at import
+	QualityMode string
 }
 
 // InitConfig initializes config settings
@@ -28,5 +40,17 @@ func InitConfig() {
 		DBname:   conf.Section("db").Key("name").String(),
 		Port:     conf.Section("web").Key("port").MustInt(),
 		IP:       conf.Section("web").Key("ip").String(),
+
+		ScanTime:     conf.Section("scan").Key("time").String(),
+		ScanLocation: conf.Section("scan").Key("location").MustString("America/New_York"),
+		ScanPeriod:   conf.Section("scan").Key("period").MustInt(365),
+
+		SMTPHost:     conf.Section("smtp").Key("host").String(),
+		SMTPPort:     conf.Section("smtp").Key("port").MustInt(25),
+		SMTPUsername: conf.Section("smtp").Key("username").String(),
+		SMTPPassword: conf.Section("smtp").Key("password").String(),
+		SMTPFrom:     conf.Section("smtp").Key("from").String(),
+
+		QualityMode: conf.Section("quality").Key("mode").MustString("report"),
 	}
 }
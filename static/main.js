@@ -1,4 +1,4 @@
-import { viewRealTime, viewChart, viewBacktestResults, viewTrade, viewSignal, removeSignal } from "./view.js"
+import { viewRealTime, viewChart, viewIchimoku, viewPsar, viewVolumeIndicators, viewPatterns, viewDivergences, viewAdx, viewLevels, viewProfile, viewBacktestResults, viewTrade, viewDigest, viewSignal, removeSignal } from "./view.js"
 import { candleGetRequest, backtestRequest, signalRequest, mappingParams } from "./request.js"
 
 const candle = document.querySelector("#candle");
@@ -29,8 +29,23 @@ function candlesGet() {
         const trade_tag = backtest.querySelector("#trade");
 
         viewChart(symbol, json["candles"]);
+        viewIchimoku(symbol, json["candles"], json["ichimoku"]);
+        viewPsar(symbol, json["candles"], json["psar"]);
+        viewVolumeIndicators(symbol, json["candles"], json["obv"], json["mfi"]);
+        viewPatterns(symbol, json["patterns"]);
+        viewDivergences(symbol, json["divergences"]);
+        viewAdx(symbol, json["candles"], json["adx"]);
         viewBacktestResults(result_tag, json["optimized_params"], signalButtonAction);
         viewTrade(trade_tag, json["trade"]);
+
+        return candleGetRequest("/levels", new URLSearchParams({ symbol: symbol, period: period }));
+    }).then(function (json) {
+        viewLevels(json["levels"]);
+
+        const anchor = candle.querySelector("#anchor").value;
+        return candleGetRequest("/profile", new URLSearchParams({ symbol: symbol, anchor: anchor }));
+    }).then(function (json) {
+        viewProfile(symbol, json);
     }).catch(function (e) {
         alert(e);
     })
@@ -98,9 +113,20 @@ function signalGet(indicator) {
     })
 }
 
+// digestGet gets signals of watchlist symbols at the latest scan
+function digestGet() {
+    candleGetRequest("/digest", new URLSearchParams()).then(function (json) {
+        viewDigest(document.querySelector("#digest"), json);
+    }).catch(function () {
+        // not scanned yet
+        viewDigest(document.querySelector("#digest"), undefined);
+    })
+}
+
 window.addEventListener("load", () => {
     viewRealTime();
     candlesGet();
     getButtonAction();
     testButtonAction();
+    digestGet();
 }, false)
\ No newline at end of file
@@ -23,7 +23,48 @@ const backtest_params = {
         period_low: "", period_high: "",
         buy_low: "", buy_high: "",
         sell_low: "", sell_high: "",
-    }
+    },
+    ichimoku: {
+        tenkan_low: "", tenkan_high: "",
+        kijun_low: "", kijun_high: "",
+        senkou_low: "", senkou_high: "",
+    },
+    psar: {
+        step_low: "", step_high: "",
+        max_low: "", max_high: "",
+    },
+    obv: {
+        period_low: "", period_high: "",
+    },
+    mfi: {
+        period_low: "", period_high: "",
+        buy_low: "", buy_high: "",
+        sell_low: "", sell_high: "",
+    },
+    donchian: {
+        entry_low: "", entry_high: "",
+        exit_low: "", exit_high: "",
+        atr_low: "", atr_high: "",
+    },
+    pattern: {
+        trend_low: "", trend_high: "",
+    },
+    pattern_filter: false,
+    divergence: {
+        width_low: "", width_high: "",
+    },
+    stoch: {
+        k_low: "", k_high: "",
+        d_low: "", d_high: "",
+        slowing_low: "", slowing_high: "",
+        buy_low: "", buy_high: "",
+        sell_low: "", sell_high: "",
+    },
+    level: {
+        lookback_low: "", lookback_high: "",
+    },
+    adx: null,
+    confirmations: []
 }
 
 // settings params sending server
@@ -89,6 +130,132 @@ export function mappingParams(params) {
         return [backtest_params, false, message]
     }
 
+    backtest_params.ichimoku.tenkan_low = +params.querySelector("#ichimoku_tenkan_low").value;
+    backtest_params.ichimoku.tenkan_high = +params.querySelector("#ichimoku_tenkan_high").value;
+    backtest_params.ichimoku.kijun_low = +params.querySelector("#ichimoku_kijun_low").value;
+    backtest_params.ichimoku.kijun_high = +params.querySelector("#ichimoku_kijun_high").value;
+    backtest_params.ichimoku.senkou_low = +params.querySelector("#ichimoku_senkou_low").value;
+    backtest_params.ichimoku.senkou_high = +params.querySelector("#ichimoku_senkou_high").value;
+    if (backtest_params.ichimoku.tenkan_low > backtest_params.ichimoku.tenkan_high ||
+        backtest_params.ichimoku.kijun_low > backtest_params.ichimoku.kijun_high ||
+        backtest_params.ichimoku.senkou_low > backtest_params.ichimoku.senkou_high) {
+        message = "wrong ichimoku parameters, please check magnitude relation(low >= high?)";
+        return [backtest_params, false, message]
+    }
+
+    backtest_params.psar.step_low = +params.querySelector("#psar_step_low").value;
+    backtest_params.psar.step_high = +params.querySelector("#psar_step_high").value;
+    backtest_params.psar.max_low = +params.querySelector("#psar_max_low").value;
+    backtest_params.psar.max_high = +params.querySelector("#psar_max_high").value;
+    if (backtest_params.psar.step_low > backtest_params.psar.step_high ||
+        backtest_params.psar.max_low > backtest_params.psar.max_high) {
+        message = "wrong psar parameters, please check magnitude relation(low >= high?)";
+        return [backtest_params, false, message]
+    }
+
+    backtest_params.obv.period_low = +params.querySelector("#obv_period_low").value;
+    backtest_params.obv.period_high = +params.querySelector("#obv_period_high").value;
+    if (backtest_params.obv.period_low > backtest_params.obv.period_high) {
+        message = "wrong obv parameters, please check magnitude relation(low >= high?)";
+        return [backtest_params, false, message]
+    }
+
+    backtest_params.mfi.period_low = +params.querySelector("#mfi_period_low").value;
+    backtest_params.mfi.period_high = +params.querySelector("#mfi_period_high").value;
+    backtest_params.mfi.buy_low = +params.querySelector("#mfi_buy_low").value;
+    backtest_params.mfi.buy_high = +params.querySelector("#mfi_buy_high").value;
+    backtest_params.mfi.sell_low = +params.querySelector("#mfi_sell_low").value;
+    backtest_params.mfi.sell_high = +params.querySelector("#mfi_sell_high").value;
+    if (backtest_params.mfi.period_low > backtest_params.mfi.period_high ||
+        backtest_params.mfi.buy_low > backtest_params.mfi.buy_high ||
+        backtest_params.mfi.sell_low > backtest_params.mfi.sell_high) {
+        message = "wrong mfi parameters, please check magnitude relation(low >= high?)";
+        return [backtest_params, false, message]
+    }
+
+    backtest_params.donchian.entry_low = +params.querySelector("#donchian_entry_low").value;
+    backtest_params.donchian.entry_high = +params.querySelector("#donchian_entry_high").value;
+    backtest_params.donchian.exit_low = +params.querySelector("#donchian_exit_low").value;
+    backtest_params.donchian.exit_high = +params.querySelector("#donchian_exit_high").value;
+    backtest_params.donchian.atr_low = +params.querySelector("#donchian_atr_low").value;
+    backtest_params.donchian.atr_high = +params.querySelector("#donchian_atr_high").value;
+    if (backtest_params.donchian.entry_low > backtest_params.donchian.entry_high ||
+        backtest_params.donchian.exit_low > backtest_params.donchian.exit_high ||
+        backtest_params.donchian.atr_low > backtest_params.donchian.atr_high) {
+        message = "wrong donchian parameters, please check magnitude relation(low >= high?)";
+        return [backtest_params, false, message]
+    }
+
+    backtest_params.pattern.trend_low = +params.querySelector("#pattern_trend_low").value;
+    backtest_params.pattern.trend_high = +params.querySelector("#pattern_trend_high").value;
+    if (backtest_params.pattern.trend_low > backtest_params.pattern.trend_high) {
+        message = "wrong pattern parameters, please check magnitude relation(low >= high?)";
+        return [backtest_params, false, message]
+    }
+    backtest_params.pattern_filter = params.querySelector("#pattern_filter").checked;
+
+    backtest_params.divergence.width_low = +params.querySelector("#divergence_width_low").value;
+    backtest_params.divergence.width_high = +params.querySelector("#divergence_width_high").value;
+    if (backtest_params.divergence.width_low > backtest_params.divergence.width_high) {
+        message = "wrong divergence parameters, please check magnitude relation(low >= high?)";
+        return [backtest_params, false, message]
+    }
+
+    backtest_params.stoch.k_low = +params.querySelector("#stoch_k_low").value;
+    backtest_params.stoch.k_high = +params.querySelector("#stoch_k_high").value;
+    backtest_params.stoch.d_low = +params.querySelector("#stoch_d_low").value;
+    backtest_params.stoch.d_high = +params.querySelector("#stoch_d_high").value;
+    backtest_params.stoch.slowing_low = +params.querySelector("#stoch_slowing_low").value;
+    backtest_params.stoch.slowing_high = +params.querySelector("#stoch_slowing_high").value;
+    backtest_params.stoch.buy_low = +params.querySelector("#stoch_buy_low").value;
+    backtest_params.stoch.buy_high = +params.querySelector("#stoch_buy_high").value;
+    backtest_params.stoch.sell_low = +params.querySelector("#stoch_sell_low").value;
+    backtest_params.stoch.sell_high = +params.querySelector("#stoch_sell_high").value;
+    if (backtest_params.stoch.k_low > backtest_params.stoch.k_high ||
+        backtest_params.stoch.d_low > backtest_params.stoch.d_high ||
+        backtest_params.stoch.slowing_low > backtest_params.stoch.slowing_high ||
+        backtest_params.stoch.buy_low > backtest_params.stoch.buy_high ||
+        backtest_params.stoch.sell_low > backtest_params.stoch.sell_high) {
+        message = "wrong stoch parameters, please check magnitude relation(low >= high?)";
+        return [backtest_params, false, message]
+    }
+
+    backtest_params.level.lookback_low = +params.querySelector("#level_lookback_low").value;
+    backtest_params.level.lookback_high = +params.querySelector("#level_lookback_high").value;
+    if (backtest_params.level.lookback_low > backtest_params.level.lookback_high) {
+        message = "wrong level parameters, please check magnitude relation(low >= high?)";
+        return [backtest_params, false, message]
+    }
+
+    backtest_params.adx = null;
+    if (params.querySelector("#adx_filter").checked) {
+        const strategies = params.querySelector("#adx_strategies").value;
+        backtest_params.adx = {
+            period: +params.querySelector("#adx_period").value,
+            thread_low: +params.querySelector("#adx_thread_low").value,
+            thread_high: +params.querySelector("#adx_thread_high").value,
+            di: params.querySelector("#adx_di").checked,
+            strategies: strategies == "" ? [] : strategies.split(",").map(s => s.trim())
+        };
+        if (backtest_params.adx.thread_low > backtest_params.adx.thread_high) {
+            message = "wrong adx parameters, please check magnitude relation(low >= high?)";
+            return [backtest_params, false, message]
+        }
+    }
+
+    backtest_params.confirmations = [];
+    for (let setting of params.querySelector("#confirmations").value.split(",")) {
+        if (setting.trim() == "") {
+            continue
+        }
+        const [strategy, timeframe, indicator] = setting.split(":").map(s => s.trim());
+        if (!["weekly", "monthly"].includes(timeframe) || !["macd", "ema", "rsi"].includes(indicator)) {
+            message = `wrong confirmation, please check the format(strategy:timeframe:indicator): ${setting}`;
+            return [backtest_params, false, message]
+        }
+        backtest_params.confirmations.push({ strategy: strategy, timeframe: timeframe, indicator: indicator });
+    }
+
     return [backtest_params, true, message]
 }
 
@@ -8,8 +8,10 @@ const chart = Highcharts.stockChart("container", {
     },
 
     yAxis: [
-        { height: "60%" },
-        { top: "60%", height: "35%", offset: 0 }
+        { height: "50%" },
+        { top: "50%", height: "18%", offset: 0 },
+        { top: "70%", height: "14%", offset: 0 },
+        { top: "86%", height: "14%", offset: 0, min: 0, max: 100 }
     ],
 
     series: []
@@ -74,6 +76,287 @@ export function viewChart(symbol, json) {
     )
 }
 
+// viewIchimoku views lines of Ichimoku Kinko Hyo, senkou spans are drawn as the cloud,
+// the values are aligned to candles, and 0 means no value
+export function viewIchimoku(symbol, candles, ichimoku) {
+    // no data
+    if (ichimoku == undefined) {
+        return
+    }
+
+    let lines = { tenkan: [], kijun: [], chikou: [] };
+    let cloud = [];
+
+    for (let i = 0; i < candles.length; i++) {
+        for (let name in lines) {
+            if (ichimoku[name][i] != 0) {
+                lines[name].push([candles[i].time, ichimoku[name][i]]);
+            }
+        }
+        if (ichimoku.senkou_a[i] != 0 && ichimoku.senkou_b[i] != 0) {
+            cloud.push([candles[i].time, ichimoku.senkou_a[i], ichimoku.senkou_b[i]]);
+        }
+    }
+
+    for (let name in lines) {
+        chart.addSeries(
+            {
+                type: "line",
+                id: `${symbol} ichimoku ${name}`,
+                name: `Ichimoku ${name}`,
+                data: lines[name],
+                lineWidth: 1
+            }
+        )
+    }
+
+    chart.addSeries(
+        {
+            type: "arearange",
+            id: `${symbol} ichimoku cloud`,
+            name: "Ichimoku cloud",
+            data: cloud,
+            lineWidth: 0,
+            fillOpacity: 0.2
+        }
+    )
+}
+
+// viewPsar views Parabolic SAR as dots, the values are aligned to candles
+export function viewPsar(symbol, candles, psar) {
+    // no data
+    if (psar == undefined) {
+        return
+    }
+
+    let data = [];
+    for (let i = 0; i < candles.length; i++) {
+        if (psar[i] != 0) {
+            data.push([candles[i].time, psar[i]]);
+        }
+    }
+
+    chart.addSeries(
+        {
+            type: "scatter",
+            id: `${symbol} psar`,
+            name: "PSAR",
+            data: data,
+            marker: { radius: 2 }
+        }
+    )
+}
+
+// viewVolumeIndicators views On-Balance Volume with its EMA and Money Flow Index
+// under the volume, the values are aligned to candles
+export function viewVolumeIndicators(symbol, candles, obv, mfi) {
+    if (obv != undefined) {
+        let lines = { obv: [], ema: [] };
+        for (let i = 0; i < candles.length; i++) {
+            for (let name in lines) {
+                if (obv[name][i] != 0) {
+                    lines[name].push([candles[i].time, obv[name][i]]);
+                }
+            }
+        }
+
+        for (let name in lines) {
+            chart.addSeries(
+                {
+                    type: "line",
+                    id: `${symbol} obv ${name}`,
+                    name: `OBV ${name}`,
+                    data: lines[name],
+                    lineWidth: 1,
+                    yAxis: 2
+                }
+            )
+        }
+    }
+
+    if (mfi != undefined) {
+        let data = [];
+        for (let i = 0; i < candles.length; i++) {
+            if (mfi[i] != 0) {
+                data.push([candles[i].time, mfi[i]]);
+            }
+        }
+
+        chart.addSeries(
+            {
+                type: "line",
+                id: `${symbol} mfi`,
+                name: "MFI",
+                data: data,
+                lineWidth: 1,
+                yAxis: 3
+            }
+        )
+    }
+}
+
+// viewPatterns views candlestick patterns as flags on the candles
+export function viewPatterns(symbol, patterns) {
+    // no data
+    if (patterns == undefined) {
+        return
+    }
+
+    let data = [];
+    for (let pattern of patterns) {
+        data.push({
+            x: pattern.time,
+            title: pattern.direction > 0 ? "▲" : pattern.direction < 0 ? "▼" : "◆",
+            text: pattern.name
+        });
+    }
+
+    chart.addSeries(
+        {
+            type: "flags",
+            id: `${symbol} patterns`,
+            onSeries: `${symbol} chart`,
+            name: "Patterns",
+            shape: "circlepin",
+            width: 12,
+            data: data,
+            visible: false
+        }
+    )
+}
+
+// viewDivergences views divergences between price and RSI or MACD as segments from a swing point to the next
+export function viewDivergences(symbol, divergences) {
+    // no data
+    if (divergences == undefined) {
+        return
+    }
+
+    // segments are separated by null
+    let lines = {};
+    for (let divergence of divergences) {
+        let name = `${divergence.source} ${divergence.direction > 0 ? "bullish" : "bearish"}`;
+        if (!(name in lines)) {
+            lines[name] = [];
+        }
+        lines[name].push(
+            { x: divergence.from_time, y: divergence.from_price },
+            { x: divergence.to_time, y: divergence.to_price, kind: divergence.kind },
+            { x: divergence.to_time, y: null }
+        );
+    }
+
+    for (let name in lines) {
+        chart.addSeries(
+            {
+                type: "line",
+                id: `${symbol} divergence ${name}`,
+                name: `Divergence ${name}`,
+                data: lines[name],
+                color: name.endsWith("bullish") ? "green" : "red",
+                dashStyle: "Dash",
+                lineWidth: 1,
+                visible: false
+            }
+        )
+    }
+}
+
+// viewAdx views ADX, +DI and -DI with MFI, the values are aligned to candles
+export function viewAdx(symbol, candles, adx) {
+    // no data
+    if (adx == undefined) {
+        return
+    }
+
+    let lines = { adx: [], plus_di: [], minus_di: [] };
+    for (let i = 0; i < candles.length; i++) {
+        for (let name in lines) {
+            if (adx[name][i] != 0) {
+                lines[name].push([candles[i].time, adx[name][i]]);
+            }
+        }
+    }
+
+    for (let name in lines) {
+        chart.addSeries(
+            {
+                type: "line",
+                id: `${symbol} ${name}`,
+                name: name.toUpperCase(),
+                data: lines[name],
+                lineWidth: 1,
+                yAxis: 3,
+                visible: name == "adx"
+            }
+        )
+    }
+}
+
+// viewLevels views support and resistance levels as horizontal lines on price,
+// the stronger level is drawn the thicker, and lines of the previous symbol are removed
+export function viewLevels(levels) {
+    chart.yAxis[0].removePlotLine("levels");
+
+    // no data
+    if (levels == undefined) {
+        return
+    }
+
+    const dashStyles = { pivot: "Solid", volume: "Dot", floor: "Dash" };
+    for (let level of levels) {
+        chart.yAxis[0].addPlotLine(
+            {
+                id: "levels",
+                value: level.price,
+                color: level.kind == "support" ? "green" : "red",
+                width: Math.max(1, Math.round(level.strength * 3)),
+                dashStyle: dashStyles[level.source],
+                label: { text: `${level.name || level.source} ${level.price}`, align: "right", x: -10 }
+            }
+        )
+    }
+}
+
+// viewProfile views anchored VWAP as a line, and POC, VAH, VAL of volume profile as horizontal lines
+export function viewProfile(symbol, profile) {
+    chart.yAxis[0].removePlotLine("profile");
+
+    // no data
+    if (profile == undefined) {
+        return
+    }
+
+    let vwap = [];
+    for (let point of profile.vwap) {
+        vwap.push([point.time, point.vwap]);
+    }
+
+    chart.addSeries(
+        {
+            type: "line",
+            id: `${symbol} vwap`,
+            name: "Anchored VWAP",
+            data: vwap,
+            color: "purple",
+            lineWidth: 1
+        }
+    )
+
+    for (let name of ["poc", "vah", "val"]) {
+        chart.yAxis[0].addPlotLine(
+            {
+                id: "profile",
+                value: profile.profile[name],
+                color: "orange",
+                width: name == "poc" ? 2 : 1,
+                dashStyle: name == "poc" ? "Solid" : "ShortDash",
+                label: { text: `${name.toUpperCase()} ${profile.profile[name]}`, align: "left" }
+            }
+        )
+    }
+}
+
 // viewBacktestResults views optimized params,
 // when optimized params exists, it's viewed, when no, it's cleared
 export function viewBacktestResults(results_element, results, onchangeFunc) {
@@ -88,6 +371,10 @@ export function viewBacktestResults(results_element, results, onchangeFunc) {
 
     results_element.innerHTML = `
         <p>Symbol: ${results.symbol} Latest Time: ${time.toString()}</p>
+        <p>PatternFilter: ${results.pattern_filter} ADXFilter: ${results.adx_period == 0 ? "none" :
+            `Period: ${results.adx_period} Thread: ${results.adx_thread} DI: ${results.adx_di} Strategies: ${results.adx_strategies || "all"}`}
+            Confirmations: ${results.confirmations == undefined || results.confirmations.length == 0 ? "none" :
+            results.confirmations.map(c => `${c.strategy}(${c.timeframe} ${c.indicator})`).join(", ")}</p>
         <input type="checkbox" id="signal" value="ema">
         [EMA] Performance: ${results.ema_performance} Short: ${results.ema_short} Long: ${results.ema_long}
         <input type="checkbox" id="signal" value="bb">
@@ -98,6 +385,24 @@ export function viewBacktestResults(results_element, results, onchangeFunc) {
         [RSI] Performance: ${results.rsi_performance} Period: ${results.rsi_period} Buy: ${results.rsi_buythread} Sell: ${results.rsi_sellthread}
         <input type="checkbox" id="signal" value="willr">
         [WILLr] Performance: ${results.willr_performance} Period: ${results.willr_period} Buy: ${results.willr_buythread} Sell: ${results.willr_sellthread}
+        <input type="checkbox" id="signal" value="ichimoku">
+        [Ichimoku] Performance: ${results.ichimoku_performance} Tenkan: ${results.ichimoku_tenkan} Kijun: ${results.ichimoku_kijun} Senkou: ${results.ichimoku_senkou}
+        <input type="checkbox" id="signal" value="psar">
+        [PSAR] Performance: ${results.psar_performance} Step: ${results.psar_step} Max: ${results.psar_max}
+        <input type="checkbox" id="signal" value="obv">
+        [OBV] Performance: ${results.obv_performance} Period: ${results.obv_period}
+        <input type="checkbox" id="signal" value="mfi">
+        [MFI] Performance: ${results.mfi_performance} Period: ${results.mfi_period} BuyThread: ${results.mfi_buythread} SellThread: ${results.mfi_sellthread}
+        <input type="checkbox" id="signal" value="donchian">
+        [Donchian] Performance: ${results.donchian_performance} Entry: ${results.donchian_entry} Exit: ${results.donchian_exit} ATR: ${results.donchian_atr}
+        <input type="checkbox" id="signal" value="pattern">
+        [Pattern] Performance: ${results.pattern_performance} Trend: ${results.pattern_trend} Filter: ${results.pattern_filter}
+        <input type="checkbox" id="signal" value="divergence">
+        [Divergence] Performance: ${results.divergence_performance} Width: ${results.divergence_width} Oscillator: ${results.divergence_source}
+        <input type="checkbox" id="signal" value="stoch">
+        [Stoch] Performance: ${results.stoch_performance} Fast: ${results.stoch_fast} %K: ${results.stoch_k} %D: ${results.stoch_d} Slowing: ${results.stoch_slowing} BuyThread: ${results.stoch_buythread} SellThread: ${results.stoch_sellthread}
+        <input type="checkbox" id="signal" value="level">
+        [Level] Performance: ${results.level_performance} Lookback: ${results.level_lookback}
     `
 
     // setting eventListener function for a part of signal
@@ -123,9 +428,38 @@ export function viewTrade(trade_element, results) {
         [MACD] <span style=${styleSet(results.last_macd, results.today_macd)}>${results.last_macd}</span>
         [RSI] <span style=${styleSet(results.last_rsi, results.today_rsi)}>${results.last_rsi}</span>
         [WILLr] <span style=${styleSet(results.last_willr, results.today_willr)}>${results.last_willr}</span>
+        [Ichimoku] <span style=${styleSet(results.last_ichimoku, results.today_ichimoku)}>${results.last_ichimoku}</span>
+        [PSAR] <span style=${styleSet(results.last_psar, results.today_psar)}>${results.last_psar}</span>
+        [OBV] <span style=${styleSet(results.last_obv, results.today_obv)}>${results.last_obv}</span>
+        [MFI] <span style=${styleSet(results.last_mfi, results.today_mfi)}>${results.last_mfi}</span>
+        [Donchian] <span style=${styleSet(results.last_donchian, results.today_donchian)}>${results.last_donchian}</span>
+        [Pattern] <span style=${styleSet(results.last_pattern, results.today_pattern)}>${results.last_pattern}</span>
+        [Divergence] <span style=${styleSet(results.last_divergence, results.today_divergence)}>${results.last_divergence}</span>
+        [Stoch] <span style=${styleSet(results.last_stoch, results.today_stoch)}>${results.last_stoch}</span>
+        [Level] <span style=${styleSet(results.last_level, results.today_level)}>${results.last_level}</span>
     `
 }
 
+// viewDigest views signals of watchlist symbols at the latest scan
+export function viewDigest(digest_element, digest) {
+    digest_element.innerHTML = ""
+
+    // not scanned yet
+    if (digest == undefined) {
+        return
+    }
+
+    let html = `[Digest ${digest.date}]`
+    for (let entry of digest.entries) {
+        let signals = entry.signals.map(signal => `<span style=${styleSet(signal.action, true)}>${signal.strategy}:${signal.action}</span>`)
+        if (entry.error) {
+            signals.push(`(${entry.error})`)
+        }
+        html += `<br>${entry.symbol} ${signals.join(" ")}`
+    }
+    digest_element.innerHTML = html
+}
+
 function styleSet(signal, today_trade) {
     let style = ""
 
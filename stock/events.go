@@ -0,0 +1,106 @@
+package stock
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// chartURL is yahoo chart api including dividends and splits, a variable to be replaced at test
+var chartURL = "https://query1.finance.yahoo.com/v8/finance/chart/%s?period1=%d&period2=%d&interval=1d&events=div%%7Csplit"
+
+// Dividend is cash dividend per share at ex-date
+type Dividend struct {
+	Date   time.Time
+	Amount float64
+}
+
+// Split is stock split at ex-date, numerator:denominator such as 2:1
+type Split struct {
+	Date        time.Time
+	Numerator   float64
+	Denominator float64
+}
+
+// Events is dividends and splits of symbol by ascending date
+type Events struct {
+	Symbol    string
+	Dividends []Dividend
+	Splits    []Split
+}
+
+// chartResponse is a part of response of yahoo chart api
+type chartResponse struct {
+	Chart struct {
+		Result []struct {
+			Events struct {
+				Dividends map[string]struct {
+					Amount float64 `json:"amount"`
+					Date   int64   `json:"date"`
+				} `json:"dividends"`
+				Splits map[string]struct {
+					Date        int64   `json:"date"`
+					Numerator   float64 `json:"numerator"`
+					Denominator float64 `json:"denominator"`
+				} `json:"splits"`
+			} `json:"events"`
+		} `json:"result"`
+		Error *struct {
+			Description string `json:"description"`
+		} `json:"error"`
+	} `json:"chart"`
+}
+
+// GetEvents downloads dividends and splits for symbol during today ~ before dayPeriod.
+func GetEvents(symbol string, dayPeriod int) (*Events, error) {
+	endDay := time.Now()
+	startDay := endDay.AddDate(0, 0, -dayPeriod)
+
+	logrus.Infof("get %s events from yahoo", symbol)
+	req, err := http.NewRequest("GET", fmt.Sprintf(chartURL, symbol, startDay.Unix(), endDay.Unix()), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", "Mozilla/5.0")
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var chart chartResponse
+	if err := json.NewDecoder(resp.Body).Decode(&chart); err != nil {
+		return nil, err
+	}
+	if chart.Chart.Error != nil {
+		return nil, fmt.Errorf("events error: %v", chart.Chart.Error.Description)
+	}
+	if len(chart.Chart.Result) == 0 {
+		return nil, fmt.Errorf("no events, symbol: %v", symbol)
+	}
+
+	events := Events{Symbol: symbol, Dividends: []Dividend{}, Splits: []Split{}}
+	result := chart.Chart.Result[0].Events
+	for _, dividend := range result.Dividends {
+		events.Dividends = append(events.Dividends, Dividend{Date: dayOf(dividend.Date), Amount: dividend.Amount})
+	}
+	for _, split := range result.Splits {
+		events.Splits = append(events.Splits, Split{Date: dayOf(split.Date), Numerator: split.Numerator, Denominator: split.Denominator})
+	}
+	sort.Slice(events.Dividends, func(i, j int) bool { return events.Dividends[i].Date.Before(events.Dividends[j].Date) })
+	sort.Slice(events.Splits, func(i, j int) bool { return events.Splits[i].Date.Before(events.Splits[j].Date) })
+
+	return &events, nil
+}
+
+// dayOf returns date of unixtime at 00:00 UTC, the same as date of quote
+func dayOf(unix int64) time.Time {
+	t := time.Unix(unix, 0).UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
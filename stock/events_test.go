@@ -0,0 +1,62 @@
+package stock
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// chartJSON is a part of yahoo chart response of VOO with two dividends and a split
+const chartJSON = `{"chart": {"result": [{"events": {
+	"dividends": {
+		"1616765400": {"amount": 1.264, "date": 1616765400},
+		"1608906600": {"amount": 1.3847, "date": 1608906600}
+	},
+	"splits": {
+		"1598880600": {"date": 1598880600, "numerator": 4, "denominator": 1, "splitRatio": "4:1"}
+	}
+}}], "error": null}}`
+
+func TestGetEvents(t *testing.T) {
+	var path string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		switch r.URL.Path {
+		case "/VOO":
+			fmt.Fprint(w, chartJSON)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"chart": {"result": null, "error": {"code": "Not Found", "description": "No data found, symbol may be delisted"}}}`)
+		}
+	}))
+	defer ts.Close()
+
+	defaultURL := chartURL
+	chartURL = ts.URL + "/%s?period1=%d&period2=%d"
+	defer func() { chartURL = defaultURL }()
+
+	assert := assert.New(t)
+	events, err := GetEvents("VOO", 365)
+	require.NoError(t, err)
+	assert.Equal("/VOO", path)
+	assert.Equal("VOO", events.Symbol)
+
+	// by ascending date at 00:00 UTC
+	require.Len(t, events.Dividends, 2)
+	assert.Equal(time.Date(2020, 12, 25, 0, 0, 0, 0, time.UTC), events.Dividends[0].Date)
+	assert.Equal(1.3847, events.Dividends[0].Amount)
+	assert.Equal(time.Date(2021, 3, 26, 0, 0, 0, 0, time.UTC), events.Dividends[1].Date)
+
+	require.Len(t, events.Splits, 1)
+	assert.Equal(time.Date(2020, 8, 31, 0, 0, 0, 0, time.UTC), events.Splits[0].Date)
+	assert.Equal(4.0, events.Splits[0].Numerator)
+	assert.Equal(1.0, events.Splits[0].Denominator)
+
+	_, err = GetEvents("TEST", 365)
+	assert.Error(err)
+}